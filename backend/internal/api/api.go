@@ -11,8 +11,22 @@ type Payload struct {
 }
 
 // Response represents an API response format.
+// Errors carry one of the codes in errors.go in Code, the fields that failed validation in Details
+// and, for internal errors, the RequestID that the error was logged with. ErrorCode holds the HTTP
+// status code, or the suspension code for suspended and banned users.
 type Response struct {
-	Payload   Payload  `json:"payload"`
-	Messages  []string `json:"messages"`
-	ErrorCode int      `json:"errorCode"`
-}
\ No newline at end of file
+	Payload   Payload      `json:"payload"`
+	Messages  []string     `json:"messages"`
+	ErrorCode int          `json:"errorCode"`
+	Code      string       `json:"code,omitempty"`
+	Details   []FieldError `json:"details,omitempty"`
+	RequestID string       `json:"requestId,omitempty"`
+}
+
+// PageMeta represents the pagination information of a list response, carried in Payload.Meta.
+// NextCursor is empty when there are no more results.
+type PageMeta struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Total      int64  `json:"total"`
+}
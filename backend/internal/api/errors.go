@@ -0,0 +1,97 @@
+package api
+
+// Error codes returned in Response.Code. They are part of the API contract, so a code is never
+// renamed or reused once it has been released, and clients should branch on it rather than on the
+// human readable message.
+const (
+	CodeInternalError        = "INTERNAL_ERROR"
+	CodeMalformedBody        = "MALFORMED_BODY"
+	CodeValidation           = "VALIDATION_FAILED"
+	CodeRateLimited          = "RATE_LIMITED"
+	CodeUnauthenticated      = "UNAUTHENTICATED"
+	CodePermissionDenied     = "PERMISSION_DENIED"
+	CodeStreamingUnsupported = "STREAMING_UNSUPPORTED"
+	CodeShuttingDown         = "SHUTTING_DOWN"
+	CodeDatabaseUnavailable  = "DATABASE_UNAVAILABLE"
+
+	CodeInvalidToken           = "INVALID_TOKEN"
+	CodeTokenRevoked           = "TOKEN_REVOKED"
+	CodePasswordChangeRequired = "PASSWORD_CHANGE_REQUIRED"
+	CodeInsufficientRole       = "INSUFFICIENT_ROLE"
+	CodeSuspended              = "SUSPENDED"
+	CodeBanned                 = "BANNED"
+	CodeInvalidCredentials     = "INVALID_CREDENTIALS"
+	CodeInvalidRefreshToken    = "INVALID_REFRESH_TOKEN"
+	CodeInvalidPassword        = "INVALID_PASSWORD"
+	CodeSamePassword           = "SAME_PASSWORD"
+	CodeMissingTokenID         = "MISSING_TOKEN_ID"
+
+	CodeInvalidPage     = "INVALID_PAGE"
+	CodeInvalidLimit    = "INVALID_LIMIT"
+	CodeInvalidSort     = "INVALID_SORT"
+	CodeInvalidQuery    = "INVALID_QUERY"
+	CodeInvalidFilter   = "INVALID_FILTER"
+	CodeInvalidDate     = "INVALID_DATE"
+	CodeInvalidMinScore = "INVALID_MIN_SCORE"
+	CodeInvalidType     = "INVALID_TYPE"
+	CodeInvalidUnread   = "INVALID_UNREAD"
+	CodeInvalidPrefix   = "INVALID_PREFIX"
+	CodeInvalidVersion  = "INVALID_VERSION"
+	CodeInvalidAction   = "INVALID_ACTION"
+
+	CodeInvalidUserID         = "INVALID_USER_ID"
+	CodeInvalidUsername       = "INVALID_USERNAME"
+	CodeUserNotFound          = "USER_NOT_FOUND"
+	CodeUsernameTaken         = "USERNAME_TAKEN"
+	CodeCannotChangeOwnRole   = "CANNOT_CHANGE_OWN_ROLE"
+	CodeModeratorNotFound     = "MODERATOR_NOT_FOUND"
+	CodeInvalidSuspensionID   = "INVALID_SUSPENSION_ID"
+	CodeSuspensionNotFound    = "SUSPENSION_NOT_FOUND"
+	CodeCannotSuspendSelf     = "CANNOT_SUSPEND_SELF"
+	CodeInvalidNotificationID = "INVALID_NOTIFICATION_ID"
+	CodeNotificationNotFound  = "NOTIFICATION_NOT_FOUND"
+
+	CodeInvalidTopicID  = "INVALID_TOPIC_ID"
+	CodeTopicNotFound   = "TOPIC_NOT_FOUND"
+	CodeTopicTitleTaken = "TOPIC_TITLE_TAKEN"
+	CodeNotSubscribed   = "NOT_SUBSCRIBED"
+
+	CodeInvalidPostID     = "INVALID_POST_ID"
+	CodePostNotFound      = "POST_NOT_FOUND"
+	CodePostTitleTaken    = "POST_TITLE_TAKEN"
+	CodeRestoreExpired    = "RESTORE_EXPIRED"
+	CodeRevisionNotFound  = "REVISION_NOT_FOUND"
+	CodeDiffTooLarge      = "DIFF_TOO_LARGE"
+	CodeVoteNotFound      = "VOTE_NOT_FOUND"
+	CodeBookmarkNotFound  = "BOOKMARK_NOT_FOUND"
+	CodeInvalidPoll       = "INVALID_POLL"
+	CodeInvalidPollOption = "INVALID_POLL_OPTION"
+	CodePollNotFound      = "POLL_NOT_FOUND"
+	CodePollClosed        = "POLL_CLOSED"
+
+	CodeInvalidCommentID       = "INVALID_COMMENT_ID"
+	CodeInvalidParentCommentID = "INVALID_PARENT_COMMENT_ID"
+	CodeReplyTooDeep           = "REPLY_TOO_DEEP"
+	CodeCommentNotFound        = "COMMENT_NOT_FOUND"
+
+	CodeInvalidAttachmentID = "INVALID_ATTACHMENT_ID"
+	CodeAttachmentNotFound  = "ATTACHMENT_NOT_FOUND"
+	CodeMissingFile         = "MISSING_FILE"
+	CodeFileTooLarge        = "FILE_TOO_LARGE"
+	CodeUnsupportedFileType = "UNSUPPORTED_FILE_TYPE"
+	CodeInvalidImage        = "INVALID_IMAGE"
+
+	CodeInvalidReportID = "INVALID_REPORT_ID"
+	CodeReportNotFound  = "REPORT_NOT_FOUND"
+	CodeReportNotOpen   = "REPORT_NOT_OPEN"
+	CodeTargetNotFound  = "TARGET_NOT_FOUND"
+	CodeAlreadyReported = "ALREADY_REPORTED"
+)
+
+// FieldError describes a request body field that failed validation. Field is the JSON name of the
+// field, with the index for elements of a list, and Rule is the validation rule that failed.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,14 @@
+package attachments
+
+import "errors"
+
+var (
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrPostNotFound       = errors.New("post not found")
+	ErrCommentNotFound    = errors.New("comment not found")
+	ErrPermissionDenied   = errors.New("permission denied")
+	ErrFileTooLarge       = errors.New("file is too large")
+	ErrUnsupportedType    = errors.New("file type is not supported")
+	ErrInvalidImage       = errors.New("image could not be read")
+	ErrMissingFile        = errors.New("file missing")
+)
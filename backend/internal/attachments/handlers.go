@@ -0,0 +1,349 @@
+package attachments
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+)
+
+const (
+	InvalidAttachmentIdMessage = "Invalid attachment id"
+	InvalidPostIdMessage       = "Invalid post id"
+	InvalidCommentIdMessage    = "Invalid comment id"
+	MissingUserIDMessage       = "Missing userID"
+	SuccessfulUploadMessage    = "Successfully uploaded attachment"
+	SuccessfulListMessage      = "Successfully listed attachments"
+	SuccessfulDeleteMessage    = "Successfully deleted attachment"
+)
+
+// multipartMemory is how much of an upload form is kept in memory before the rest is spooled to
+// disk, and multipartOverhead is how much larger than MaxSize the form may be around the file.
+const (
+	multipartMemory   = 1 << 20
+	multipartOverhead = 1 << 20
+)
+
+// handler handles the attachment related HTTP requests.
+// It is responsible for translating HTTP requests into service calls and formatting service
+// responses into HTTP responses.
+type handler struct {
+	service Service
+}
+
+// NewHandler creates a new attachment handler.
+func NewHandler(service Service) *handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// UploadToPost handles POST /api/attachments/posts/{postId} requests.
+// It parses the postId string and reads the file from the `file` field of the multipart form, and
+// passes them to the attachment service to attach the file to the post. It then serializes the
+// result into a JSON HTTP response.
+func (h *handler) UploadToPost(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "postId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidPostIdMessage, http.StatusBadRequest, api.CodeInvalidPostID)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	file, err := readUpload(w, r)
+	if err != nil {
+		writeUploadError(w, r, err)
+		return
+	}
+
+	attachment, err := h.service.UploadToPost(r.Context(), userId, id, file)
+	if err != nil {
+		writeUploadError(w, r, err)
+		return
+	}
+
+	jsonAttachment, err := json.Marshal(attachment)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonAttachment, SuccessfulUploadMessage)
+	helper.Write(w, response)
+}
+
+// UploadToComment handles POST /api/attachments/comments/{commentId} requests.
+// It parses the commentId string and reads the file from the `file` field of the multipart form,
+// and passes them to the attachment service to attach the file to the comment. It then serializes
+// the result into a JSON HTTP response.
+func (h *handler) UploadToComment(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "commentId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidCommentIdMessage, http.StatusBadRequest, api.CodeInvalidCommentID)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	file, err := readUpload(w, r)
+	if err != nil {
+		writeUploadError(w, r, err)
+		return
+	}
+
+	attachment, err := h.service.UploadToComment(r.Context(), userId, id, file)
+	if err != nil {
+		writeUploadError(w, r, err)
+		return
+	}
+
+	jsonAttachment, err := json.Marshal(attachment)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonAttachment, SuccessfulUploadMessage)
+	helper.Write(w, response)
+}
+
+// ListByPost handles GET /api/attachments/posts/{postId} requests.
+// It parses the postId string, and passes it to the attachment service to return the attachments
+// of the post, which then serializes the result into a JSON HTTP response.
+func (h *handler) ListByPost(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "postId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidPostIdMessage, http.StatusBadRequest, api.CodeInvalidPostID)
+		return
+	}
+
+	attachments, err := h.service.ListByPost(r.Context(), id)
+	if err != nil {
+		if err == ErrPostNotFound {
+			helper.WriteError(w, ErrPostNotFound.Error(), http.StatusNotFound, api.CodePostNotFound)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonAttachments, err := json.Marshal(attachments)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonAttachments, SuccessfulListMessage)
+	helper.Write(w, response)
+}
+
+// ListByComment handles GET /api/attachments/comments/{commentId} requests.
+// It parses the commentId string, and passes it to the attachment service to return the
+// attachments of the comment, which then serializes the result into a JSON HTTP response.
+func (h *handler) ListByComment(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "commentId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidCommentIdMessage, http.StatusBadRequest, api.CodeInvalidCommentID)
+		return
+	}
+
+	attachments, err := h.service.ListByComment(r.Context(), id)
+	if err != nil {
+		if err == ErrCommentNotFound {
+			helper.WriteError(w, ErrCommentNotFound.Error(), http.StatusNotFound, api.CodeCommentNotFound)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonAttachments, err := json.Marshal(attachments)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonAttachments, SuccessfulListMessage)
+	helper.Write(w, response)
+}
+
+// Download handles GET /api/attachments/{id} requests.
+// It parses the id string, and passes it to the attachment service to open the stored file, which
+// is then written as the raw HTTP response body.
+func (h *handler) Download(w http.ResponseWriter, r *http.Request) {
+	h.download(w, r, false)
+}
+
+// DownloadThumbnail handles GET /api/attachments/{id}/thumbnail requests.
+// It parses the id string, and passes it to the attachment service to open the stored thumbnail of
+// the image, which is then written as the raw HTTP response body.
+func (h *handler) DownloadThumbnail(w http.ResponseWriter, r *http.Request) {
+	h.download(w, r, true)
+}
+
+// DeleteAttachment handles DELETE /api/attachments/{id} requests.
+// It parses the id string, and passes it to the attachment service to delete the attachment, which
+// then serializes the result into a JSON HTTP response.
+func (h *handler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidAttachmentIdMessage, http.StatusBadRequest, api.CodeInvalidAttachmentID)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	err = h.service.DeleteAttachment(r.Context(), userId, id)
+	if err != nil {
+		if err == ErrAttachmentNotFound {
+			helper.WriteError(w, ErrAttachmentNotFound.Error(), http.StatusNotFound, api.CodeAttachmentNotFound)
+			return
+		}
+		if err == ErrPermissionDenied {
+			helper.WriteError(w, ErrPermissionDenied.Error(), http.StatusForbidden, api.CodePermissionDenied)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseMessage(SuccessfulDeleteMessage)
+	helper.Write(w, response)
+}
+
+// download writes the stored attachment or its thumbnail as the response body. The file is never
+// sniffed or run by the browser, and only images are shown inline.
+func (h *handler) download(w http.ResponseWriter, r *http.Request, thumbnail bool) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidAttachmentIdMessage, http.StatusBadRequest, api.CodeInvalidAttachmentID)
+		return
+	}
+
+	file, err := h.service.Open(r.Context(), id, thumbnail)
+	if err != nil {
+		if err == ErrAttachmentNotFound {
+			helper.WriteError(w, ErrAttachmentNotFound.Error(), http.StatusNotFound, api.CodeAttachmentNotFound)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+	defer file.Body.Close()
+
+	disposition := "attachment"
+	if file.Inline {
+		disposition = "inline"
+	}
+
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": file.Filename}))
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; sandbox")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+
+	_, err = io.Copy(w, file.Body)
+	if err != nil {
+		log.Printf("failed to write attachment %d: %v", id, err)
+	}
+}
+
+// readUpload reads the file from the `file` field of the multipart form, refusing bodies that are
+// larger than an attachment may be.
+func readUpload(w http.ResponseWriter, r *http.Request) (Upload, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, MaxSize+multipartOverhead)
+
+	err := r.ParseMultipartForm(multipartMemory)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return Upload{}, ErrFileTooLarge
+		}
+		return Upload{}, ErrMissingFile
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return Upload{}, ErrMissingFile
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, MaxSize+1))
+	if err != nil {
+		return Upload{}, err
+	}
+
+	if len(data) > MaxSize {
+		return Upload{}, ErrFileTooLarge
+	}
+
+	return Upload{Filename: header.Filename, Data: data}, nil
+}
+
+// writeUploadError writes the HTTP response of an upload that failed.
+func writeUploadError(w http.ResponseWriter, r *http.Request, err error) {
+	if suspended, ok := helper.AsSuspendedError(err); ok {
+		helper.WriteSuspendedError(w, suspended)
+		return
+	}
+	if err == ErrMissingFile {
+		helper.WriteError(w, ErrMissingFile.Error(), http.StatusBadRequest, api.CodeMissingFile)
+		return
+	}
+	if err == ErrFileTooLarge {
+		helper.WriteError(w, ErrFileTooLarge.Error(), http.StatusRequestEntityTooLarge, api.CodeFileTooLarge)
+		return
+	}
+	if err == ErrUnsupportedType {
+		helper.WriteError(w, ErrUnsupportedType.Error(), http.StatusUnsupportedMediaType, api.CodeUnsupportedFileType)
+		return
+	}
+	if err == ErrInvalidImage {
+		helper.WriteError(w, ErrInvalidImage.Error(), http.StatusBadRequest, api.CodeInvalidImage)
+		return
+	}
+	if err == ErrPostNotFound {
+		helper.WriteError(w, ErrPostNotFound.Error(), http.StatusNotFound, api.CodePostNotFound)
+		return
+	}
+	if err == ErrCommentNotFound {
+		helper.WriteError(w, ErrCommentNotFound.Error(), http.StatusNotFound, api.CodeCommentNotFound)
+		return
+	}
+	if err == ErrPermissionDenied {
+		helper.WriteError(w, ErrPermissionDenied.Error(), http.StatusForbidden, api.CodePermissionDenied)
+		return
+	}
+
+	helper.WriteInternalError(w, r, err)
+}
@@ -0,0 +1,17 @@
+package attachments
+
+import "github.com/go-chi/chi/v5"
+
+// Routes group all attachment related HTTP endpoints together, with the base prefix path
+// /attachments. It connects the URLS to their respective handler methods.
+func Routes(router chi.Router, h *handler) {
+	router.Route("/attachments", func(r chi.Router) {
+		r.Get("/posts/{postId}", h.ListByPost)
+		r.Get("/comments/{commentId}", h.ListByComment)
+		r.Get("/{id}", h.Download)
+		r.Get("/{id}/thumbnail", h.DownloadThumbnail)
+		r.Post("/posts/{postId}", h.UploadToPost)
+		r.Post("/comments/{commentId}", h.UploadToComment)
+		r.Delete("/{id}", h.DeleteAttachment)
+	})
+}
@@ -0,0 +1,359 @@
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+	"github.com/haobuhaoo/gossip-with-go/internal/storage"
+	"github.com/haobuhaoo/gossip-with-go/internal/suspensions"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxFilenameLength is the longest file name that is kept for an attachment.
+const maxFilenameLength = 255
+
+// svc implements the Service interface.
+// It depends on the sql generated Queries type to interact with the PostgreSQL database, on the
+// storage to keep the files, and on the suspension service to refuse suspended users.
+type svc struct {
+	repo        *repo.Queries
+	storage     storage.Storage
+	suspensions suspensions.Service
+}
+
+// NewService creates a new attachment service.
+func NewService(repo *repo.Queries, storage storage.Storage, suspensions suspensions.Service) Service {
+	return &svc{
+		repo:        repo,
+		storage:     storage,
+		suspensions: suspensions,
+	}
+}
+
+// UploadToPost stores the file as an attachment of the post given by the id and returns it. Only
+// the author of the post may attach files to it, unless they are suspended from the topic.
+func (s *svc) UploadToPost(ctx context.Context, userId int64, postId int64, file Upload) (Attachment, error) {
+	post, err := s.repo.FindPostAuthor(ctx, postId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return Attachment{}, ErrPostNotFound
+		}
+		return Attachment{}, err
+	}
+
+	err = s.checkAuthor(ctx, userId, post.UserID, post.TopicID)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	arg := repo.CreateAttachmentParams{
+		UserID: userId,
+		PostID: pgtype.Int8{Int64: postId, Valid: true},
+	}
+	return s.upload(ctx, arg, file)
+}
+
+// UploadToComment stores the file as an attachment of the comment given by the id and returns it.
+// Only the author of the comment may attach files to it, unless they are suspended from the topic.
+func (s *svc) UploadToComment(ctx context.Context, userId int64, commentId int64, file Upload) (Attachment, error) {
+	comment, err := s.repo.FindCommentAuthor(ctx, commentId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return Attachment{}, ErrCommentNotFound
+		}
+		return Attachment{}, err
+	}
+
+	err = s.checkAuthor(ctx, userId, comment.UserID, comment.TopicID)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	arg := repo.CreateAttachmentParams{
+		UserID:    userId,
+		CommentID: pgtype.Int8{Int64: commentId, Valid: true},
+	}
+	return s.upload(ctx, arg, file)
+}
+
+// ListByPost returns the attachments of the post given by the id, in the order they were uploaded.
+// Attachments of deleted posts are hidden together with the post.
+func (s *svc) ListByPost(ctx context.Context, postId int64) ([]Attachment, error) {
+	_, err := s.repo.FindPostAuthor(ctx, postId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return []Attachment{}, ErrPostNotFound
+		}
+		return []Attachment{}, err
+	}
+
+	rows, err := s.repo.ListPostAttachments(ctx, pgtype.Int8{Int64: postId, Valid: true})
+	if err != nil {
+		return []Attachment{}, err
+	}
+
+	return toAttachments(rows), nil
+}
+
+// ListByComment returns the attachments of the comment given by the id, in the order they were
+// uploaded. Attachments of deleted comments are hidden together with the comment.
+func (s *svc) ListByComment(ctx context.Context, commentId int64) ([]Attachment, error) {
+	_, err := s.repo.FindCommentAuthor(ctx, commentId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return []Attachment{}, ErrCommentNotFound
+		}
+		return []Attachment{}, err
+	}
+
+	rows, err := s.repo.ListCommentAttachments(ctx, pgtype.Int8{Int64: commentId, Valid: true})
+	if err != nil {
+		return []Attachment{}, err
+	}
+
+	return toAttachments(rows), nil
+}
+
+// Open returns the stored content of the attachment given by the id, or of its thumbnail. Images
+// are shown inline, while other files are downloaded.
+func (s *svc) Open(ctx context.Context, attachmentId int64, thumbnail bool) (File, error) {
+	attachment, err := s.repo.FindAttachment(ctx, attachmentId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return File{}, ErrAttachmentNotFound
+		}
+		return File{}, err
+	}
+
+	key := attachment.StorageKey
+	file := File{
+		Filename:    attachment.Filename,
+		ContentType: attachment.ContentType,
+		Inline:      attachment.ThumbnailKey.Valid,
+	}
+	if thumbnail {
+		if !attachment.ThumbnailKey.Valid {
+			return File{}, ErrAttachmentNotFound
+		}
+		key = attachment.ThumbnailKey.String
+		file.ContentType = "image/jpeg"
+	}
+
+	file.Body, err = s.storage.Open(ctx, key)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return File{}, ErrAttachmentNotFound
+		}
+		return File{}, err
+	}
+
+	return file, nil
+}
+
+// DeleteAttachment removes the attachment given by the id together with its stored files. Only
+// the user who uploaded the attachment may delete it.
+func (s *svc) DeleteAttachment(ctx context.Context, userId int64, attachmentId int64) error {
+	attachment, err := s.repo.FindAttachment(ctx, attachmentId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrAttachmentNotFound
+		}
+		return err
+	}
+
+	if attachment.UserID != userId {
+		return ErrPermissionDenied
+	}
+
+	deleted, err := s.repo.DeleteAttachment(ctx, attachmentId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrAttachmentNotFound
+		}
+		return err
+	}
+
+	s.removeFiles(ctx, deleted.StorageKey, deleted.ThumbnailKey)
+	return nil
+}
+
+// checkAuthor returns ErrPermissionDenied if the user is not the author, or a
+// *helper.SuspendedError if the user is suspended from the topic.
+func (s *svc) checkAuthor(ctx context.Context, userId int64, authorId int64, topicId int64) error {
+	if authorId != userId {
+		return ErrPermissionDenied
+	}
+
+	return s.suspensions.CheckTopic(ctx, userId, topicId)
+}
+
+// upload checks the size and sniffed type of the file, stores it together with a thumbnail for
+// images, and creates the attachment with the given owner. The stored files are removed again if
+// the attachment cannot be created.
+func (s *svc) upload(ctx context.Context, arg repo.CreateAttachmentParams, file Upload) (Attachment, error) {
+	if len(file.Data) > MaxSize {
+		return Attachment{}, ErrFileTooLarge
+	}
+
+	mtype := mimetype.Detect(file.Data)
+	if !mimetype.EqualsAny(mtype.String(), allowedTypes...) {
+		return Attachment{}, ErrUnsupportedType
+	}
+
+	var thumbnail []byte
+	if strings.HasPrefix(mtype.String(), "image/") {
+		var width, height int
+		var err error
+		thumbnail, width, height, err = helper.Thumbnail(file.Data)
+		if err != nil {
+			if err == helper.ErrInvalidImage {
+				return Attachment{}, ErrInvalidImage
+			}
+			return Attachment{}, err
+		}
+		arg.Width = pgtype.Int4{Int32: int32(width), Valid: true}
+		arg.Height = pgtype.Int4{Int32: int32(height), Valid: true}
+	}
+
+	name, err := newKey()
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	arg.StorageKey = "attachments/" + name + mtype.Extension()
+	arg.Filename = cleanFilename(file.Filename, mtype.Extension())
+	arg.ContentType = mtype.String()
+	arg.SizeBytes = int64(len(file.Data))
+
+	err = s.storage.Put(ctx, arg.StorageKey, bytes.NewReader(file.Data), arg.SizeBytes, arg.ContentType)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	if thumbnail != nil {
+		arg.ThumbnailKey = pgtype.Text{String: "thumbnails/" + name + ".jpg", Valid: true}
+		err = s.storage.Put(ctx, arg.ThumbnailKey.String, bytes.NewReader(thumbnail), int64(len(thumbnail)), "image/jpeg")
+		if err != nil {
+			s.removeFiles(ctx, arg.StorageKey, pgtype.Text{})
+			return Attachment{}, err
+		}
+	}
+
+	attachment, err := s.repo.CreateAttachment(ctx, arg)
+	if err != nil {
+		s.removeFiles(ctx, arg.StorageKey, arg.ThumbnailKey)
+		if helper.IsForeignKeyViolation(err) {
+			if arg.PostID.Valid {
+				return Attachment{}, ErrPostNotFound
+			}
+			return Attachment{}, ErrCommentNotFound
+		}
+		return Attachment{}, err
+	}
+
+	return toAttachment(attachment), nil
+}
+
+// removeFiles removes the stored file of an attachment and its thumbnail, if any. Failures are
+// only logged, as the attachment itself is already gone.
+func (s *svc) removeFiles(ctx context.Context, key string, thumbnailKey pgtype.Text) {
+	err := s.storage.Delete(ctx, key)
+	if err != nil {
+		log.Printf("failed to delete stored file %s: %v", key, err)
+	}
+
+	if thumbnailKey.Valid {
+		err = s.storage.Delete(ctx, thumbnailKey.String)
+		if err != nil {
+			log.Printf("failed to delete stored file %s: %v", thumbnailKey.String, err)
+		}
+	}
+}
+
+// newKey returns a random name to store a file under, so that stored files cannot be guessed.
+func newKey() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// cleanFilename returns the base name of the uploaded file without any directories or control
+// characters, or a generic name with the given extension if nothing is left.
+func cleanFilename(filename string, ext string) string {
+	name := strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, filename)
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+
+	if name == "." || name == "/" || name == "" {
+		name = "file" + ext
+	}
+
+	runes := []rune(name)
+	if len(runes) > maxFilenameLength {
+		name = string(runes[len(runes)-maxFilenameLength:])
+	}
+
+	return name
+}
+
+// toAttachments converts attachment rows into Attachment models.
+func toAttachments(rows []repo.Attachment) []Attachment {
+	attachments := make([]Attachment, 0, len(rows))
+	for _, row := range rows {
+		attachments = append(attachments, toAttachment(row))
+	}
+	return attachments
+}
+
+// toAttachment converts an attachment row into the Attachment model, with the URLs it can be
+// downloaded from.
+func toAttachment(row repo.Attachment) Attachment {
+	attachment := Attachment{
+		AttachmentID: row.AttachmentID,
+		UserID:       row.UserID,
+		Filename:     row.Filename,
+		ContentType:  row.ContentType,
+		Size:         row.SizeBytes,
+		URL:          fmt.Sprintf("/api/attachments/%d", row.AttachmentID),
+		CreatedAt:    row.CreatedAt.Time,
+	}
+
+	if row.PostID.Valid {
+		postId := row.PostID.Int64
+		attachment.PostID = &postId
+	}
+
+	if row.CommentID.Valid {
+		commentId := row.CommentID.Int64
+		attachment.CommentID = &commentId
+	}
+
+	if row.Width.Valid && row.Height.Valid {
+		width, height := row.Width.Int32, row.Height.Int32
+		attachment.Width = &width
+		attachment.Height = &height
+	}
+
+	if row.ThumbnailKey.Valid {
+		attachment.ThumbnailURL = attachment.URL + "/thumbnail"
+	}
+
+	return attachment
+}
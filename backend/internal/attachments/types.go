@@ -0,0 +1,63 @@
+package attachments
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// MaxSize is the largest file in bytes that can be uploaded as an attachment.
+const MaxSize = 10 << 20
+
+// allowedTypes are the MIME types that attachments may have, as sniffed from their content.
+var allowedTypes = []string{
+	"image/jpeg",
+	"image/png",
+	"image/gif",
+	"image/webp",
+	"application/pdf",
+	"text/plain",
+}
+
+// Service defines the domain logic for attachment related operations.
+// It is responsible for enforcing application rules, making database calls and storing the files.
+type Service interface {
+	UploadToPost(ctx context.Context, userId int64, postId int64, file Upload) (Attachment, error)
+	UploadToComment(ctx context.Context, userId int64, commentId int64, file Upload) (Attachment, error)
+	ListByPost(ctx context.Context, postId int64) ([]Attachment, error)
+	ListByComment(ctx context.Context, commentId int64) ([]Attachment, error)
+	Open(ctx context.Context, attachmentId int64, thumbnail bool) (File, error)
+	DeleteAttachment(ctx context.Context, userId int64, attachmentId int64) error
+}
+
+// Attachment model that is passed to the frontend.
+// Exactly one of PostID and CommentID is set. Width, Height and ThumbnailURL are only set for
+// images.
+type Attachment struct {
+	AttachmentID int64     `json:"attachment_id"`
+	UserID       int64     `json:"user_id"`
+	PostID       *int64    `json:"post_id"`
+	CommentID    *int64    `json:"comment_id"`
+	Filename     string    `json:"filename"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	Width        *int32    `json:"width,omitempty"`
+	Height       *int32    `json:"height,omitempty"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Upload is a file uploaded by a user, with the name it had on their device.
+type Upload struct {
+	Filename string
+	Data     []byte
+}
+
+// File is the stored content of an attachment or its thumbnail, which must be closed once read.
+type File struct {
+	Body        io.ReadCloser
+	Filename    string
+	ContentType string
+	Inline      bool
+}
@@ -0,0 +1,7 @@
+package audit
+
+import "errors"
+
+var (
+	ErrInvalidFilter = errors.New("invalid audit log filter")
+)
@@ -0,0 +1,152 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	InvalidFilterMessage          = "Invalid audit log filter"
+	InvalidPageMessage            = "Invalid limit or cursor"
+	SuccessfulListAuditLogMessage = "Successfully listed audit log"
+)
+
+// handler handles the audit log related HTTP requests.
+// It is responsible for translating HTTP requests into service calls and formatting service
+// responses into HTTP responses.
+type handler struct {
+	service Service
+}
+
+// NewHandler creates a new audit log handler.
+func NewHandler(service Service) *handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// ListAuditLog handles GET /api/admin/audit requests.
+// It parses the optional actor, action, target_type, target_id, topic, from, to, limit and cursor
+// query strings, and passes them to the audit log service to return a page of the matching
+// entries. It then serializes the page and its pagination metadata into a JSON HTTP response.
+func (h *handler) ListAuditLog(w http.ResponseWriter, r *http.Request) {
+	arg, err := readFilter(r)
+	if err != nil {
+		helper.WriteError(w, InvalidFilterMessage, http.StatusBadRequest, api.CodeInvalidFilter)
+		return
+	}
+
+	page, err := helper.ReadPage(r)
+	if err != nil {
+		helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+		return
+	}
+
+	logs, meta, err := h.service.ListAuditLog(r.Context(), arg, page)
+	if err != nil {
+		if err == helper.ErrInvalidCursor {
+			helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonLogs, err := json.Marshal(logs)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonMeta, err := json.Marshal(meta)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataMetaAndMessage(jsonLogs, jsonMeta, SuccessfulListAuditLogMessage)
+	helper.Write(w, response)
+}
+
+// readFilter parses the optional filter query strings of the HTTP request. The ids must be
+// integers, the target type must be one of the known target types, and from and to must be RFC
+// 3339 timestamps.
+func readFilter(r *http.Request) (repo.ListAuditLogParams, error) {
+	values := r.URL.Query()
+	arg := repo.ListAuditLogParams{}
+	var err error
+
+	arg.ActorID, err = readID(values.Get("actor"))
+	if err != nil {
+		return repo.ListAuditLogParams{}, err
+	}
+
+	arg.TargetID, err = readID(values.Get("target_id"))
+	if err != nil {
+		return repo.ListAuditLogParams{}, err
+	}
+
+	arg.TopicID, err = readID(values.Get("topic"))
+	if err != nil {
+		return repo.ListAuditLogParams{}, err
+	}
+
+	arg.Since, err = readTime(values.Get("from"))
+	if err != nil {
+		return repo.ListAuditLogParams{}, err
+	}
+
+	arg.Until, err = readTime(values.Get("to"))
+	if err != nil {
+		return repo.ListAuditLogParams{}, err
+	}
+
+	if action := values.Get("action"); action != "" {
+		arg.Action = pgtype.Text{String: action, Valid: true}
+	}
+
+	if targetType := values.Get("target_type"); targetType != "" {
+		switch targetType {
+		case TargetTopic, TargetPost, TargetComment, TargetUser, TargetReport:
+		default:
+			return repo.ListAuditLogParams{}, ErrInvalidFilter
+		}
+		arg.TargetType = pgtype.Text{String: targetType, Valid: true}
+	}
+
+	return arg, nil
+}
+
+// readID parses an optional id query string.
+func readID(s string) (pgtype.Int8, error) {
+	if s == "" {
+		return pgtype.Int8{}, nil
+	}
+
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id < 1 {
+		return pgtype.Int8{}, ErrInvalidFilter
+	}
+	return pgtype.Int8{Int64: id, Valid: true}, nil
+}
+
+// readTime parses an optional RFC 3339 timestamp query string.
+func readTime(s string) (pgtype.Timestamptz, error) {
+	if s == "" {
+		return pgtype.Timestamptz{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return pgtype.Timestamptz{}, ErrInvalidFilter
+	}
+	return pgtype.Timestamptz{Time: t, Valid: true}, nil
+}
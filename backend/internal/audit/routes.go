@@ -0,0 +1,10 @@
+package audit
+
+import "github.com/go-chi/chi/v5"
+
+// AdminRoutes group all audit log HTTP endpoints together. They must be mounted under the admin
+// prefix path, behind a middleware that only lets admins through.
+// It connects the URLS to their respective handler methods.
+func AdminRoutes(router chi.Router, h *handler) {
+	router.Get("/audit", h.ListAuditLog)
+}
@@ -0,0 +1,123 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// svc implements the Service interface.
+// It depends on the sql generated Queries type to interact with the PostgreSQL database.
+type svc struct {
+	repo *repo.Queries
+}
+
+// NewService creates a new audit log service.
+func NewService(repo *repo.Queries) Service {
+	return &svc{
+		repo: repo,
+	}
+}
+
+// Record appends the entry to the audit log through qtx, which is bound to the transaction of the
+// action, so the entry is only recorded if the action is committed and the action fails if the
+// entry cannot be recorded. Entries can never be changed or removed once they are recorded.
+func (s *svc) Record(ctx context.Context, qtx *repo.Queries, entry Entry) error {
+	before, err := snapshot(entry.Before)
+	if err != nil {
+		return err
+	}
+
+	after, err := snapshot(entry.After)
+	if err != nil {
+		return err
+	}
+
+	arg := repo.CreateAuditLogParams{
+		ActorID:    entry.ActorID,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		TopicID:    pgtype.Int8{Int64: entry.TopicID, Valid: entry.TopicID != 0},
+		Before:     before,
+		After:      after,
+	}
+	return qtx.CreateAuditLog(ctx, arg)
+}
+
+// ListAuditLog returns a page of the audit log entries that match the filters of arg, newest
+// first, together with the pagination metadata.
+func (s *svc) ListAuditLog(ctx context.Context, arg repo.ListAuditLogParams, page helper.Page) ([]Log, api.PageMeta, error) {
+	arg.PageLimit = page.Limit + 1
+	if page.Cursor != "" {
+		var c auditCursor
+		err := helper.DecodeCursor(page.Cursor, &c)
+		if err != nil {
+			return []Log{}, api.PageMeta{}, err
+		}
+		arg.CursorAuditID = pgtype.Int8{Int64: c.AuditID, Valid: true}
+	}
+
+	rows, err := s.repo.ListAuditLog(ctx, arg)
+	if err != nil {
+		return []Log{}, api.PageMeta{}, err
+	}
+
+	countArg := repo.CountAuditLogParams{
+		ActorID:    arg.ActorID,
+		Action:     arg.Action,
+		TargetType: arg.TargetType,
+		TargetID:   arg.TargetID,
+		TopicID:    arg.TopicID,
+		Since:      arg.Since,
+		Until:      arg.Until,
+	}
+	total, err := s.repo.CountAuditLog(ctx, countArg)
+	if err != nil {
+		return []Log{}, api.PageMeta{}, err
+	}
+
+	fetched := len(rows)
+	if fetched > int(page.Limit) {
+		rows = rows[:page.Limit]
+	}
+
+	logs := make([]Log, 0, len(rows))
+	for _, row := range rows {
+		entry := Log{
+			AuditID:    row.AuditID,
+			ActorID:    row.ActorID,
+			ActorName:  row.ActorName,
+			Action:     row.Action,
+			TargetType: row.TargetType,
+			TargetID:   row.TargetID,
+			Before:     row.Before,
+			After:      row.After,
+			CreatedAt:  row.CreatedAt.Time,
+		}
+		if row.TopicID.Valid {
+			entry.TopicID = &row.TopicID.Int64
+		}
+		logs = append(logs, entry)
+	}
+
+	nextCursor := ""
+	if len(logs) > 0 {
+		last := logs[len(logs)-1]
+		nextCursor = helper.EncodeCursor(auditCursor{AuditID: last.AuditID})
+	}
+
+	return logs, helper.NewPageMeta(fetched, page, total, nextCursor), nil
+}
+
+// snapshot serializes v into JSON, or returns nil if v is nil.
+func snapshot(v any) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
@@ -0,0 +1,78 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+)
+
+// Actions taken by moderators and admins that are recorded in the audit log.
+const (
+	ActionTopicUpdate      = "topic.update"
+	ActionTopicDelete      = "topic.delete"
+	ActionPostUpdate       = "post.update"
+	ActionPostDelete       = "post.delete"
+	ActionPostRestore      = "post.restore"
+	ActionCommentUpdate    = "comment.update"
+	ActionCommentDelete    = "comment.delete"
+	ActionCommentRestore   = "comment.restore"
+	ActionReportResolve    = "report.resolve"
+	ActionSuspensionCreate = "suspension.create"
+	ActionSuspensionLift   = "suspension.lift"
+	ActionRoleUpdate       = "role.update"
+	ActionModeratorAdd     = "moderator.add"
+	ActionModeratorRemove  = "moderator.remove"
+)
+
+// Types of the targets that an action can be taken on.
+const (
+	TargetTopic   = "topic"
+	TargetPost    = "post"
+	TargetComment = "comment"
+	TargetUser    = "user"
+	TargetReport  = "report"
+)
+
+// Service defines the domain logic for audit log related operations.
+// It is responsible for enforcing application rules and making database calls.
+type Service interface {
+	Record(ctx context.Context, qtx *repo.Queries, entry Entry) error
+	ListAuditLog(ctx context.Context, arg repo.ListAuditLogParams, page helper.Page) ([]Log, api.PageMeta, error)
+}
+
+// Entry describes an action to record in the audit log. TopicID is the topic the target belongs
+// to, or 0 if it does not belong to one. Before and After are snapshots of the target around the
+// action, serialized into JSON, and are left nil when the target did not exist before or after it.
+type Entry struct {
+	ActorID    int64
+	Action     string
+	TargetType string
+	TargetID   int64
+	TopicID    int64
+	Before     any
+	After      any
+}
+
+// Log model that is passed to the frontend for an audit log entry.
+type Log struct {
+	AuditID    int64           `json:"audit_id"`
+	ActorID    int64           `json:"actor_id"`
+	ActorName  string          `json:"actor_name"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   int64           `json:"target_id"`
+	TopicID    *int64          `json:"topic_id,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// auditCursor holds the id of the last audit log entry in a page, which is encoded into the
+// opaque next_cursor string.
+type auditCursor struct {
+	AuditID int64 `json:"audit_id"`
+}
@@ -1,22 +1,35 @@
 package auth
 
 import (
+	"context"
 	"encoding/json"
+	"io"
 	"net/http"
+	"strconv"
 	"time"
 
-	"github.com/go-playground/validator/v10"
+	"github.com/go-chi/chi/v5"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
 	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
 )
 
 const (
-	InvalidRequestBodyMessage         = "Required fields missing"
 	InvalidCredentialMessage          = "Invalid credentials"
-	FailedTokenGenerationMessage      = "Failed to generate token"
+	InvalidPasswordMessage            = "Password must be between 8 and 72 characters"
+	SamePasswordMessage               = "New password must be different from current password"
+	InvalidRefreshTokenMessage        = "Invalid refresh token"
+	InvalidUserIdMessage              = "Invalid user id"
 	MissingUserIDMessage              = "Missing userID"
+	MissingTokenIDMessage             = "Missing token id"
 	SuccessfulLoginMessage            = "Successfully login"
+	SuccessfulRefreshMessage          = "Successfully refreshed token"
+	SuccessfulLogoutMessage           = "Successfully logout"
+	SuccessfulLogoutAllMessage        = "Successfully logout of all devices"
+	SuccessfulForceLogoutMessage      = "Successfully logout user of all devices"
 	SuccessfulAuthenticateUserMessage = "Successfully authenticate user"
+	SuccessfulChangePasswordMessage   = "Successfully changed password"
 )
 
 // handler handles the authentication related HTTP requests.
@@ -36,48 +49,42 @@ func NewHandler(service Service, secret string) *handler {
 }
 
 // Login handles POST /auth/login requests.
-// It reads and validates the request body, and passes it to the authentication service to login
-// the user. It then generates a JWT token with the userID that expires in 12 hours, and serializes
-// it into a JSON HTTP response.
+// It reads and validates the request body, and passes it to the authentication service to verify
+// the username and password. It then generates a short-lived JWT access token with the userID and
+// a refresh token, and serializes them into a JSON HTTP response.
 func (h *handler) Login(w http.ResponseWriter, r *http.Request) {
 	var req LoginRequest
 	err := helper.Read(r, &req)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusBadRequest)
+		helper.WriteError(w, err.Error(), http.StatusBadRequest, api.CodeMalformedBody)
 		return
 	}
 
-	err = validator.New().Struct(req)
+	err = helper.Validate(req)
 	if err != nil {
-		helper.WriteError(w, InvalidRequestBodyMessage, http.StatusBadRequest)
+		helper.WriteValidationError(w, err)
 		return
 	}
 
-	user, err := h.service.Login(r.Context(), req.Username)
+	user, err := h.service.Login(r.Context(), req.Username, req.Password)
 	if err != nil {
-		if err == ErrUserNotFound {
-			helper.WriteError(w, InvalidCredentialMessage, http.StatusUnauthorized)
+		if err == ErrInvalidCredentials {
+			helper.WriteError(w, InvalidCredentialMessage, http.StatusUnauthorized, api.CodeInvalidCredentials)
 			return
 		}
-		helper.WriteError(w, err.Error(), http.StatusUnauthorized)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
-	claims := jwt.MapClaims{
-		"user_id": user.UserID,
-		"exp":     time.Now().Add(12 * time.Hour).Unix(),
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString(h.jwtSecret)
+	resp, err := h.issueTokens(r.Context(), user)
 	if err != nil {
-		helper.WriteError(w, FailedTokenGenerationMessage, http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
-	jsonResp, err := json.Marshal(LoginResponse{Token: signedToken})
+	jsonResp, err := json.Marshal(resp)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
@@ -91,27 +98,270 @@ func (h *handler) Login(w http.ResponseWriter, r *http.Request) {
 func (h *handler) AuthenticateUser(w http.ResponseWriter, r *http.Request) {
 	userId, ok := r.Context().Value("userID").(int64)
 	if !ok {
-		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest)
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
 		return
 	}
 
 	user, err := h.service.AuthenticateUser(r.Context(), userId)
 	if err != nil {
 		if err == ErrUserNotFound {
-			helper.WriteError(w, ErrUserNotFound.Error(), http.StatusNotFound)
+			helper.WriteError(w, ErrUserNotFound.Error(), http.StatusNotFound, api.CodeUserNotFound)
 			return
 		}
 
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
 	jsonUser, err := json.Marshal(user)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
 	response := helper.ParseResponseDataAndMessage(jsonUser, SuccessfulAuthenticateUserMessage)
 	helper.Write(w, response)
 }
+
+// ChangePassword handles PUT /api/me/password requests.
+// It gets the userId from the JWT context, reads and validates the request body, and passes it to
+// the authentication service to replace the user's password. Since the old token may carry the
+// forced password change flag and all existing tokens are revoked, it then generates a new pair of
+// tokens and serializes them into a JSON HTTP response.
+func (h *handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	var req ChangePasswordRequest
+	err := helper.Read(r, &req)
+	if err != nil {
+		helper.WriteError(w, err.Error(), http.StatusBadRequest, api.CodeMalformedBody)
+		return
+	}
+
+	err = helper.Validate(req)
+	if err != nil {
+		helper.WriteValidationError(w, err)
+		return
+	}
+
+	if !helper.IsValidPasswordLength(req.NewPassword) {
+		helper.WriteError(w, InvalidPasswordMessage, http.StatusBadRequest, api.CodeInvalidPassword)
+		return
+	}
+
+	if req.NewPassword == req.CurrentPassword {
+		helper.WriteError(w, SamePasswordMessage, http.StatusBadRequest, api.CodeSamePassword)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	user, err := h.service.ChangePassword(r.Context(), userId, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		if err == ErrInvalidCredentials {
+			helper.WriteError(w, InvalidCredentialMessage, http.StatusUnauthorized, api.CodeInvalidCredentials)
+			return
+		}
+		if err == ErrUserNotFound {
+			helper.WriteError(w, ErrUserNotFound.Error(), http.StatusNotFound, api.CodeUserNotFound)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	resp, err := h.issueTokens(r.Context(), user)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonResp, err := json.Marshal(resp)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonResp, SuccessfulChangePasswordMessage)
+	helper.Write(w, response)
+}
+
+// Refresh handles POST /auth/refresh requests.
+// It reads and validates the request body, and passes the refresh token to the authentication
+// service to rotate it. It then generates a new access token and serializes both tokens into a
+// JSON HTTP response.
+func (h *handler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req RefreshRequest
+	err := helper.Read(r, &req)
+	if err != nil {
+		helper.WriteError(w, err.Error(), http.StatusBadRequest, api.CodeMalformedBody)
+		return
+	}
+
+	err = helper.Validate(req)
+	if err != nil {
+		helper.WriteValidationError(w, err)
+		return
+	}
+
+	user, refreshToken, err := h.service.RotateRefreshToken(r.Context(), req.RefreshToken)
+	if err != nil {
+		if err == ErrInvalidRefreshToken {
+			helper.WriteError(w, InvalidRefreshTokenMessage, http.StatusUnauthorized, api.CodeInvalidRefreshToken)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	signedToken, err := h.generateToken(user)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	resp := LoginResponse{
+		Token:              signedToken,
+		RefreshToken:       refreshToken,
+		ExpiresIn:          int64(AccessTokenTTL.Seconds()),
+		MustChangePassword: user.MustChangePassword,
+	}
+	jsonResp, err := json.Marshal(resp)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonResp, SuccessfulRefreshMessage)
+	helper.Write(w, response)
+}
+
+// Logout handles POST /auth/logout requests.
+// It gets the userId and token id from the JWT context, reads the optional refresh token from the
+// request body, and passes them to the authentication service to revoke both tokens. It then
+// serializes the result into a JSON HTTP response.
+func (h *handler) Logout(w http.ResponseWriter, r *http.Request) {
+	var req LogoutRequest
+	err := helper.Read(r, &req)
+	if err != nil && err != io.EOF {
+		helper.WriteError(w, err.Error(), http.StatusBadRequest, api.CodeMalformedBody)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	tokenId, ok := r.Context().Value("tokenID").(string)
+	if !ok {
+		helper.WriteError(w, MissingTokenIDMessage, http.StatusBadRequest, api.CodeMissingTokenID)
+		return
+	}
+
+	expiresAt, _ := r.Context().Value("tokenExpiresAt").(time.Time)
+
+	token := AccessToken{
+		ID:        tokenId,
+		UserID:    userId,
+		ExpiresAt: expiresAt,
+	}
+	err = h.service.Logout(r.Context(), token, req.RefreshToken)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseMessage(SuccessfulLogoutMessage)
+	helper.Write(w, response)
+}
+
+// LogoutAll handles POST /api/me/logout-all requests.
+// It gets the userId from the JWT context, and passes it to the authentication service to revoke
+// every token issued to the user. It then serializes the result into a JSON HTTP response.
+func (h *handler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	err := h.service.LogoutAll(r.Context(), userId)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseMessage(SuccessfulLogoutAllMessage)
+	helper.Write(w, response)
+}
+
+// ForceLogout handles POST /api/admin/users/{id}/logout requests.
+// It parses the id string, and passes it to the authentication service to revoke every token
+// issued to that user, logging them out of all devices. It then serializes the result into a JSON
+// HTTP response.
+func (h *handler) ForceLogout(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidUserIdMessage, http.StatusBadRequest, api.CodeInvalidUserID)
+		return
+	}
+
+	err = h.service.LogoutAll(r.Context(), id)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseMessage(SuccessfulForceLogoutMessage)
+	helper.Write(w, response)
+}
+
+// issueTokens generates a new access token and refresh token for the user.
+func (h *handler) issueTokens(ctx context.Context, user repo.User) (LoginResponse, error) {
+	signedToken, err := h.generateToken(user)
+	if err != nil {
+		return LoginResponse{}, err
+	}
+
+	refreshToken, err := h.service.IssueRefreshToken(ctx, user.UserID)
+	if err != nil {
+		return LoginResponse{}, err
+	}
+
+	resp := LoginResponse{
+		Token:              signedToken,
+		RefreshToken:       refreshToken,
+		ExpiresIn:          int64(AccessTokenTTL.Seconds()),
+		MustChangePassword: user.MustChangePassword,
+	}
+	return resp, nil
+}
+
+// generateToken signs a JWT access token with a random token id, the userID, the global role and
+// the forced password change flag that expires after AccessTokenTTL.
+func (h *handler) generateToken(user repo.User) (string, error) {
+	jti, err := randomString(16)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"jti":                  jti,
+		"user_id":              user.UserID,
+		"role":                 user.Role,
+		"must_change_password": user.MustChangePassword,
+		"iat":                  now.Unix(),
+		"exp":                  now.Add(AccessTokenTTL).Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(h.jwtSecret)
+}
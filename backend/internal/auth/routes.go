@@ -1,12 +1,38 @@
 package auth
 
-import "github.com/go-chi/chi/v5"
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
 
 // Routes group all authentication related HTTP endpoints together, with the base prefix
-// path /auth.
+// path /auth. Logout requires a valid access token, so it is wrapped with the `authenticate`
+// middleware.
 // It connects the URLS to their respective handler methods.
-func Routes(router chi.Router, h *handler) {
+func Routes(router chi.Router, h *handler, authenticate func(http.Handler) http.Handler) {
 	router.Route("/auth", func(r chi.Router) {
 		r.Post("/login", h.Login)
+		r.Post("/refresh", h.Refresh)
+		r.With(authenticate).Post("/logout", h.Logout)
+	})
+}
+
+// MeRoutes group all HTTP endpoints about the logged in user together, with the base prefix
+// path /me. They must be mounted behind the JWT middleware.
+// It connects the URLS to their respective handler methods.
+func MeRoutes(router chi.Router, h *handler) {
+	router.Route("/me", func(r chi.Router) {
+		r.Get("/", h.AuthenticateUser)
+		r.Put("/password", h.ChangePassword)
+		r.Post("/logout-all", h.LogoutAll)
 	})
 }
+
+// AdminRoutes group all authentication related HTTP endpoints that are only available to admins.
+// They must be mounted under the admin prefix path, behind a middleware that only lets admins
+// through.
+// It connects the URLS to their respective handler methods.
+func AdminRoutes(router chi.Router, h *handler) {
+	router.Post("/users/{id}/logout", h.ForceLogout)
+}
@@ -2,39 +2,88 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"log"
+	"time"
 
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
 	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+	"github.com/haobuhaoo/gossip-with-go/internal/users"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 30 * 24 * time.Hour
+
+	// purgeInterval is how often Run purges the expired revoked tokens.
+	purgeInterval = time.Hour
+
+	// temporaryPasswordBytes is the number of random bytes in a temporary password, which encode
+	// to 16 characters.
+	temporaryPasswordBytes = 12
 )
 
 // svc implements the Service interface.
 // It depends on the sql generated Queries type to interact with the PostgreSQL database.
 type svc struct {
 	repo *repo.Queries
+	db   *pgxpool.Pool
 }
 
 // NewService creates a new authentication service.
-func NewService(repo *repo.Queries) Service {
+func NewService(repo *repo.Queries, db *pgxpool.Pool) Service {
 	return &svc{
 		repo: repo,
+		db:   db,
 	}
 }
 
-// Login finds and returns the user identified by the name in the database.
-func (s *svc) Login(ctx context.Context, name string) (repo.User, error) {
+// Login finds the user identified by the name in the database and verifies the password against
+// the stored hash. An unknown name and a wrong password both return ErrInvalidCredentials, and
+// the password is checked against a dummy hash for an unknown name, so that the response time does
+// not reveal which names exist.
+func (s *svc) Login(ctx context.Context, name string, password string) (repo.User, error) {
 	user, err := s.repo.FindUserByName(ctx, name)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return repo.User{}, ErrUserNotFound
+			helper.CheckPassword(pgtype.Text{}, password)
+			return repo.User{}, ErrInvalidCredentials
 		}
 		return repo.User{}, err
 	}
 
+	if !helper.CheckPassword(user.PasswordHash, password) {
+		return repo.User{}, ErrInvalidCredentials
+	}
+
 	return user, nil
 }
 
 // AuthenticateUser finds and returns the user identified by the ID in the database.
-func (s *svc) AuthenticateUser(ctx context.Context, id int64) (repo.User, error) {
+func (s *svc) AuthenticateUser(ctx context.Context, id int64) (users.User, error) {
+	user, err := s.repo.FindUserByID(ctx, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return users.User{}, ErrUserNotFound
+		}
+		return users.User{}, err
+	}
+
+	return users.ToUser(user), nil
+}
+
+// ChangePassword verifies the current password of the user identified by the ID, then replaces it
+// with the hash of the new password and returns the updated user. It also clears the forced
+// password change flag and revokes every token issued to the user so that other devices are
+// logged out.
+// If there is an error in between, the whole transaction is rolled back.
+func (s *svc) ChangePassword(ctx context.Context, id int64, currentPassword string, newPassword string) (repo.User, error) {
 	user, err := s.repo.FindUserByID(ctx, id)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -43,5 +92,309 @@ func (s *svc) AuthenticateUser(ctx context.Context, id int64) (repo.User, error)
 		return repo.User{}, err
 	}
 
+	if !helper.CheckPassword(user.PasswordHash, currentPassword) {
+		return repo.User{}, ErrInvalidCredentials
+	}
+
+	hash, err := helper.HashPassword(newPassword)
+	if err != nil {
+		return repo.User{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.User{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.repo.WithTx(tx)
+
+	arg := repo.UpdateUserPasswordParams{
+		UserID:       id,
+		PasswordHash: pgtype.Text{String: hash, Valid: true},
+	}
+	user, err = qtx.UpdateUserPassword(ctx, arg)
+	if err != nil {
+		return repo.User{}, err
+	}
+
+	err = revokeAllTokens(ctx, qtx, id)
+	if err != nil {
+		return repo.User{}, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return repo.User{}, err
+	}
+
 	return user, nil
 }
+
+// ResetPassword replaces the password of the user identified by the name with a random temporary
+// password, which is returned so that it can be handed to the user out of band. The user has to
+// change it before using the rest of the API, and every token issued to the user is revoked.
+// If there is an error in between, the whole transaction is rolled back.
+func (s *svc) ResetPassword(ctx context.Context, name string) (string, error) {
+	password, err := randomString(temporaryPasswordBytes)
+	if err != nil {
+		return "", err
+	}
+
+	hash, err := helper.HashPassword(password)
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.repo.WithTx(tx)
+
+	arg := repo.ResetUserPasswordParams{
+		Name:         name,
+		PasswordHash: pgtype.Text{String: hash, Valid: true},
+	}
+	user, err := qtx.ResetUserPassword(ctx, arg)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", ErrUserNotFound
+		}
+		return "", err
+	}
+
+	err = revokeAllTokens(ctx, qtx, user.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return password, nil
+}
+
+// IssueRefreshToken creates a new refresh token for the user. Only the SHA-256 hash of the token is
+// stored in the database.
+func (s *svc) IssueRefreshToken(ctx context.Context, userId int64) (string, error) {
+	return issueRefreshToken(ctx, s.repo, userId)
+}
+
+// RotateRefreshToken revokes the given refresh token and returns its user together with a newly
+// issued refresh token.
+// If the given token has already been revoked, it is treated as stolen and every token of the user
+// is revoked.
+// If there is an error in between, the whole transaction is rolled back.
+func (s *svc) RotateRefreshToken(ctx context.Context, refreshToken string) (repo.User, string, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.User{}, "", err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.repo.WithTx(tx)
+
+	stored, err := qtx.FindRefreshToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return repo.User{}, "", ErrInvalidRefreshToken
+		}
+		return repo.User{}, "", err
+	}
+
+	if stored.RevokedAt.Valid {
+		err = revokeAllTokens(ctx, qtx, stored.UserID)
+		if err != nil {
+			return repo.User{}, "", err
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			return repo.User{}, "", err
+		}
+		return repo.User{}, "", ErrInvalidRefreshToken
+	}
+
+	if time.Now().After(stored.ExpiresAt.Time) {
+		return repo.User{}, "", ErrInvalidRefreshToken
+	}
+
+	updRows, err := qtx.RevokeRefreshToken(ctx, stored.TokenID)
+	if err != nil {
+		return repo.User{}, "", err
+	}
+
+	if updRows == 0 {
+		return repo.User{}, "", ErrInvalidRefreshToken
+	}
+
+	user, err := qtx.FindUserByID(ctx, stored.UserID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return repo.User{}, "", ErrInvalidRefreshToken
+		}
+		return repo.User{}, "", err
+	}
+
+	newToken, err := issueRefreshToken(ctx, qtx, user.UserID)
+	if err != nil {
+		return repo.User{}, "", err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return repo.User{}, "", err
+	}
+
+	return user, newToken, nil
+}
+
+// Logout revokes the access token, and the refresh token if it is given and belongs to the same
+// user.
+func (s *svc) Logout(ctx context.Context, token AccessToken, refreshToken string) error {
+	arg := repo.RevokeAccessTokenParams{
+		Jti:    token.ID,
+		UserID: token.UserID,
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  token.ExpiresAt,
+			Valid: true,
+		},
+	}
+	err := s.repo.RevokeAccessToken(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	stored, err := s.repo.FindRefreshToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	if stored.UserID != token.UserID {
+		return nil
+	}
+
+	_, err = s.repo.RevokeRefreshToken(ctx, stored.TokenID)
+	return err
+}
+
+// LogoutAll revokes every access and refresh token issued to the user, logging the user out of
+// all devices.
+// If there is an error in between, the whole transaction is rolled back.
+func (s *svc) LogoutAll(ctx context.Context, userId int64) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = revokeAllTokens(ctx, s.repo.WithTx(tx), userId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// IsTokenRevoked returns true if the access token has been revoked individually, or was issued
+// before all tokens of the user were revoked.
+func (s *svc) IsTokenRevoked(ctx context.Context, jti string, userId int64, issuedAt time.Time) (bool, error) {
+	arg := repo.IsTokenRevokedParams{
+		Jti:    jti,
+		UserID: userId,
+		IssuedAt: pgtype.Timestamptz{
+			Time:  issuedAt,
+			Valid: true,
+		},
+	}
+	return s.repo.IsTokenRevoked(ctx, arg)
+}
+
+// PurgeRevokedTokens removes the revoked access tokens that have expired, as they are rejected
+// for having expired anyway.
+func (s *svc) PurgeRevokedTokens(ctx context.Context) (int64, error) {
+	return s.repo.PurgeRevokedTokens(ctx)
+}
+
+// Run purges the expired revoked tokens once an hour until ctx is cancelled. It is meant to be run
+// in its own goroutine.
+func (s *svc) Run(ctx context.Context) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		_, err := s.PurgeRevokedTokens(ctx)
+		if err != nil {
+			log.Printf("failed to purge revoked tokens: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// issueRefreshToken generates a random refresh token and stores its hash using the given queries,
+// so that it can take part in an outer transaction.
+func issueRefreshToken(ctx context.Context, q *repo.Queries, userId int64) (string, error) {
+	token, err := randomString(32)
+	if err != nil {
+		return "", err
+	}
+
+	arg := repo.CreateRefreshTokenParams{
+		UserID:    userId,
+		TokenHash: hashToken(token),
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  time.Now().Add(RefreshTokenTTL),
+			Valid: true,
+		},
+	}
+	_, err = q.CreateRefreshToken(ctx, arg)
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// revokeAllTokens marks every access token issued so far to the user as revoked and revokes all of
+// the user's refresh tokens.
+func revokeAllTokens(ctx context.Context, q *repo.Queries, userId int64) error {
+	err := q.RevokeUserTokens(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	return q.RevokeUserRefreshTokens(ctx, userId)
+}
+
+// randomString returns n cryptographically random bytes encoded as a URL-safe string.
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken returns the hex encoded SHA-256 hash of the token.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
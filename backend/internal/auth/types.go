@@ -2,23 +2,66 @@ package auth
 
 import (
 	"context"
+	"time"
 
 	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+	"github.com/haobuhaoo/gossip-with-go/internal/users"
 )
 
 // Service defines the domain logic for authentication related operations.
 // It is responsible for enforcing application rules and making database calls.
 type Service interface {
-	Login(ctx context.Context, name string) (repo.User, error)
-	AuthenticateUser(ctx context.Context, id int64) (repo.User, error)
+	Login(ctx context.Context, name string, password string) (repo.User, error)
+	AuthenticateUser(ctx context.Context, id int64) (users.User, error)
+	ChangePassword(ctx context.Context, id int64, currentPassword string, newPassword string) (repo.User, error)
+	ResetPassword(ctx context.Context, name string) (string, error)
+	IssueRefreshToken(ctx context.Context, userId int64) (string, error)
+	RotateRefreshToken(ctx context.Context, refreshToken string) (repo.User, string, error)
+	Logout(ctx context.Context, token AccessToken, refreshToken string) error
+	LogoutAll(ctx context.Context, userId int64) error
+	IsTokenRevoked(ctx context.Context, jti string, userId int64, issuedAt time.Time) (bool, error)
+	PurgeRevokedTokens(ctx context.Context) (int64, error)
+	Run(ctx context.Context)
+}
+
+// AccessToken identifies a signed access token that is being revoked.
+type AccessToken struct {
+	ID        string
+	UserID    int64
+	ExpiresAt time.Time
 }
 
 // LoginRequest handles the authentication related HTTP request body for login of user.
 type LoginRequest struct {
 	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// ChangePasswordRequest handles the authentication related HTTP request body for changing the
+// password of the logged in user.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required"`
+}
+
+// RefreshRequest handles the authentication related HTTP request body for exchanging a refresh
+// token for a new pair of tokens.
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+// LogoutRequest handles the authentication related HTTP request body for logout of user.
+// The refresh token is optional, since the access token in the header is always revoked.
+type LogoutRequest struct {
+	RefreshToken string `json:"refreshToken"`
 }
 
 // LoginResponse handles the authentication HTTP response body after login.
+// MustChangePassword is true when the user has to set a new password before the rest of the API
+// can be used.
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token              string `json:"token"`
+	RefreshToken       string `json:"refresh_token"`
+	ExpiresIn          int64  `json:"expires_in"`
+	MustChangePassword bool   `json:"must_change_password"`
 }
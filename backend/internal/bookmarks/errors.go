@@ -0,0 +1,9 @@
+package bookmarks
+
+import "errors"
+
+var (
+	ErrPostNotFound     = errors.New("post not found")
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrBookmarkNotFound = errors.New("bookmark not found")
+)
@@ -0,0 +1,220 @@
+package bookmarks
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+)
+
+const (
+	InvalidPostIdMessage           = "Invalid post id"
+	InvalidCommentIdMessage        = "Invalid comment id"
+	InvalidTopicIdMessage          = "Invalid topic id"
+	InvalidPageMessage             = "Invalid limit or cursor"
+	MissingUserIDMessage           = "Missing userID"
+	SuccessfulListSavedMessage     = "Successfully listed saved posts and comments"
+	SuccessfulSavePostMessage      = "Successfully saved post"
+	SuccessfulUnsavePostMessage    = "Successfully removed saved post"
+	SuccessfulSaveCommentMessage   = "Successfully saved comment"
+	SuccessfulUnsaveCommentMessage = "Successfully removed saved comment"
+)
+
+// handler handles the bookmark related HTTP requests.
+// It is responsible for translating HTTP requests into service calls and formatting service
+// responses into HTTP responses.
+type handler struct {
+	service Service
+}
+
+// NewHandler creates a new bookmark handler.
+func NewHandler(service Service) *handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// ListSaved handles GET /api/me/saved requests.
+// It parses the optional topic, limit and cursor query strings, and passes them to the bookmark
+// service to return a page of the posts and comments saved by the user. It then serializes the page
+// and its pagination metadata into a JSON HTTP response.
+func (h *handler) ListSaved(w http.ResponseWriter, r *http.Request) {
+	var topicId int64
+	if topicStr := r.URL.Query().Get("topic"); topicStr != "" {
+		id, err := strconv.ParseInt(topicStr, 10, 64)
+		if err != nil || id < 1 {
+			helper.WriteError(w, InvalidTopicIdMessage, http.StatusBadRequest, api.CodeInvalidTopicID)
+			return
+		}
+		topicId = id
+	}
+
+	page, err := helper.ReadPage(r)
+	if err != nil {
+		helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	bookmarks, meta, err := h.service.ListSaved(r.Context(), userId, topicId, page)
+	if err != nil {
+		if err == helper.ErrInvalidCursor {
+			helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonBookmarks, err := json.Marshal(bookmarks)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonMeta, err := json.Marshal(meta)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataMetaAndMessage(jsonBookmarks, jsonMeta, SuccessfulListSavedMessage)
+	helper.Write(w, response)
+}
+
+// SavePost handles POST /api/bookmarks/posts/{postId} requests.
+// It parses the postId string, and passes it to the bookmark service to save the post for the
+// user, which then serializes the result into a JSON HTTP response.
+func (h *handler) SavePost(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "postId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidPostIdMessage, http.StatusBadRequest, api.CodeInvalidPostID)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	err = h.service.SavePost(r.Context(), userId, id)
+	if err != nil {
+		if err == ErrPostNotFound {
+			helper.WriteError(w, ErrPostNotFound.Error(), http.StatusNotFound, api.CodePostNotFound)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseMessage(SuccessfulSavePostMessage)
+	helper.Write(w, response)
+}
+
+// UnsavePost handles DELETE /api/bookmarks/posts/{postId} requests.
+// It parses the postId string, and passes it to the bookmark service to remove the post from the
+// saved content of the user, which then serializes the result into a JSON HTTP response.
+func (h *handler) UnsavePost(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "postId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidPostIdMessage, http.StatusBadRequest, api.CodeInvalidPostID)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	err = h.service.UnsavePost(r.Context(), userId, id)
+	if err != nil {
+		if err == ErrBookmarkNotFound {
+			helper.WriteError(w, ErrBookmarkNotFound.Error(), http.StatusNotFound, api.CodeBookmarkNotFound)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseMessage(SuccessfulUnsavePostMessage)
+	helper.Write(w, response)
+}
+
+// SaveComment handles POST /api/bookmarks/comments/{commentId} requests.
+// It parses the commentId string, and passes it to the bookmark service to save the comment for the
+// user, which then serializes the result into a JSON HTTP response.
+func (h *handler) SaveComment(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "commentId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidCommentIdMessage, http.StatusBadRequest, api.CodeInvalidCommentID)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	err = h.service.SaveComment(r.Context(), userId, id)
+	if err != nil {
+		if err == ErrCommentNotFound {
+			helper.WriteError(w, ErrCommentNotFound.Error(), http.StatusNotFound, api.CodeCommentNotFound)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseMessage(SuccessfulSaveCommentMessage)
+	helper.Write(w, response)
+}
+
+// UnsaveComment handles DELETE /api/bookmarks/comments/{commentId} requests.
+// It parses the commentId string, and passes it to the bookmark service to remove the comment from
+// the saved content of the user, which then serializes the result into a JSON HTTP response.
+func (h *handler) UnsaveComment(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "commentId")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidCommentIdMessage, http.StatusBadRequest, api.CodeInvalidCommentID)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	err = h.service.UnsaveComment(r.Context(), userId, id)
+	if err != nil {
+		if err == ErrBookmarkNotFound {
+			helper.WriteError(w, ErrBookmarkNotFound.Error(), http.StatusNotFound, api.CodeBookmarkNotFound)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseMessage(SuccessfulUnsaveCommentMessage)
+	helper.Write(w, response)
+}
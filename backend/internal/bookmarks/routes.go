@@ -0,0 +1,17 @@
+package bookmarks
+
+import "github.com/go-chi/chi/v5"
+
+// Routes group all bookmark related HTTP endpoints together, with the base prefix path /bookmarks.
+// The saved content of the user is listed under /me/saved, next to the other endpoints about the
+// logged in user.
+// It connects the URLS to their respective handler methods.
+func Routes(router chi.Router, h *handler) {
+	router.Get("/me/saved", h.ListSaved)
+	router.Route("/bookmarks", func(r chi.Router) {
+		r.Post("/posts/{postId}", h.SavePost)
+		r.Delete("/posts/{postId}", h.UnsavePost)
+		r.Post("/comments/{commentId}", h.SaveComment)
+		r.Delete("/comments/{commentId}", h.UnsaveComment)
+	})
+}
@@ -0,0 +1,210 @@
+package bookmarks
+
+import (
+	"context"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	"github.com/haobuhaoo/gossip-with-go/internal/mentions"
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// svc implements the Service interface.
+// It depends on the sql generated Queries type to interact with the PostgreSQL database, and on the
+// mention service to render the mentions in saved content.
+type svc struct {
+	repo     *repo.Queries
+	mentions mentions.Service
+}
+
+// NewService creates a new bookmark service.
+func NewService(repo *repo.Queries, mentions mentions.Service) Service {
+	return &svc{
+		repo:     repo,
+		mentions: mentions,
+	}
+}
+
+// SavePost saves the post given by the id for the user. Saving a post that is already saved has no
+// effect.
+func (s *svc) SavePost(ctx context.Context, userId int64, postId int64) error {
+	_, err := s.repo.FindPostAuthor(ctx, postId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrPostNotFound
+		}
+		return err
+	}
+
+	arg := repo.SavePostParams{
+		UserID: userId,
+		PostID: pgtype.Int8{Int64: postId, Valid: true},
+	}
+	return s.repo.SavePost(ctx, arg)
+}
+
+// UnsavePost removes the post given by the id from the saved content of the user.
+func (s *svc) UnsavePost(ctx context.Context, userId int64, postId int64) error {
+	arg := repo.UnsavePostParams{
+		UserID: userId,
+		PostID: pgtype.Int8{Int64: postId, Valid: true},
+	}
+	delRows, err := s.repo.UnsavePost(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	if delRows == 0 {
+		return ErrBookmarkNotFound
+	}
+	return nil
+}
+
+// SaveComment saves the comment given by the id for the user. Saving a comment that is already
+// saved has no effect.
+func (s *svc) SaveComment(ctx context.Context, userId int64, commentId int64) error {
+	_, err := s.repo.FindCommentAuthor(ctx, commentId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrCommentNotFound
+		}
+		return err
+	}
+
+	arg := repo.SaveCommentParams{
+		UserID:    userId,
+		CommentID: pgtype.Int8{Int64: commentId, Valid: true},
+	}
+	return s.repo.SaveComment(ctx, arg)
+}
+
+// UnsaveComment removes the comment given by the id from the saved content of the user.
+func (s *svc) UnsaveComment(ctx context.Context, userId int64, commentId int64) error {
+	arg := repo.UnsaveCommentParams{
+		UserID:    userId,
+		CommentID: pgtype.Int8{Int64: commentId, Valid: true},
+	}
+	delRows, err := s.repo.UnsaveComment(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	if delRows == 0 {
+		return ErrBookmarkNotFound
+	}
+	return nil
+}
+
+// ListSaved returns a page of the posts and comments saved by the user, most recently saved first,
+// together with the pagination metadata. If topicId is not 0, only the content of that topic is
+// returned. Saved content that has been deleted is hidden.
+func (s *svc) ListSaved(ctx context.Context, userId int64, topicId int64, page helper.Page) ([]Bookmark, api.PageMeta, error) {
+	topic := pgtype.Int8{Int64: topicId, Valid: topicId != 0}
+	arg := repo.ListBookmarksParams{
+		UserID:    userId,
+		TopicID:   topic,
+		PageLimit: page.Limit + 1,
+	}
+	if page.Cursor != "" {
+		var c bookmarkCursor
+		err := helper.DecodeCursor(page.Cursor, &c)
+		if err != nil {
+			return []Bookmark{}, api.PageMeta{}, err
+		}
+		arg.CursorBookmarkID = pgtype.Int8{Int64: c.BookmarkID, Valid: true}
+	}
+
+	rows, err := s.repo.ListBookmarks(ctx, arg)
+	if err != nil {
+		return []Bookmark{}, api.PageMeta{}, err
+	}
+
+	countArg := repo.CountBookmarksParams{
+		UserID:  userId,
+		TopicID: topic,
+	}
+	total, err := s.repo.CountBookmarks(ctx, countArg)
+	if err != nil {
+		return []Bookmark{}, api.PageMeta{}, err
+	}
+
+	fetched := len(rows)
+	if fetched > int(page.Limit) {
+		rows = rows[:page.Limit]
+	}
+
+	bookmarks := make([]Bookmark, 0, len(rows))
+	for _, row := range rows {
+		bookmarks = append(bookmarks, toBookmark(row))
+	}
+
+	err = s.renderBookmarks(ctx, bookmarks)
+	if err != nil {
+		return []Bookmark{}, api.PageMeta{}, err
+	}
+
+	nextCursor := ""
+	if len(bookmarks) > 0 {
+		last := bookmarks[len(bookmarks)-1]
+		nextCursor = helper.EncodeCursor(bookmarkCursor{BookmarkID: last.BookmarkID})
+	}
+
+	return bookmarks, helper.NewPageMeta(fetched, page, total, nextCursor), nil
+}
+
+// renderBookmarks renders the description of each saved post or comment, with the users that it
+// mentions linked to their profiles.
+func (s *svc) renderBookmarks(ctx context.Context, bookmarks []Bookmark) error {
+	postIds := make([]int64, 0, len(bookmarks))
+	commentIds := make([]int64, 0, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		if bookmark.CommentID != nil {
+			commentIds = append(commentIds, *bookmark.CommentID)
+		} else {
+			postIds = append(postIds, bookmark.PostID)
+		}
+	}
+
+	postUsers, err := s.mentions.ForPosts(ctx, postIds)
+	if err != nil {
+		return err
+	}
+
+	commentUsers, err := s.mentions.ForComments(ctx, commentIds)
+	if err != nil {
+		return err
+	}
+
+	for i, bookmark := range bookmarks {
+		users := postUsers[bookmark.PostID]
+		if bookmark.CommentID != nil {
+			users = commentUsers[*bookmark.CommentID]
+		}
+		bookmarks[i].DescriptionHTML = helper.RenderMarkdown(bookmark.Description, mentions.Usernames(users)...)
+	}
+	return nil
+}
+
+// toBookmark converts a saved post or comment row into the Bookmark model. The description is
+// rendered once the mentions of the page are known.
+func toBookmark(row repo.ListBookmarksRow) Bookmark {
+	bookmark := Bookmark{
+		BookmarkID:  row.BookmarkID,
+		Type:        TypePost,
+		TopicID:     row.TopicID,
+		PostID:      row.PostID,
+		Title:       row.Title,
+		Description: row.Description,
+		AuthorID:    row.AuthorID,
+		AuthorName:  row.AuthorName,
+		CreatedAt:   row.CreatedAt.Time,
+		SavedAt:     row.SavedAt.Time,
+	}
+	if row.CommentID.Valid {
+		bookmark.Type = TypeComment
+		bookmark.CommentID = &row.CommentID.Int64
+	}
+	return bookmark
+}
@@ -0,0 +1,49 @@
+package bookmarks
+
+import (
+	"context"
+	"time"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+)
+
+// Types of content that can be saved.
+const (
+	TypePost    = "post"
+	TypeComment = "comment"
+)
+
+// Service defines the domain logic for bookmark related operations.
+// It is responsible for enforcing application rules and making database calls.
+type Service interface {
+	SavePost(ctx context.Context, userId int64, postId int64) error
+	UnsavePost(ctx context.Context, userId int64, postId int64) error
+	SaveComment(ctx context.Context, userId int64, commentId int64) error
+	UnsaveComment(ctx context.Context, userId int64, commentId int64) error
+	ListSaved(ctx context.Context, userId int64, topicId int64, page helper.Page) ([]Bookmark, api.PageMeta, error)
+}
+
+// Bookmark model of a saved post or comment that is passed to the frontend. Title is the title of
+// the post, which is also the post that a saved comment belongs to, and Description is the content
+// of the saved post or comment. CommentID is omitted for saved posts.
+type Bookmark struct {
+	BookmarkID      int64     `json:"bookmark_id"`
+	Type            string    `json:"type"`
+	TopicID         int64     `json:"topic_id"`
+	PostID          int64     `json:"post_id"`
+	CommentID       *int64    `json:"comment_id,omitempty"`
+	Title           string    `json:"title"`
+	Description     string    `json:"description"`
+	DescriptionHTML string    `json:"description_html"`
+	AuthorID        int64     `json:"author_id"`
+	AuthorName      string    `json:"author_name"`
+	CreatedAt       time.Time `json:"created_at"`
+	SavedAt         time.Time `json:"saved_at"`
+}
+
+// bookmarkCursor holds the id of the last bookmark in a page, which is encoded into the opaque
+// next_cursor string.
+type bookmarkCursor struct {
+	BookmarkID int64 `json:"bookmark_id"`
+}
@@ -3,7 +3,12 @@ package comments
 import "errors"
 
 var (
-	ErrCommentNotFound = errors.New("comment not found")
-	ErrPostNotUpdated  = errors.New("post not updated")
-	ErrVoteNotFound    = errors.New("vote not found")
+	ErrCommentNotFound      = errors.New("comment not found")
+	ErrPostNotUpdated       = errors.New("post not updated")
+	ErrVoteNotFound         = errors.New("vote not found")
+	ErrPermissionDenied     = errors.New("permission denied")
+	ErrInvalidParentComment = errors.New("invalid parent comment")
+	ErrReplyTooDeep         = errors.New("reply is nested too deep")
+	ErrRestoreExpired       = errors.New("comment can no longer be restored")
+	ErrRevisionNotFound     = errors.New("revision not found")
 )
@@ -6,23 +6,32 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-playground/validator/v10"
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
 	"github.com/haobuhaoo/gossip-with-go/internal/helper"
 	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
 	"github.com/haobuhaoo/gossip-with-go/internal/posts"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const (
 	InvalidCommentIdMessage            = "Invalid comment id"
-	InvalidRequestBodyMessage          = "Required fields missing"
+	InvalidParentCommentIdMessage      = "Invalid parent comment id"
+	InvalidPageMessage                 = "Invalid limit or cursor"
+	InvalidQueryMessage                = "Query string missing"
+	InvalidSortMessage                 = "Invalid sort or time window"
+	InvalidVersionMessage              = "Invalid from or to version"
 	MissingUserIDMessage               = "Missing userID"
 	SuccessfulFindCommentByPostMessage = "Successfully listed all comments"
 	SuccessfulCreateCommentMessage     = "Successfully created comment"
 	SuccessfulUpdateCommentMessage     = "Successfully updated comment"
 	SuccessfulDeleteCommentMessage     = "Successfully deleted comment"
+	SuccessfulRestoreCommentMessage    = "Successfully restored comment"
 	SuccessfulLikeCommentMessage       = "Successfully liked comment"
 	SuccessfulDislikeCommentMessage    = "Successfully disliked comment"
 	SuccessfulRemoveCommentVoteMessage = "Successfully removed vote"
+	SuccessfulSearchCommentMessage     = "Successfully searched comment"
+	SuccessfulListRevisionsMessage     = "Successfully listed revisions"
+	SuccessfulDiffRevisionsMessage     = "Successfully compared revisions"
 )
 
 // handler handles the comment related HTTP requests.
@@ -40,26 +49,40 @@ func NewHandler(service Service) *handler {
 }
 
 // FindCommentsByPost handles GET /api/comments/all/{topicId}/{postId} requests.
-// It parses the topicId and postId string, and passes it to the comment service to return all
-// comments for that post, and serializes the result into a JSON HTTP response.
+// It parses the topicId and postId string together with the sort, t, limit and cursor query
+// strings, and passes them to the comment service to return a page of sorted top-level comments
+// for that post, each with its nested replies. It then serializes the result and its pagination
+// metadata into a JSON HTTP response.
 func (h *handler) FindCommentsByPost(w http.ResponseWriter, r *http.Request) {
 	topicIdStr := chi.URLParam(r, "topicId")
 	topicId, err := strconv.ParseInt(topicIdStr, 10, 64)
 	if err != nil {
-		helper.WriteError(w, posts.InvalidTopicIdMessage, http.StatusBadRequest)
+		helper.WriteError(w, posts.InvalidTopicIdMessage, http.StatusBadRequest, api.CodeInvalidTopicID)
 		return
 	}
 
 	postIdStr := chi.URLParam(r, "postId")
 	postId, err := strconv.ParseInt(postIdStr, 10, 64)
 	if err != nil {
-		helper.WriteError(w, posts.InvalidPostIdMessage, http.StatusBadRequest)
+		helper.WriteError(w, posts.InvalidPostIdMessage, http.StatusBadRequest, api.CodeInvalidPostID)
 		return
 	}
 
 	userId, ok := r.Context().Value("userID").(int64)
 	if !ok {
-		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest)
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	sort, err := helper.ReadSort(r)
+	if err != nil {
+		helper.WriteError(w, InvalidSortMessage, http.StatusBadRequest, api.CodeInvalidSort)
+		return
+	}
+
+	page, err := helper.ReadPage(r)
+	if err != nil {
+		helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
 		return
 	}
 
@@ -68,47 +91,59 @@ func (h *handler) FindCommentsByPost(w http.ResponseWriter, r *http.Request) {
 		TopicID: topicId,
 		UserID:  userId,
 	}
-	comments, err := h.service.FindCommentsByPost(r.Context(), req)
+	comments, meta, err := h.service.FindCommentsByPost(r.Context(), req, sort, page)
 	if err != nil {
 		if err == posts.ErrPostNotFound {
-			helper.WriteError(w, posts.InvalidPostIdMessage, http.StatusBadRequest)
+			helper.WriteError(w, posts.InvalidPostIdMessage, http.StatusBadRequest, api.CodeInvalidPostID)
+			return
+		}
+
+		if err == helper.ErrInvalidCursor {
+			helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
 			return
 		}
 
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
 	jsonComment, err := json.Marshal(comments)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
-	response := helper.ParseResponseDataAndMessage(jsonComment, SuccessfulFindCommentByPostMessage)
+	jsonMeta, err := json.Marshal(meta)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataMetaAndMessage(jsonComment, jsonMeta, SuccessfulFindCommentByPostMessage)
 	helper.Write(w, response)
 }
 
 // CreateComment handles POST /api/comments requests.
 // It reads and validates the request body, and passes it to the comment service to create the new
-// comment with a description. It then serializes the result into a JSON HTTP response.
+// comment with a description, optionally as a reply to a parent comment. It then serializes the
+// result into a JSON HTTP response.
 func (h *handler) CreateComment(w http.ResponseWriter, r *http.Request) {
 	var req CreateCommentRequest
 	err := helper.Read(r, &req)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusBadRequest)
+		helper.WriteError(w, err.Error(), http.StatusBadRequest, api.CodeMalformedBody)
 		return
 	}
 
-	err = validator.New().Struct(req)
+	err = helper.Validate(req)
 	if err != nil {
-		helper.WriteError(w, InvalidRequestBodyMessage, http.StatusBadRequest)
+		helper.WriteValidationError(w, err)
 		return
 	}
 
 	userId, ok := r.Context().Value("userID").(int64)
 	if !ok {
-		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest)
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
 		return
 	}
 
@@ -117,15 +152,38 @@ func (h *handler) CreateComment(w http.ResponseWriter, r *http.Request) {
 		PostID:      req.PostID,
 		Description: req.Description,
 	}
+	if req.ParentCommentID != nil {
+		newComment.ParentCommentID = pgtype.Int8{
+			Int64: *req.ParentCommentID,
+			Valid: true,
+		}
+	}
 	comment, err := h.service.CreateComment(r.Context(), newComment)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		if suspended, ok := helper.AsSuspendedError(err); ok {
+			helper.WriteSuspendedError(w, suspended)
+			return
+		}
+		if err == posts.ErrPostNotFound {
+			helper.WriteError(w, posts.ErrPostNotFound.Error(), http.StatusNotFound, api.CodePostNotFound)
+			return
+		}
+		if err == ErrInvalidParentComment {
+			helper.WriteError(w, InvalidParentCommentIdMessage, http.StatusBadRequest, api.CodeInvalidParentCommentID)
+			return
+		}
+		if err == ErrReplyTooDeep {
+			helper.WriteError(w, ErrReplyTooDeep.Error(), http.StatusBadRequest, api.CodeReplyTooDeep)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
 	jsonComment, err := json.Marshal(comment)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
@@ -141,49 +199,56 @@ func (h *handler) UpdateComment(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		helper.WriteError(w, InvalidCommentIdMessage, http.StatusBadRequest)
+		helper.WriteError(w, InvalidCommentIdMessage, http.StatusBadRequest, api.CodeInvalidCommentID)
 		return
 	}
 
 	var req UpdateCommentRequest
 	err = helper.Read(r, &req)
 	if err != nil {
-		helper.WriteError(w, InvalidRequestBodyMessage, http.StatusBadRequest)
+		helper.WriteError(w, err.Error(), http.StatusBadRequest, api.CodeMalformedBody)
 		return
 	}
 
-	err = validator.New().Struct(req)
+	err = helper.Validate(req)
 	if err != nil {
-		helper.WriteError(w, InvalidRequestBodyMessage, http.StatusBadRequest)
+		helper.WriteValidationError(w, err)
 		return
 	}
 
 	userId, ok := r.Context().Value("userID").(int64)
 	if !ok {
-		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest)
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
 		return
 	}
 
 	newComment := repo.UpdateCommentParams{
 		CommentID:   id,
 		PostID:      req.PostID,
-		UserID:      userId,
 		Description: req.Description,
 	}
-	comment, err := h.service.UpdateComment(r.Context(), newComment)
+	comment, err := h.service.UpdateComment(r.Context(), userId, newComment)
 	if err != nil {
+		if suspended, ok := helper.AsSuspendedError(err); ok {
+			helper.WriteSuspendedError(w, suspended)
+			return
+		}
 		if err == ErrCommentNotFound {
-			helper.WriteError(w, ErrCommentNotFound.Error(), http.StatusNotFound)
+			helper.WriteError(w, ErrCommentNotFound.Error(), http.StatusNotFound, api.CodeCommentNotFound)
+			return
+		}
+		if err == ErrPermissionDenied {
+			helper.WriteError(w, ErrPermissionDenied.Error(), http.StatusForbidden, api.CodePermissionDenied)
 			return
 		}
 
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
 	jsonComment, err := json.Marshal(comment)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
@@ -198,28 +263,28 @@ func (h *handler) DeleteComment(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		helper.WriteError(w, InvalidCommentIdMessage, http.StatusBadRequest)
+		helper.WriteError(w, InvalidCommentIdMessage, http.StatusBadRequest, api.CodeInvalidCommentID)
 		return
 	}
 
 	userId, ok := r.Context().Value("userID").(int64)
 	if !ok {
-		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest)
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
 		return
 	}
 
-	arg := repo.DeleteCommentParams{
-		CommentID: id,
-		UserID:    userId,
-	}
-	err = h.service.DeleteComment(r.Context(), arg)
+	err = h.service.DeleteComment(r.Context(), userId, id)
 	if err != nil {
 		if err == ErrCommentNotFound {
-			helper.WriteError(w, ErrCommentNotFound.Error(), http.StatusNotFound)
+			helper.WriteError(w, ErrCommentNotFound.Error(), http.StatusNotFound, api.CodeCommentNotFound)
+			return
+		}
+		if err == ErrPermissionDenied {
+			helper.WriteError(w, ErrPermissionDenied.Error(), http.StatusForbidden, api.CodePermissionDenied)
 			return
 		}
 
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
@@ -227,6 +292,56 @@ func (h *handler) DeleteComment(w http.ResponseWriter, r *http.Request) {
 	helper.Write(w, response)
 }
 
+// RestoreComment handles POST /api/comments/{id}/restore requests.
+// It parses the id string, and passes it to the comment service to restore the deleted comment. It
+// then serializes the result into a JSON HTTP response.
+func (h *handler) RestoreComment(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidCommentIdMessage, http.StatusBadRequest, api.CodeInvalidCommentID)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	comment, err := h.service.RestoreComment(r.Context(), userId, id)
+	if err != nil {
+		if suspended, ok := helper.AsSuspendedError(err); ok {
+			helper.WriteSuspendedError(w, suspended)
+			return
+		}
+		if err == ErrCommentNotFound {
+			helper.WriteError(w, ErrCommentNotFound.Error(), http.StatusNotFound, api.CodeCommentNotFound)
+			return
+		}
+		if err == ErrPermissionDenied {
+			helper.WriteError(w, ErrPermissionDenied.Error(), http.StatusForbidden, api.CodePermissionDenied)
+			return
+		}
+		if err == ErrRestoreExpired {
+			helper.WriteError(w, ErrRestoreExpired.Error(), http.StatusGone, api.CodeRestoreExpired)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonComment, err := json.Marshal(comment)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonComment, SuccessfulRestoreCommentMessage)
+	helper.Write(w, response)
+}
+
 // LikesComment handles POST /api/comments/{id}/likes requests.
 // It parses the id string and passes it to the comment service to increment a like count for that
 // specified comment, which then serializes the result into a JSON HTTP response.
@@ -234,13 +349,13 @@ func (h *handler) LikesComment(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		helper.WriteError(w, InvalidCommentIdMessage, http.StatusBadRequest)
+		helper.WriteError(w, InvalidCommentIdMessage, http.StatusBadRequest, api.CodeInvalidCommentID)
 		return
 	}
 
 	userId, ok := r.Context().Value("userID").(int64)
 	if !ok {
-		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest)
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
 		return
 	}
 
@@ -250,7 +365,16 @@ func (h *handler) LikesComment(w http.ResponseWriter, r *http.Request) {
 	}
 	err = h.service.LikesComment(r.Context(), arg)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		if suspended, ok := helper.AsSuspendedError(err); ok {
+			helper.WriteSuspendedError(w, suspended)
+			return
+		}
+		if err == ErrCommentNotFound {
+			helper.WriteError(w, ErrCommentNotFound.Error(), http.StatusNotFound, api.CodeCommentNotFound)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
@@ -265,13 +389,13 @@ func (h *handler) DislikesComment(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		helper.WriteError(w, InvalidCommentIdMessage, http.StatusBadRequest)
+		helper.WriteError(w, InvalidCommentIdMessage, http.StatusBadRequest, api.CodeInvalidCommentID)
 		return
 	}
 
 	userId, ok := r.Context().Value("userID").(int64)
 	if !ok {
-		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest)
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
 		return
 	}
 
@@ -281,7 +405,16 @@ func (h *handler) DislikesComment(w http.ResponseWriter, r *http.Request) {
 	}
 	err = h.service.DislikesComment(r.Context(), arg)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		if suspended, ok := helper.AsSuspendedError(err); ok {
+			helper.WriteSuspendedError(w, suspended)
+			return
+		}
+		if err == ErrCommentNotFound {
+			helper.WriteError(w, ErrCommentNotFound.Error(), http.StatusNotFound, api.CodeCommentNotFound)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
@@ -296,13 +429,13 @@ func (h *handler) RemoveCommentVote(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		helper.WriteError(w, InvalidCommentIdMessage, http.StatusBadRequest)
+		helper.WriteError(w, InvalidCommentIdMessage, http.StatusBadRequest, api.CodeInvalidCommentID)
 		return
 	}
 
 	userId, ok := r.Context().Value("userID").(int64)
 	if !ok {
-		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest)
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
 		return
 	}
 
@@ -312,15 +445,160 @@ func (h *handler) RemoveCommentVote(w http.ResponseWriter, r *http.Request) {
 	}
 	err = h.service.RemoveCommentVote(r.Context(), arg)
 	if err != nil {
+		if suspended, ok := helper.AsSuspendedError(err); ok {
+			helper.WriteSuspendedError(w, suspended)
+			return
+		}
 		if err == ErrVoteNotFound {
-			helper.WriteError(w, ErrVoteNotFound.Error(), http.StatusNotFound)
+			helper.WriteError(w, ErrVoteNotFound.Error(), http.StatusNotFound, api.CodeVoteNotFound)
 			return
 		}
 
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
 	response := helper.ParseResponseMessage(SuccessfulRemoveCommentVoteMessage)
 	helper.Write(w, response)
 }
+
+// SearchComment handles GET /api/comments/{topicId}/search requests.
+// It parses the topicId, query, limit and cursor strings, and passes them to the comment service
+// to run a full-text search over the comments under the specified topic, which then serializes
+// the page of ranked results and its pagination metadata into a JSON HTTP response.
+func (h *handler) SearchComment(w http.ResponseWriter, r *http.Request) {
+	topicIdStr := chi.URLParam(r, "topicId")
+	topicId, err := strconv.ParseInt(topicIdStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, posts.InvalidTopicIdMessage, http.StatusBadRequest, api.CodeInvalidTopicID)
+		return
+	}
+
+	rawQuery := r.URL.Query().Get("q")
+	if rawQuery == "" {
+		helper.WriteError(w, InvalidQueryMessage, http.StatusBadRequest, api.CodeInvalidQuery)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	page, err := helper.ReadPage(r)
+	if err != nil {
+		helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+		return
+	}
+
+	arg := repo.SearchCommentParams{
+		TopicID: topicId,
+		Query:   rawQuery,
+		UserID:  userId,
+	}
+	comments, meta, err := h.service.SearchComment(r.Context(), arg, page)
+	if err != nil {
+		if err == helper.ErrInvalidCursor {
+			helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonComment, err := json.Marshal(comments)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonMeta, err := json.Marshal(meta)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataMetaAndMessage(jsonComment, jsonMeta, SuccessfulSearchCommentMessage)
+	helper.Write(w, response)
+}
+
+// ListRevisions handles GET /api/comments/{id}/revisions requests.
+// It parses the id string, and passes it to the comment service to return all versions of the
+// comment, which then serializes the result into a JSON HTTP response.
+func (h *handler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidCommentIdMessage, http.StatusBadRequest, api.CodeInvalidCommentID)
+		return
+	}
+
+	revisions, err := h.service.ListRevisions(r.Context(), id)
+	if err != nil {
+		if err == ErrCommentNotFound {
+			helper.WriteError(w, ErrCommentNotFound.Error(), http.StatusNotFound, api.CodeCommentNotFound)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonRevisions, err := json.Marshal(revisions)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonRevisions, SuccessfulListRevisionsMessage)
+	helper.Write(w, response)
+}
+
+// DiffRevisions handles GET /api/comments/{id}/revisions/diff requests.
+// It parses the id string together with the from and to query strings, and passes them to the
+// comment service to compare the two versions of the comment, which then serializes the result
+// into a JSON HTTP response.
+func (h *handler) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidCommentIdMessage, http.StatusBadRequest, api.CodeInvalidCommentID)
+		return
+	}
+
+	from, to, err := helper.ReadVersions(r)
+	if err != nil {
+		helper.WriteError(w, InvalidVersionMessage, http.StatusBadRequest, api.CodeInvalidVersion)
+		return
+	}
+
+	diff, err := h.service.DiffRevisions(r.Context(), id, from, to)
+	if err != nil {
+		if err == ErrCommentNotFound {
+			helper.WriteError(w, ErrCommentNotFound.Error(), http.StatusNotFound, api.CodeCommentNotFound)
+			return
+		}
+		if err == ErrRevisionNotFound {
+			helper.WriteError(w, ErrRevisionNotFound.Error(), http.StatusNotFound, api.CodeRevisionNotFound)
+			return
+		}
+		if err == helper.ErrDiffTooLarge {
+			helper.WriteError(w, helper.ErrDiffTooLarge.Error(), http.StatusUnprocessableEntity, api.CodeDiffTooLarge)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonDiff, err := json.Marshal(diff)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonDiff, SuccessfulDiffRevisionsMessage)
+	helper.Write(w, response)
+}
@@ -7,8 +7,12 @@ import "github.com/go-chi/chi/v5"
 func Routes(router chi.Router, h *handler) {
 	router.Route("/comments", func(r chi.Router) {
 		r.Get("/all/{topicId}/{postId}", h.FindCommentsByPost)
+		r.Get("/{topicId}/search", h.SearchComment)
+		r.Get("/{id}/revisions", h.ListRevisions)
+		r.Get("/{id}/revisions/diff", h.DiffRevisions)
 		r.Post("/{id}/likes", h.LikesComment)
 		r.Post("/{id}/dislikes", h.DislikesComment)
+		r.Post("/{id}/restore", h.RestoreComment)
 		r.Post("/", h.CreateComment)
 		r.Put("/{id}", h.UpdateComment)
 		r.Delete("/{id}/remove", h.RemoveCommentVote)
@@ -2,66 +2,188 @@ package comments
 
 import (
 	"context"
+	"log"
 
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/audit"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	"github.com/haobuhaoo/gossip-with-go/internal/mentions"
+	"github.com/haobuhaoo/gossip-with-go/internal/notifications"
 	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
 	"github.com/haobuhaoo/gossip-with-go/internal/posts"
+	"github.com/haobuhaoo/gossip-with-go/internal/roles"
+	"github.com/haobuhaoo/gossip-with-go/internal/stream"
+	"github.com/haobuhaoo/gossip-with-go/internal/suspensions"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // svc implements the Service interface.
-// It depends on the sql generated Queries type to interact with the PostgreSQL database.
+// It depends on the sql generated Queries type to interact with the PostgreSQL database, on the
+// role service to check permissions, on the suspension service to refuse suspended users, on the
+// notification service to notify authors, on the mention service to store mentioned users, on the
+// stream service to push changes to clients, and on the audit log service to record moderator
+// actions.
 type svc struct {
-	repo *repo.Queries
-	db   *pgxpool.Pool
+	repo          *repo.Queries
+	db            *pgxpool.Pool
+	roles         roles.Service
+	suspensions   suspensions.Service
+	notifications notifications.Service
+	mentions      mentions.Service
+	stream        stream.Service
+	audit         audit.Service
 }
 
 // NewService creates a new comment service.
-func NewService(repo *repo.Queries, db *pgxpool.Pool) Service {
+func NewService(repo *repo.Queries, db *pgxpool.Pool, roles roles.Service, suspensions suspensions.Service, notifications notifications.Service, mentions mentions.Service, stream stream.Service, audit audit.Service) Service {
 	return &svc{
-		repo: repo,
-		db:   db,
+		repo:          repo,
+		db:            db,
+		roles:         roles,
+		suspensions:   suspensions,
+		notifications: notifications,
+		mentions:      mentions,
+		stream:        stream,
+		audit:         audit,
 	}
 }
 
-// FindCommentsByPost returns all comments of the given post id from the database.
-func (s *svc) FindCommentsByPost(ctx context.Context, arg repo.FindPostByIDParams) ([]Comment, error) {
+// FindCommentsByPost returns a page of top-level comments of the given post id in the given sort
+// order from the database, together with the pagination metadata. The replies of each comment are
+// nested under it in the same sort order, up to MaxReplyDepth levels, and each comment holds its
+// mentioned users.
+func (s *svc) FindCommentsByPost(ctx context.Context, arg repo.FindPostByIDParams, sort helper.Sort, page helper.Page) ([]Comment, api.PageMeta, error) {
 	_, err := s.repo.FindPostByID(ctx, arg)
 	if err != nil {
-		return []Comment{}, posts.ErrPostNotFound
+		return []Comment{}, api.PageMeta{}, posts.ErrPostNotFound
 	}
 
 	commentArg := repo.FindCommentsByPostParams{
-		PostID: arg.PostID,
-		UserID: arg.UserID,
+		Sort:      sort.Order,
+		UserID:    arg.UserID,
+		PostID:    arg.PostID,
+		Since:     sort.Since,
+		PageLimit: page.Limit + 1,
+	}
+	commentArg.CursorSortKey, commentArg.CursorUpdatedAt, commentArg.CursorCommentID, err = decodeCommentCursor(page.Cursor, sort.Order)
+	if err != nil {
+		return []Comment{}, api.PageMeta{}, err
 	}
+
 	rows, err := s.repo.FindCommentsByPost(ctx, commentArg)
 	if err != nil {
-		return []Comment{}, err
+		return []Comment{}, api.PageMeta{}, err
+	}
+
+	countArg := repo.CountCommentsByPostParams{
+		PostID: arg.PostID,
+		Since:  sort.Since,
+	}
+	total, err := s.repo.CountCommentsByPost(ctx, countArg)
+	if err != nil {
+		return []Comment{}, api.PageMeta{}, err
 	}
 
+	fetched := len(rows)
+	if fetched > int(page.Limit) {
+		rows = rows[:page.Limit]
+	}
+
+	nextCursor := ""
+	if len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = helper.EncodeCursor(commentCursor{
+			Sort:      sort.Order,
+			SortKey:   last.SortKey,
+			UpdatedAt: last.UpdatedAt.Time,
+			CommentID: last.CommentID,
+		})
+	}
+	meta := helper.NewPageMeta(fetched, page, total, nextCursor)
+
 	comments := make([]Comment, 0, len(rows))
 	for _, row := range rows {
-		comments = append(comments, Comment{
-			CommentID:   row.CommentID,
-			PostID:      row.PostID,
-			UserID:      row.UserID,
-			Username:    row.Username,
-			Description: row.Description,
-			Likes:       row.Likes,
-			Dislikes:    row.Dislikes,
-			UserVote:    row.UserVote,
-			CreatedAt:   row.CreatedAt.Time,
-			UpdatedAt:   row.UpdatedAt.Time,
-		})
+		comments = append(comments, toComment(repo.FindCommentRepliesRow(row), 0))
+	}
+
+	if len(comments) == 0 {
+		return comments, meta, nil
+	}
+
+	rootIds := make([]int64, 0, len(comments))
+	for _, comment := range comments {
+		rootIds = append(rootIds, comment.CommentID)
+	}
+
+	replyArg := repo.FindCommentRepliesParams{
+		RootIds:  rootIds,
+		MaxDepth: MaxReplyDepth,
+		UserID:   arg.UserID,
+		Sort:     sort.Order,
+	}
+	replies, err := s.repo.FindCommentReplies(ctx, replyArg)
+	if err != nil {
+		return []Comment{}, api.PageMeta{}, err
+	}
+
+	children := make(map[int64][]repo.FindCommentRepliesRow)
+	for _, reply := range replies {
+		parentId := reply.ParentCommentID.Int64
+		children[parentId] = append(children[parentId], reply)
+	}
+
+	for i := range comments {
+		comments[i].Replies = buildReplies(comments[i], children)
+	}
+
+	err = s.attachMentions(ctx, comments)
+	if err != nil {
+		return []Comment{}, api.PageMeta{}, err
 	}
-	return comments, nil
+	return comments, meta, nil
 }
 
 // CreateComment creates and returns a new comment with the given arg params. It then updates
-// the post's updated status.
+// the post's updated status. A reply must have a parent comment that belongs to the same post and
+// has not been deleted, and cannot be nested deeper than MaxReplyDepth. The author of the parent
+// comment, or of the post for a top-level comment, is then notified together with the users
+// mentioned in it, and the comment is pushed to the clients that follow the post. Users who are
+// suspended from the topic cannot comment.
 // If there is an error in between, the whole transaction is rolled back.
 func (s *svc) CreateComment(ctx context.Context, arg repo.CreateCommentParams) (repo.Comment, error) {
+	post, err := s.repo.FindPostAuthor(ctx, arg.PostID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return repo.Comment{}, posts.ErrPostNotFound
+		}
+		return repo.Comment{}, err
+	}
+
+	err = s.suspensions.CheckTopic(ctx, arg.UserID, post.TopicID)
+	if err != nil {
+		return repo.Comment{}, err
+	}
+
+	if arg.ParentCommentID.Valid {
+		parent, err := s.repo.FindCommentParent(ctx, arg.ParentCommentID.Int64)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return repo.Comment{}, ErrInvalidParentComment
+			}
+			return repo.Comment{}, err
+		}
+
+		if parent.PostID != arg.PostID || parent.DeletedAt.Valid {
+			return repo.Comment{}, ErrInvalidParentComment
+		}
+
+		if parent.Depth >= MaxReplyDepth {
+			return repo.Comment{}, ErrReplyTooDeep
+		}
+	}
+
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
 		return repo.Comment{}, err
@@ -80,14 +202,54 @@ func (s *svc) CreateComment(ctx context.Context, arg repo.CreateCommentParams) (
 		return repo.Comment{}, ErrPostNotUpdated
 	}
 
-	tx.Commit(ctx)
+	err = tx.Commit(ctx)
+	if err != nil {
+		return repo.Comment{}, err
+	}
+
+	err = s.notifyComment(ctx, comment)
+	if err != nil {
+		log.Printf("failed to notify comment %d: %v", comment.CommentID, err)
+	}
+
+	err = s.mentions.SyncComment(ctx, comment)
+	if err != nil {
+		log.Printf("failed to sync mentions of comment %d: %v", comment.CommentID, err)
+	}
+
+	s.publishComment(ctx, stream.TypeCommentCreated, comment)
 	return comment, nil
 }
 
-// UpdateComment updates an existing comment with the given arg params and returns it. It then updates
-// the post's updated status.
+// UpdateComment updates an existing comment with the given arg params and returns it. The prior
+// version of the comment is stored as a revision, and the post's updated status is updated. Only
+// the author of the comment or a user who can moderate the topic may update it, unless they are
+// suspended from the topic. Newly mentioned users are notified, the change is pushed to the clients
+// that follow the post, and updates by moderators are recorded in the audit log.
 // If there is an error in between, the whole transaction is rolled back.
-func (s *svc) UpdateComment(ctx context.Context, arg repo.UpdateCommentParams) (repo.Comment, error) {
+func (s *svc) UpdateComment(ctx context.Context, userId int64, arg repo.UpdateCommentParams) (repo.Comment, error) {
+	err := s.checkSuspended(ctx, userId, arg.CommentID)
+	if err != nil {
+		return repo.Comment{}, err
+	}
+
+	author, err := s.checkPermission(ctx, userId, arg.CommentID)
+	if err != nil {
+		return repo.Comment{}, err
+	}
+
+	moderated := author.UserID != userId
+	var before repo.Comment
+	if moderated {
+		before, err = s.repo.FindCommentSnapshot(ctx, arg.CommentID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return repo.Comment{}, ErrCommentNotFound
+			}
+			return repo.Comment{}, err
+		}
+	}
+
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
 		return repo.Comment{}, err
@@ -96,6 +258,15 @@ func (s *svc) UpdateComment(ctx context.Context, arg repo.UpdateCommentParams) (
 
 	qtx := s.repo.WithTx(tx)
 
+	revisionArg := repo.CreateCommentRevisionParams{
+		ReplacedBy: userId,
+		CommentID:  arg.CommentID,
+	}
+	err = qtx.CreateCommentRevision(ctx, revisionArg)
+	if err != nil {
+		return repo.Comment{}, err
+	}
+
 	comment, err := qtx.UpdateComment(ctx, arg)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -109,13 +280,71 @@ func (s *svc) UpdateComment(ctx context.Context, arg repo.UpdateCommentParams) (
 		return repo.Comment{}, ErrPostNotUpdated
 	}
 
-	tx.Commit(ctx)
+	if moderated {
+		err = s.audit.Record(ctx, qtx, audit.Entry{
+			ActorID:    userId,
+			Action:     audit.ActionCommentUpdate,
+			TargetType: audit.TargetComment,
+			TargetID:   comment.CommentID,
+			TopicID:    author.TopicID,
+			Before:     before,
+			After:      comment,
+		})
+		if err != nil {
+			return repo.Comment{}, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return repo.Comment{}, err
+	}
+
+	err = s.mentions.SyncComment(ctx, comment)
+	if err != nil {
+		log.Printf("failed to sync mentions of comment %d: %v", comment.CommentID, err)
+	}
+
+	s.publishComment(ctx, stream.TypeCommentUpdated, comment)
 	return comment, nil
 }
 
-// DeleteComment deletes the comment given by the id from the database.
-func (s *svc) DeleteComment(ctx context.Context, arg repo.DeleteCommentParams) error {
-	delRows, err := s.repo.DeleteComment(ctx, arg)
+// DeleteComment soft-deletes the comment given by the id. A deleted comment that has replies is
+// kept as a tombstone, so that its subthread is kept, while one without replies is hidden. The
+// comment can be restored within the restore window, and is purged once the retention period has
+// passed. Only the author of the comment or a user who can moderate the topic may delete it, and
+// deletions by moderators are recorded in the audit log.
+func (s *svc) DeleteComment(ctx context.Context, userId int64, commentId int64) error {
+	author, err := s.checkPermission(ctx, userId, commentId)
+	if err != nil {
+		return err
+	}
+
+	moderated := author.UserID != userId
+	var before repo.Comment
+	if moderated {
+		before, err = s.repo.FindCommentSnapshot(ctx, commentId)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrCommentNotFound
+			}
+			return err
+		}
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.repo.WithTx(tx)
+
+	arg := repo.DeleteCommentParams{
+		CommentID: commentId,
+		DeletedBy: pgtype.Int8{Int64: userId, Valid: true},
+	}
+	delRows, err := qtx.DeleteComment(ctx, arg)
 	if err != nil {
 		return err
 	}
@@ -124,31 +353,143 @@ func (s *svc) DeleteComment(ctx context.Context, arg repo.DeleteCommentParams) e
 		return ErrCommentNotFound
 	}
 
-	return nil
+	if moderated {
+		err = s.audit.Record(ctx, qtx, audit.Entry{
+			ActorID:    userId,
+			Action:     audit.ActionCommentDelete,
+			TargetType: audit.TargetComment,
+			TargetID:   commentId,
+			TopicID:    author.TopicID,
+			Before:     before,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RestoreComment restores the deleted comment given by the id and returns it. Authors may restore
+// the comments they deleted themselves, and users who can moderate the topic may restore any
+// comment of it, as long as the restore window has not passed and the post is not deleted. Users
+// who are suspended from the topic cannot restore comments. Restorations by moderators are
+// recorded in the audit log.
+func (s *svc) RestoreComment(ctx context.Context, userId int64, commentId int64) (repo.Comment, error) {
+	deleted, err := s.repo.FindDeletedComment(ctx, commentId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return repo.Comment{}, ErrCommentNotFound
+		}
+		return repo.Comment{}, err
+	}
+
+	err = s.suspensions.CheckTopic(ctx, userId, deleted.TopicID)
+	if err != nil {
+		return repo.Comment{}, err
+	}
+
+	moderated := deleted.UserID != userId || deleted.DeletedBy.Int64 != userId
+	if moderated {
+		allowed, err := s.roles.CanModerateTopic(ctx, userId, deleted.TopicID)
+		if err != nil {
+			return repo.Comment{}, err
+		}
+		if !allowed {
+			return repo.Comment{}, ErrPermissionDenied
+		}
+	}
+
+	if !helper.CanRestore(deleted.DeletedAt.Time) {
+		return repo.Comment{}, ErrRestoreExpired
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.Comment{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.repo.WithTx(tx)
+
+	comment, err := qtx.RestoreComment(ctx, commentId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return repo.Comment{}, ErrCommentNotFound
+		}
+		return repo.Comment{}, err
+	}
+
+	if moderated {
+		err = s.audit.Record(ctx, qtx, audit.Entry{
+			ActorID:    userId,
+			Action:     audit.ActionCommentRestore,
+			TargetType: audit.TargetComment,
+			TargetID:   comment.CommentID,
+			TopicID:    deleted.TopicID,
+			After:      comment,
+		})
+		if err != nil {
+			return repo.Comment{}, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return repo.Comment{}, err
+	}
+
+	return comment, nil
 }
 
-// LikesComment increments the like count for the specific comment by 1.
+// LikesComment increments the like count for the specific comment by 1, and notifies the author
+// of the comment. The new vote counts are pushed to the clients. Users who are suspended from the
+// topic cannot vote.
 func (s *svc) LikesComment(ctx context.Context, arg repo.LikesCommentParams) error {
-	err := s.repo.LikesComment(ctx, arg)
+	err := s.checkSuspended(ctx, arg.UserID, arg.CommentID)
 	if err != nil {
 		return err
 	}
 
+	err = s.repo.LikesComment(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	err = s.notifyVote(ctx, arg)
+	if err != nil {
+		log.Printf("failed to notify vote on comment %d: %v", arg.CommentID, err)
+	}
+
+	s.publishVote(ctx, arg.CommentID, arg.UserID)
 	return nil
 }
 
-// DislikesComment increments the dislike count for the specific comment by 1.
+// DislikesComment increments the dislike count for the specific comment by 1. The new vote counts
+// are pushed to the clients. Users who are suspended from the topic cannot vote.
 func (s *svc) DislikesComment(ctx context.Context, arg repo.DislikesCommentParams) error {
-	err := s.repo.DislikesComment(ctx, arg)
+	err := s.checkSuspended(ctx, arg.UserID, arg.CommentID)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.DislikesComment(ctx, arg)
 	if err != nil {
 		return err
 	}
 
+	s.publishVote(ctx, arg.CommentID, arg.UserID)
 	return nil
 }
 
-// RemoveCommentVote removes the user's vote for that specific comment.
+// RemoveCommentVote removes the user's vote for that specific comment. The new vote counts are
+// pushed to the clients. Users who are suspended from the topic cannot vote.
 func (s *svc) RemoveCommentVote(ctx context.Context, arg repo.RemoveCommentVoteParams) error {
+	err := s.checkSuspended(ctx, arg.UserID, arg.CommentID)
+	if err != nil {
+		return err
+	}
+
 	delRows, err := s.repo.RemoveCommentVote(ctx, arg)
 	if err != nil {
 		return err
@@ -158,5 +499,444 @@ func (s *svc) RemoveCommentVote(ctx context.Context, arg repo.RemoveCommentVoteP
 		return ErrVoteNotFound
 	}
 
+	s.publishVote(ctx, arg.CommentID, arg.UserID)
+	return nil
+}
+
+// SearchComment runs a full-text search over the comments of all posts under the specific topic,
+// excluding deleted comments. It returns a page of matched comments ordered by relevance, together
+// with the pagination metadata.
+func (s *svc) SearchComment(ctx context.Context, arg repo.SearchCommentParams, page helper.Page) ([]Comment, api.PageMeta, error) {
+	var err error
+	arg.CursorRank, arg.CursorCommentID, err = helper.DecodeSearchCursor(page.Cursor)
+	if err != nil {
+		return []Comment{}, api.PageMeta{}, err
+	}
+	arg.PageLimit = page.Limit + 1
+
+	rows, err := s.repo.SearchComment(ctx, arg)
+	if err != nil {
+		return []Comment{}, api.PageMeta{}, err
+	}
+
+	countArg := repo.CountSearchCommentParams{
+		TopicID: arg.TopicID,
+		Query:   arg.Query,
+	}
+	total, err := s.repo.CountSearchComment(ctx, countArg)
+	if err != nil {
+		return []Comment{}, api.PageMeta{}, err
+	}
+
+	fetched := len(rows)
+	if fetched > int(page.Limit) {
+		rows = rows[:page.Limit]
+	}
+
+	comments := make([]Comment, 0, len(rows))
+	for _, row := range rows {
+		comment := Comment{
+			CommentID:   row.CommentID,
+			PostID:      row.PostID,
+			UserID:      row.UserID,
+			Username:    row.Username,
+			Description: row.Description,
+			Likes:       row.Likes,
+			Dislikes:    row.Dislikes,
+			UserVote:    row.UserVote,
+			Saved:       row.Saved,
+			Edited:      row.EditCount > 0,
+			EditCount:   row.EditCount,
+			Mentions:    []mentions.User{},
+			Replies:     []Comment{},
+			Headline:    helper.SanitizeHeadline(row.Headline),
+			Rank:        row.Rank,
+			CreatedAt:   row.CreatedAt.Time,
+			UpdatedAt:   row.UpdatedAt.Time,
+		}
+		if row.ParentCommentID.Valid {
+			parentId := row.ParentCommentID.Int64
+			comment.ParentCommentID = &parentId
+		}
+		comments = append(comments, comment)
+	}
+
+	nextCursor := ""
+	if len(comments) > 0 {
+		last := comments[len(comments)-1]
+		nextCursor = helper.EncodeCursor(helper.SearchCursor{Rank: last.Rank, ID: last.CommentID})
+	}
+
+	err = s.attachMentions(ctx, comments)
+	if err != nil {
+		return []Comment{}, api.PageMeta{}, err
+	}
+	return comments, helper.NewPageMeta(fetched, page, total, nextCursor), nil
+}
+
+// ListRevisions returns all versions of the comment given by the id, from the current content to
+// the original comment. Revisions of deleted comments are hidden together with the comment.
+func (s *svc) ListRevisions(ctx context.Context, commentId int64) ([]Revision, error) {
+	current, err := s.currentRevision(ctx, commentId)
+	if err != nil {
+		return []Revision{}, err
+	}
+
+	rows, err := s.repo.ListCommentRevisions(ctx, commentId)
+	if err != nil {
+		return []Revision{}, err
+	}
+
+	revisions := make([]Revision, 0, len(rows)+1)
+	revisions = append(revisions, current)
+	for _, row := range rows {
+		revisions = append(revisions, toRevision(repo.FindCommentRevisionRow(row)))
+	}
+
+	return revisions, nil
+}
+
+// DiffRevisions returns the line-based changes of the description of the comment given by the id,
+// from one version to another. Either version may be the current content.
+func (s *svc) DiffRevisions(ctx context.Context, commentId int64, from int32, to int32) (RevisionDiff, error) {
+	current, err := s.currentRevision(ctx, commentId)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+
+	fromRevision, err := s.findRevision(ctx, commentId, from, current)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+
+	toRevision, err := s.findRevision(ctx, commentId, to, current)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+
+	description, err := helper.DiffLines(fromRevision.Description, toRevision.Description)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+
+	diff := RevisionDiff{
+		From:        from,
+		To:          to,
+		Description: description,
+	}
+	return diff, nil
+}
+
+// currentRevision returns the current content of the comment as its latest revision. Comments that
+// are deleted or belong to a deleted post are not found.
+func (s *svc) currentRevision(ctx context.Context, commentId int64) (Revision, error) {
+	_, err := s.repo.FindCommentAuthor(ctx, commentId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return Revision{}, ErrCommentNotFound
+		}
+		return Revision{}, err
+	}
+
+	comment, err := s.repo.FindCommentSnapshot(ctx, commentId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return Revision{}, ErrCommentNotFound
+		}
+		return Revision{}, err
+	}
+
+	revision := Revision{
+		Version:     comment.EditCount + 1,
+		Description: comment.Description,
+		Current:     true,
+		CreatedAt:   comment.UpdatedAt.Time,
+	}
+	return revision, nil
+}
+
+// findRevision returns the given version of the comment, which is either the current content or
+// one of its prior revisions.
+func (s *svc) findRevision(ctx context.Context, commentId int64, version int32, current Revision) (Revision, error) {
+	if version == current.Version {
+		return current, nil
+	}
+
+	arg := repo.FindCommentRevisionParams{
+		CommentID: commentId,
+		Version:   version,
+	}
+	row, err := s.repo.FindCommentRevision(ctx, arg)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return Revision{}, ErrRevisionNotFound
+		}
+		return Revision{}, err
+	}
+
+	return toRevision(row), nil
+}
+
+// checkSuspended returns a *helper.SuspendedError if the user is suspended from the topic the
+// comment belongs to.
+func (s *svc) checkSuspended(ctx context.Context, userId int64, commentId int64) error {
+	comment, err := s.repo.FindCommentAuthor(ctx, commentId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrCommentNotFound
+		}
+		return err
+	}
+
+	return s.suspensions.CheckTopic(ctx, userId, comment.TopicID)
+}
+
+// checkPermission returns the author of the comment if the user is the author or can moderate the
+// topic the comment belongs to.
+func (s *svc) checkPermission(ctx context.Context, userId int64, commentId int64) (repo.FindCommentAuthorRow, error) {
+	comment, err := s.repo.FindCommentAuthor(ctx, commentId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return repo.FindCommentAuthorRow{}, ErrCommentNotFound
+		}
+		return repo.FindCommentAuthorRow{}, err
+	}
+
+	if comment.UserID == userId {
+		return comment, nil
+	}
+
+	allowed, err := s.roles.CanModerateTopic(ctx, userId, comment.TopicID)
+	if err != nil {
+		return repo.FindCommentAuthorRow{}, err
+	}
+	if !allowed {
+		return repo.FindCommentAuthorRow{}, ErrPermissionDenied
+	}
+
+	return comment, nil
+}
+
+// notifyComment notifies the author of the parent comment of a reply, or the author of the post
+// of a top-level comment, about the new comment.
+func (s *svc) notifyComment(ctx context.Context, comment repo.Comment) error {
+	post, err := s.repo.FindPostAuthor(ctx, comment.PostID)
+	if err != nil {
+		return err
+	}
+
+	arg := repo.CreateNotificationParams{
+		UserID:    post.UserID,
+		ActorID:   comment.UserID,
+		Type:      notifications.TypePostComment,
+		TopicID:   pgtype.Int8{Int64: post.TopicID, Valid: true},
+		PostID:    pgtype.Int8{Int64: post.PostID, Valid: true},
+		CommentID: pgtype.Int8{Int64: comment.CommentID, Valid: true},
+	}
+
+	if comment.ParentCommentID.Valid {
+		parent, err := s.repo.FindCommentAuthor(ctx, comment.ParentCommentID.Int64)
+		if err != nil {
+			return err
+		}
+
+		arg.UserID = parent.UserID
+		arg.Type = notifications.TypeCommentReply
+	}
+
+	return s.notifications.Notify(ctx, arg)
+}
+
+// notifyVote notifies the author of the comment that the user liked it.
+func (s *svc) notifyVote(ctx context.Context, vote repo.LikesCommentParams) error {
+	comment, err := s.repo.FindCommentAuthor(ctx, vote.CommentID)
+	if err != nil {
+		return err
+	}
+
+	arg := repo.CreateNotificationParams{
+		UserID:    comment.UserID,
+		ActorID:   vote.UserID,
+		Type:      notifications.TypeCommentVote,
+		TopicID:   pgtype.Int8{Int64: comment.TopicID, Valid: true},
+		PostID:    pgtype.Int8{Int64: comment.PostID, Valid: true},
+		CommentID: pgtype.Int8{Int64: comment.CommentID, Valid: true},
+	}
+	return s.notifications.Notify(ctx, arg)
+}
+
+// publishComment pushes a change to the comment to the clients that follow its post.
+func (s *svc) publishComment(ctx context.Context, eventType string, comment repo.Comment) {
+	post, err := s.repo.FindPostAuthor(ctx, comment.PostID)
+	if err != nil {
+		log.Printf("failed to find post of comment %d: %v", comment.CommentID, err)
+		return
+	}
+
+	err = s.stream.Publish(ctx, stream.Event{
+		Type:      eventType,
+		TopicID:   post.TopicID,
+		PostID:    comment.PostID,
+		CommentID: &comment.CommentID,
+		UserID:    comment.UserID,
+	})
+	if err != nil {
+		log.Printf("failed to publish %s for comment %d: %v", eventType, comment.CommentID, err)
+	}
+}
+
+// publishVote pushes the new vote counts of the comment to the clients that follow its post.
+func (s *svc) publishVote(ctx context.Context, commentId int64, userId int64) {
+	counts, err := s.repo.FindCommentVoteCounts(ctx, commentId)
+	if err != nil {
+		log.Printf("failed to count votes of comment %d: %v", commentId, err)
+		return
+	}
+
+	err = s.stream.Publish(ctx, stream.Event{
+		Type:      stream.TypeCommentVoted,
+		TopicID:   counts.TopicID,
+		PostID:    counts.PostID,
+		CommentID: &counts.CommentID,
+		UserID:    userId,
+		Likes:     &counts.Likes,
+		Dislikes:  &counts.Dislikes,
+	})
+	if err != nil {
+		log.Printf("failed to publish %s for comment %d: %v", stream.TypeCommentVoted, commentId, err)
+	}
+}
+
+// toComment converts a comment row into the Comment model at the given depth of its thread. The
+// content, author and edit history of a deleted comment are hidden.
+func toComment(row repo.FindCommentRepliesRow, depth int) Comment {
+	comment := Comment{
+		CommentID:   row.CommentID,
+		PostID:      row.PostID,
+		UserID:      row.UserID,
+		Username:    row.Username,
+		Description: row.Description,
+		Likes:       row.Likes,
+		Dislikes:    row.Dislikes,
+		UserVote:    row.UserVote,
+		Saved:       row.Saved,
+		Edited:      row.EditCount > 0,
+		EditCount:   row.EditCount,
+		Depth:       depth,
+		Mentions:    []mentions.User{},
+		Replies:     []Comment{},
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+	}
+
+	if row.ParentCommentID.Valid {
+		parentId := row.ParentCommentID.Int64
+		comment.ParentCommentID = &parentId
+	}
+
+	if row.DeletedAt.Valid {
+		comment.UserID = 0
+		comment.Username = ""
+		comment.Description = DeletedCommentDescription
+		comment.Deleted = true
+		comment.Edited = false
+		comment.EditCount = 0
+	}
+
+	return comment
+}
+
+// toRevision converts a prior revision row into the Revision model.
+func toRevision(row repo.FindCommentRevisionRow) Revision {
+	revision := Revision{
+		Version:        row.Version,
+		Description:    row.Description,
+		ReplacedByName: row.ReplacedByName,
+		CreatedAt:      row.CreatedAt.Time,
+		ReplacedAt:     &row.ReplacedAt.Time,
+	}
+
+	if row.ReplacedBy.Valid {
+		replacedBy := row.ReplacedBy.Int64
+		revision.ReplacedBy = &replacedBy
+	}
+
+	return revision
+}
+
+// attachMentions sets the mentioned users of each comment and its nested replies, and renders their
+// descriptions with the mentions linked to their profiles. Deleted comments have no mentions.
+func (s *svc) attachMentions(ctx context.Context, comments []Comment) error {
+	commentIds := collectCommentIds(comments, []int64{})
+	users, err := s.mentions.ForComments(ctx, commentIds)
+	if err != nil {
+		return err
+	}
+
+	setMentions(comments, users)
 	return nil
 }
+
+// collectCommentIds appends the ids of the comments that are not deleted, and of their nested
+// replies, to ids.
+func collectCommentIds(comments []Comment, ids []int64) []int64 {
+	for _, comment := range comments {
+		if !comment.Deleted {
+			ids = append(ids, comment.CommentID)
+		}
+		ids = collectCommentIds(comment.Replies, ids)
+	}
+	return ids
+}
+
+// setMentions sets the mentioned users of the comments and their nested replies from the users
+// keyed by comment id, and renders their descriptions.
+func setMentions(comments []Comment, users map[int64][]mentions.User) {
+	for i := range comments {
+		setMentions(comments[i].Replies, users)
+
+		mentioned, ok := users[comments[i].CommentID]
+		if ok && !comments[i].Deleted {
+			comments[i].Mentions = mentioned
+		} else {
+			mentioned = nil
+		}
+		comments[i].DescriptionHTML = helper.RenderMarkdown(comments[i].Description, mentions.Usernames(mentioned)...)
+	}
+}
+
+// buildReplies returns the nested replies of the comment from the rows grouped by their parent
+// comment id.
+func buildReplies(parent Comment, children map[int64][]repo.FindCommentRepliesRow) []Comment {
+	rows := children[parent.CommentID]
+	replies := make([]Comment, 0, len(rows))
+	for _, row := range rows {
+		reply := toComment(row, parent.Depth+1)
+		reply.Replies = buildReplies(reply, children)
+		replies = append(replies, reply)
+	}
+	return replies
+}
+
+// decodeCommentCursor returns the sort keys to continue after, or invalid values for the first
+// page. A cursor from a different sort order is invalid.
+func decodeCommentCursor(cursor string, sort string) (pgtype.Float8, pgtype.Timestamptz, pgtype.Int8, error) {
+	if cursor == "" {
+		return pgtype.Float8{}, pgtype.Timestamptz{}, pgtype.Int8{}, nil
+	}
+
+	var c commentCursor
+	err := helper.DecodeCursor(cursor, &c)
+	if err != nil {
+		return pgtype.Float8{}, pgtype.Timestamptz{}, pgtype.Int8{}, err
+	}
+
+	if c.Sort != sort {
+		return pgtype.Float8{}, pgtype.Timestamptz{}, pgtype.Int8{}, helper.ErrInvalidCursor
+	}
+
+	sortKey := pgtype.Float8{Float64: c.SortKey, Valid: true}
+	updatedAt := pgtype.Timestamptz{Time: c.UpdatedAt, Valid: true}
+	commentId := pgtype.Int8{Int64: c.CommentID, Valid: true}
+	return sortKey, updatedAt, commentId, nil
+}
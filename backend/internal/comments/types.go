@@ -4,43 +4,110 @@ import (
 	"context"
 	"time"
 
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	"github.com/haobuhaoo/gossip-with-go/internal/mentions"
 	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
 )
 
 // Service defines the domain logic for comment related operations.
 // It is responsible for enforcing application rules and making database calls.
 type Service interface {
-	FindCommentsByPost(ctx context.Context, arg repo.FindPostByIDParams) ([]Comment, error)
+	FindCommentsByPost(ctx context.Context, arg repo.FindPostByIDParams, sort helper.Sort, page helper.Page) ([]Comment, api.PageMeta, error)
 	CreateComment(ctx context.Context, arg repo.CreateCommentParams) (repo.Comment, error)
-	UpdateComment(ctx context.Context, arg repo.UpdateCommentParams) (repo.Comment, error)
-	DeleteComment(ctx context.Context, arg repo.DeleteCommentParams) error
+	UpdateComment(ctx context.Context, userId int64, arg repo.UpdateCommentParams) (repo.Comment, error)
+	DeleteComment(ctx context.Context, userId int64, commentId int64) error
+	RestoreComment(ctx context.Context, userId int64, commentId int64) (repo.Comment, error)
 	LikesComment(ctx context.Context, arg repo.LikesCommentParams) error
 	DislikesComment(ctx context.Context, arg repo.DislikesCommentParams) error
 	RemoveCommentVote(ctx context.Context, arg repo.RemoveCommentVoteParams) error
+	SearchComment(ctx context.Context, arg repo.SearchCommentParams, page helper.Page) ([]Comment, api.PageMeta, error)
+	ListRevisions(ctx context.Context, commentId int64) ([]Revision, error)
+	DiffRevisions(ctx context.Context, commentId int64, from int32, to int32) (RevisionDiff, error)
 }
 
+// MaxReplyDepth is the deepest a reply can be nested under its top-level comment, so that threads
+// are listed with a bounded number of levels.
+const MaxReplyDepth = 8
+
+// DeletedCommentDescription replaces the description of a deleted comment that is kept as a
+// tombstone for its replies.
+const DeletedCommentDescription = "[deleted]"
+
 // Comment model that is passed to the frontend.
+// Description is the CommonMark source of the comment, and DescriptionHTML is its rendered and
+// sanitized HTML.
+// Saved tells whether the requesting user has saved the comment, and Mentions lists the users
+// mentioned in the description.
+// Replies are nested under their parent comment, and Depth is 0 for top-level comments.
+// Edited is set once the comment has been updated, and EditCount is the number of updates, so that
+// the comment has EditCount prior revisions.
+// Headline and Rank are only set for search results, where the headline is a snippet of the
+// description with the matched terms wrapped in <mark> tags.
 type Comment struct {
-	CommentID   int64       `json:"comment_id"`
-	PostID      int64       `json:"post_id"`
-	UserID      int64       `json:"user_id"`
-	Username    string      `json:"username"`
-	Description string      `json:"description"`
-	Likes       int64       `json:"likes"`
-	Dislikes    int64       `json:"dislikes"`
-	UserVote    interface{} `json:"user_vote"`
-	CreatedAt   time.Time   `json:"created_at"`
-	UpdatedAt   time.Time   `json:"updated_at"`
+	CommentID       int64           `json:"comment_id"`
+	PostID          int64           `json:"post_id"`
+	ParentCommentID *int64          `json:"parent_comment_id"`
+	UserID          int64           `json:"user_id"`
+	Username        string          `json:"username"`
+	Description     string          `json:"description"`
+	DescriptionHTML string          `json:"description_html"`
+	Likes           int64           `json:"likes"`
+	Dislikes        int64           `json:"dislikes"`
+	UserVote        interface{}     `json:"user_vote"`
+	Saved           bool            `json:"saved"`
+	Deleted         bool            `json:"deleted"`
+	Edited          bool            `json:"edited"`
+	EditCount       int32           `json:"edit_count"`
+	Mentions        []mentions.User `json:"mentions"`
+	Depth           int             `json:"depth"`
+	Replies         []Comment       `json:"replies"`
+	Headline        string          `json:"headline,omitempty"`
+	Rank            float32         `json:"rank,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+// Revision model of a version of a comment that is passed to the frontend.
+// Version 1 is the original comment, and the version after the last prior revision is the current
+// content. ReplacedBy and ReplacedAt are only set for prior revisions, and tell which user replaced
+// the revision with an update and when.
+type Revision struct {
+	Version        int32      `json:"version"`
+	Description    string     `json:"description"`
+	Current        bool       `json:"current"`
+	ReplacedBy     *int64     `json:"replaced_by"`
+	ReplacedByName string     `json:"replaced_by_name,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ReplacedAt     *time.Time `json:"replaced_at"`
+}
+
+// RevisionDiff model of the line-based changes between two revisions of a comment that is passed
+// to the frontend.
+type RevisionDiff struct {
+	From        int32             `json:"from"`
+	To          int32             `json:"to"`
+	Description []helper.DiffLine `json:"description"`
+}
+
+// commentCursor holds the sort order and sort keys of the last comment in a page, which is encoded
+// into the opaque next_cursor string.
+type commentCursor struct {
+	Sort      string    `json:"sort"`
+	SortKey   float64   `json:"sort_key"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CommentID int64     `json:"comment_id"`
 }
 
 // CreateCommentRequest handles the comment related HTTP request body for creation of a new comment.
 type CreateCommentRequest struct {
-	PostID      int64  `json:"postId" validate:"required,min=1"`
-	Description string `json:"description" validate:"required"`
+	PostID          int64  `json:"postId" validate:"required,min=1"`
+	ParentCommentID *int64 `json:"parentCommentId" validate:"omitempty,min=1"`
+	Description     string `json:"description" validate:"required,max=10000"`
 }
 
 // UpdateCommentRequest handles the comment related HTTP request body for updating of existing comment.
 type UpdateCommentRequest struct {
 	PostID      int64  `json:"postId" validate:"required,min=1"`
-	Description string `json:"description" validate:"required"`
+	Description string `json:"description" validate:"required,max=10000"`
 }
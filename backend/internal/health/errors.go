@@ -0,0 +1,7 @@
+package health
+
+import "errors"
+
+var (
+	ErrDraining = errors.New("server is shutting down")
+)
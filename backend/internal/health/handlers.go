@@ -0,0 +1,58 @@
+package health
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+)
+
+const (
+	DatabaseUnavailableMessage = "Database unavailable"
+	SuccessfulLiveMessage      = "Server is alive"
+	SuccessfulReadyMessage     = "Server is ready"
+)
+
+// handler handles the health check HTTP requests.
+// It is responsible for translating HTTP requests into service calls and formatting service
+// responses into HTTP responses.
+type handler struct {
+	service Service
+}
+
+// NewHandler creates a new health handler.
+func NewHandler(service Service) *handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// Live handles GET /healthz requests.
+// It reports that the server process is running, without checking its dependencies, so that it is
+// only restarted when it stops responding.
+func (h *handler) Live(w http.ResponseWriter, r *http.Request) {
+	response := helper.ParseResponseMessage(SuccessfulLiveMessage)
+	helper.Write(w, response)
+}
+
+// Ready handles GET /readyz requests.
+// It passes the request to the health service to check that the server is not shutting down and
+// that the database can be reached. It responds with 503 Service Unavailable otherwise, so that
+// load balancers stop routing requests to the server.
+func (h *handler) Ready(w http.ResponseWriter, r *http.Request) {
+	err := h.service.Ready(r.Context())
+	if err != nil {
+		if err == ErrDraining {
+			helper.WriteError(w, ErrDraining.Error(), http.StatusServiceUnavailable, api.CodeShuttingDown)
+			return
+		}
+
+		log.Printf("readiness check failed: %v", err)
+		helper.WriteError(w, DatabaseUnavailableMessage, http.StatusServiceUnavailable, api.CodeDatabaseUnavailable)
+		return
+	}
+
+	response := helper.ParseResponseMessage(SuccessfulReadyMessage)
+	helper.Write(w, response)
+}
@@ -0,0 +1,11 @@
+package health
+
+import "github.com/go-chi/chi/v5"
+
+// Routes serves the liveness check at /healthz and the readiness check at /readyz. They are
+// mounted outside of the /api group, so that load balancers can call them without logging in.
+// It connects the URLS to their respective handler methods.
+func Routes(router chi.Router, h *handler) {
+	router.Get("/healthz", h.Live)
+	router.Get("/readyz", h.Ready)
+}
@@ -0,0 +1,44 @@
+package health
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// pingTimeout is how long Ready waits for the database to answer.
+const pingTimeout = 2 * time.Second
+
+// svc implements the Service interface.
+// It depends on the connection pool to check that the database can be reached.
+type svc struct {
+	db       *pgxpool.Pool
+	draining atomic.Bool
+}
+
+// NewService creates a new health service.
+func NewService(db *pgxpool.Pool) Service {
+	return &svc{
+		db: db,
+	}
+}
+
+// Ready returns ErrDraining once the server has started shutting down, and otherwise pings the
+// database and returns the error if it cannot be reached.
+func (s *svc) Ready(ctx context.Context) error {
+	if s.draining.Load() {
+		return ErrDraining
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	return s.db.Ping(ctx)
+}
+
+// Drain marks the server as shutting down, so that Ready fails and load balancers stop routing
+// new requests to it while the requests in flight finish.
+func (s *svc) Drain() {
+	s.draining.Store(true)
+}
@@ -0,0 +1,11 @@
+package health
+
+import "context"
+
+// Service defines the domain logic for the health checks of the server.
+// It is responsible for reporting whether the server is alive, and whether it is ready to serve
+// requests, which it stops being once it starts shutting down.
+type Service interface {
+	Ready(ctx context.Context) error
+	Drain()
+}
@@ -12,6 +12,18 @@ func ParseResponseDataAndMessage(data []byte, msg string) api.Response {
 	}
 }
 
+// ParseResponseDataMetaAndMessage packages the data and its metadata into an API Response with the
+// success message.
+func ParseResponseDataMetaAndMessage(data []byte, meta []byte, msg string) api.Response {
+	return api.Response{
+		Payload: api.Payload{
+			Meta: meta,
+			Data: data,
+		},
+		Messages: []string{msg},
+	}
+}
+
 // ParseResponseMessage packages the message into an API Response.
 func ParseResponseMessage(msg string) api.Response {
 	return api.Response{
@@ -19,10 +31,12 @@ func ParseResponseMessage(msg string) api.Response {
 	}
 }
 
-// ParseErrorResponseMessage packages the error message into an API Response.
-func ParseErrorResponseMessage(msg string, code int) api.Response {
+// ParseErrorResponseMessage packages the error message, the status code and the error code from the
+// api catalogue into an API Response.
+func ParseErrorResponseMessage(msg string, status int, code string) api.Response {
 	return api.Response{
-		Messages: []string{msg},
-		ErrorCode: code,
+		Messages:  []string{msg},
+		ErrorCode: status,
+		Code:      code,
 	}
 }
@@ -25,3 +25,13 @@ func IsCheckViolation(err error) bool {
 	}
 	return false
 }
+
+// IsForeignKeyViolation returns true if the foreign key constraint is violated.
+func IsForeignKeyViolation(err error) bool {
+	const foreignKeyViolationCode = "23503"
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == foreignKeyViolationCode
+	}
+	return false
+}
@@ -2,9 +2,18 @@ package helper
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
 )
 
+// InternalErrorMessage is returned instead of the underlying error of a 500 Internal Server Error.
+const InternalErrorMessage = "Something went wrong, please try again later"
+
 // Write encodes the data as JSON and writes it to the HTTP response.
 func Write(w http.ResponseWriter, data any) {
 	w.Header().Set("Content-Type", "application/json")
@@ -19,9 +28,47 @@ func Read(r *http.Request, data any) error {
 	return decoder.Decode(data)
 }
 
-// WriteError encodes the error message and status code into a HTTP response.
-func WriteError(w http.ResponseWriter, msg string, code int) {
-	w.WriteHeader(code)
-	response := ParseErrorResponseMessage(msg, code)
+// WriteError encodes the error message, status code and the error code from the api catalogue into a
+// HTTP response.
+func WriteError(w http.ResponseWriter, msg string, status int, code string) {
+	w.WriteHeader(status)
+	response := ParseErrorResponseMessage(msg, status, code)
+	Write(w, response)
+}
+
+// WriteInternalError logs the error together with the request ID set by the RequestID middleware,
+// and encodes a generic message and the request ID into a 500 Internal Server Error HTTP response,
+// so that the underlying error is never exposed to the client but can still be traced in the logs.
+func WriteInternalError(w http.ResponseWriter, r *http.Request, err error) {
+	requestID := middleware.GetReqID(r.Context())
+	log.Printf("internal error on %s %s [%s]: %v", r.Method, r.URL.Path, requestID, err)
+
+	w.WriteHeader(http.StatusInternalServerError)
+	response := ParseErrorResponseMessage(InternalErrorMessage, http.StatusInternalServerError, api.CodeInternalError)
+	response.RequestID = requestID
+	Write(w, response)
+}
+
+// WriteValidationError encodes the fields that failed validation into a 400 Bad Request HTTP
+// response, with a message for each field so that the frontend can show them next to the inputs.
+// Errors that are not validator.ValidationErrors are encoded as a malformed body.
+func WriteValidationError(w http.ResponseWriter, err error) {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		WriteError(w, err.Error(), http.StatusBadRequest, api.CodeMalformedBody)
+		return
+	}
+
+	response := api.Response{
+		ErrorCode: http.StatusBadRequest,
+		Code:      api.CodeValidation,
+	}
+	for _, fieldError := range validationErrors {
+		detail := toFieldError(fieldError)
+		response.Messages = append(response.Messages, detail.Message)
+		response.Details = append(response.Details, detail)
+	}
+
+	w.WriteHeader(http.StatusBadRequest)
 	Write(w, response)
 }
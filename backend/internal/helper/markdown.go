@@ -0,0 +1,52 @@
+package helper
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/util"
+)
+
+// markdown renders CommonMark. Raw HTML in the source is omitted rather than passed through, and
+// mentions are linked to the profile of the user.
+var markdown = goldmark.New(
+	goldmark.WithParserOptions(parser.WithInlineParsers(util.Prioritized(&mentionParser{}, 500))),
+)
+
+// markdownPolicy only keeps the HTML that user generated content may contain. Links are marked as
+// nofollow, code blocks keep the language class of their fence, and mention links keep their
+// class.
+var markdownPolicy = newMarkdownPolicy()
+
+func newMarkdownPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#.-]+$`)).OnElements("code")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^mention$`)).OnElements("a")
+	policy.RequireNoReferrerOnFullyQualifiedLinks(true)
+	return policy
+}
+
+// RenderMarkdown renders the CommonMark source of a post or comment description into HTML that is
+// safe to insert into a page. The `@name` tokens of the given resolved mentions are rendered as
+// links. If the source cannot be rendered, it is returned escaped instead.
+func RenderMarkdown(source string, mentions ...string) string {
+	pc := parser.NewContext()
+	if len(mentions) > 0 {
+		names := make(map[string]bool, len(mentions))
+		for _, name := range mentions {
+			names[name] = true
+		}
+		pc.Set(mentionsKey, names)
+	}
+
+	var buf bytes.Buffer
+	err := markdown.Convert([]byte(source), &buf, parser.WithContext(pc))
+	if err != nil {
+		return html.EscapeString(source)
+	}
+	return markdownPolicy.Sanitize(buf.String())
+}
@@ -0,0 +1,95 @@
+package helper
+
+import (
+	"slices"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+const (
+	minUsernameLength = 3
+	maxUsernameLength = 50
+)
+
+// mentionsKey holds the set of resolved mention names while a description is rendered, and
+// parsedMentionsKey collects the mention names while a description is parsed.
+var (
+	mentionsKey       = parser.NewContextKey()
+	parsedMentionsKey = parser.NewContextKey()
+)
+
+// ParseMentions returns the distinct names of the `@name` tokens in the CommonMark source, in the
+// order they first appear. Tokens in code are ignored, and a token must not directly follow another
+// username character, so that email addresses are not taken as mentions. Trailing periods are left
+// out as sentence punctuation. Names follow the same rules as usernames at registration.
+func ParseMentions(source string) []string {
+	names := []string{}
+	pc := parser.NewContext()
+	pc.Set(parsedMentionsKey, &names)
+	markdown.Parser().Parse(text.NewReader([]byte(source)), parser.WithContext(pc))
+	return names
+}
+
+// readMention returns the name at the start of b, or an empty string if it is not a valid
+// username.
+func readMention(b []byte) string {
+	n := 0
+	for n < len(b) && isUsernameChar(b[n]) {
+		n++
+	}
+	for n > 0 && b[n-1] == '.' {
+		n--
+	}
+	if n < minUsernameLength || n > maxUsernameLength {
+		return ""
+	}
+	return string(b[:n])
+}
+
+// isUsernameChar reports whether c may appear in a username.
+func isUsernameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '.' || c == '_' || c == '-'
+}
+
+// mentionParser turns the `@name` tokens of resolved mentions into links to the profile of the
+// user, or collects the names of all tokens when the description is parsed by ParseMentions.
+type mentionParser struct{}
+
+func (p *mentionParser) Trigger() []byte {
+	return []byte{'@'}
+}
+
+func (p *mentionParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	prev := block.PrecendingCharacter()
+	if prev < 0x80 && (isUsernameChar(byte(prev)) || prev == '@') {
+		return nil
+	}
+
+	line, segment := block.PeekLine()
+	name := readMention(line[1:])
+	if name == "" {
+		return nil
+	}
+
+	if parsed, ok := pc.Get(parsedMentionsKey).(*[]string); ok {
+		if !slices.Contains(*parsed, name) {
+			*parsed = append(*parsed, name)
+		}
+		return nil
+	}
+
+	mentions, _ := pc.Get(mentionsKey).(map[string]bool)
+	if !mentions[name] {
+		return nil
+	}
+
+	link := ast.NewLink()
+	link.Destination = []byte("/users/" + name)
+	link.SetAttributeString("class", []byte("mention"))
+	link.AppendChild(link, ast.NewTextSegment(text.NewSegment(segment.Start, segment.Start+1+len(name))))
+	block.Advance(1 + len(name))
+	return link
+}
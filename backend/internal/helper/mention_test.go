@@ -0,0 +1,109 @@
+package helper
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{name: "no mentions", source: "hello world", want: []string{}},
+		{name: "single mention", source: "hello @alice", want: []string{"alice"}},
+		{name: "start of text", source: "@alice hello", want: []string{"alice"}},
+		{name: "distinct in order", source: "@bob and @alice, then @bob again", want: []string{"bob", "alice"}},
+		{name: "trailing period", source: "thanks @alice.", want: []string{"alice"}},
+		{name: "trailing periods", source: "thanks @alice...", want: []string{"alice"}},
+		{name: "inner period", source: "thanks @alice.smith", want: []string{"alice.smith"}},
+		{name: "punctuation after", source: "(@alice), @bob!", want: []string{"alice", "bob"}},
+		{name: "email address", source: "mail alice@example.com", want: []string{}},
+		{name: "double at", source: "@@alice", want: []string{}},
+		{name: "inline code", source: "run `@alice` now", want: []string{}},
+		{name: "code block", source: "```\n@alice\n```\n@bob", want: []string{"bob"}},
+		{name: "emphasis", source: "*@alice*", want: []string{"alice"}},
+		{name: "too short", source: "@al", want: []string{}},
+		{name: "shortest", source: "@ali", want: []string{"ali"}},
+		{name: "longest", source: "@" + strings.Repeat("a", maxUsernameLength), want: []string{strings.Repeat("a", maxUsernameLength)}},
+		{name: "too long", source: "@" + strings.Repeat("a", maxUsernameLength+1), want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseMentions(tt.source)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMentions(%q) = %v, want %v", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadMention(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "empty", input: "", want: ""},
+		{name: "name only", input: "alice", want: "alice"},
+		{name: "stops at space", input: "alice bob", want: "alice"},
+		{name: "stops at comma", input: "alice, hi", want: "alice"},
+		{name: "allowed characters", input: "a.b_c-1", want: "a.b_c-1"},
+		{name: "trailing period", input: "alice.", want: "alice"},
+		{name: "only periods", input: "...", want: ""},
+		{name: "below minimum", input: strings.Repeat("a", minUsernameLength-1), want: ""},
+		{name: "at minimum", input: strings.Repeat("a", minUsernameLength), want: strings.Repeat("a", minUsernameLength)},
+		{name: "too short after period", input: "ab.", want: ""},
+		{name: "at maximum", input: strings.Repeat("a", maxUsernameLength), want: strings.Repeat("a", maxUsernameLength)},
+		{name: "above maximum", input: strings.Repeat("a", maxUsernameLength+1), want: ""},
+		{name: "non ascii", input: "ålice", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := readMention([]byte(tt.input))
+			if got != tt.want {
+				t.Errorf("readMention(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownMentions(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		mentions []string
+		want     string
+	}{
+		{
+			name:     "resolved mention",
+			source:   "hi @alice",
+			mentions: []string{"alice"},
+			want:     `<a href="/users/alice" class="mention" rel="nofollow">@alice</a>`,
+		},
+		{
+			name:   "unresolved mention",
+			source: "hi @alice",
+			want:   "<p>hi @alice</p>",
+		},
+		{
+			name:     "mention in code",
+			source:   "`@alice`",
+			mentions: []string{"alice"},
+			want:     "<code>@alice</code>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RenderMarkdown(tt.source, tt.mentions...)
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("RenderMarkdown(%q) = %q, want it to contain %q", tt.source, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+var (
+	ErrInvalidLimit  = errors.New("invalid limit")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// Page holds the pagination query parameters of a list request.
+type Page struct {
+	Limit  int32
+	Cursor string
+}
+
+// ReadPage parses the optional `limit` and `cursor` query parameters of the HTTP request.
+// The limit defaults to DefaultPageLimit and must be between 1 and MaxPageLimit.
+func ReadPage(r *http.Request) (Page, error) {
+	page := Page{
+		Limit:  DefaultPageLimit,
+		Cursor: r.URL.Query().Get("cursor"),
+	}
+
+	limitStr := r.URL.Query().Get("limit")
+	if limitStr != "" {
+		limit, err := strconv.ParseInt(limitStr, 10, 32)
+		if err != nil || limit < 1 || limit > MaxPageLimit {
+			return Page{}, ErrInvalidLimit
+		}
+		page.Limit = int32(limit)
+	}
+
+	return page, nil
+}
+
+// EncodeCursor serializes the sort keys of the last item in a page into an opaque cursor string.
+func EncodeCursor(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor deserializes the opaque cursor string into v. It returns ErrInvalidCursor if the
+// cursor was not produced by EncodeCursor.
+func DecodeCursor(cursor string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+// NewPageMeta returns the pagination metadata for a page of results. Callers fetch one row more
+// than the limit, so `fetched` greater than the limit means there are more results after the page.
+func NewPageMeta(fetched int, page Page, total int64, nextCursor string) api.PageMeta {
+	hasMore := fetched > int(page.Limit)
+	if !hasMore {
+		nextCursor = ""
+	}
+
+	return api.PageMeta{
+		NextCursor: nextCursor,
+		HasMore:    hasMore,
+		Total:      total,
+	}
+}
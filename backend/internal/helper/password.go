@@ -0,0 +1,42 @@
+package helper
+
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72 // bcrypt ignores any bytes after the 72nd
+)
+
+// dummyPasswordHash is a bcrypt hash with the default cost that passwords are compared against
+// when there is no stored hash, so that checking a password takes as long whether or not the user
+// exists or has a password.
+const dummyPasswordHash = "$2a$10$3sov5C27ZWVBjKAa35jqx.BreeXvB8uMr0E6y38SWOjYgtBVBtU76"
+
+// IsValidPasswordLength returns true if the password is between MinPasswordLength and
+// MaxPasswordLength bytes long.
+func IsValidPasswordLength(password string) bool {
+	return len(password) >= MinPasswordLength && len(password) <= MaxPasswordLength
+}
+
+// HashPassword returns the bcrypt hash of the plaintext password.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword returns true if the plaintext password matches the bcrypt hash. A NULL hash means
+// that the user has no password yet, which no password matches. The password is still compared
+// against dummyPasswordHash then, so that the check takes the same time.
+func CheckPassword(hash pgtype.Text, password string) bool {
+	if !hash.Valid {
+		bcrypt.CompareHashAndPassword([]byte(dummyPasswordHash), []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash.String), []byte(password)) == nil
+}
@@ -0,0 +1,46 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestDummyPasswordHashCost(t *testing.T) {
+	cost, err := bcrypt.Cost([]byte(dummyPasswordHash))
+	if err != nil {
+		t.Fatalf("dummyPasswordHash is not a bcrypt hash: %v", err)
+	}
+	if cost != bcrypt.DefaultCost {
+		t.Errorf("dummyPasswordHash cost = %d, want %d", cost, bcrypt.DefaultCost)
+	}
+}
+
+func TestCheckPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatalf("HashPassword returned an error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		hash     pgtype.Text
+		password string
+		want     bool
+	}{
+		{name: "matching password", hash: pgtype.Text{String: hash, Valid: true}, password: "correct horse", want: true},
+		{name: "wrong password", hash: pgtype.Text{String: hash, Valid: true}, password: "wrong horse", want: false},
+		{name: "null hash", hash: pgtype.Text{}, password: "correct horse", want: false},
+		{name: "null hash with empty password", hash: pgtype.Text{}, password: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckPassword(tt.hash, tt.password)
+			if got != tt.want {
+				t.Errorf("CheckPassword(%v, %q) = %v, want %v", tt.hash.Valid, tt.password, got, tt.want)
+			}
+		})
+	}
+}
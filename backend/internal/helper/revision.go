@@ -0,0 +1,124 @@
+package helper
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Operations of a line in a diff between two revisions.
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// MaxDiffLines is the most lines of each revision that DiffLines compares once the lines they share
+// at the start and end are skipped.
+const MaxDiffLines = 1000
+
+var (
+	ErrInvalidVersion = errors.New("invalid version")
+	ErrDiffTooLarge   = errors.New("revisions are too large to compare")
+)
+
+// DiffLine is a single line of a diff, which is kept, inserted or deleted between two revisions.
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// ReadVersions parses the required `from` and `to` query parameters of a revision diff request.
+// Versions start at 1 for the original content.
+func ReadVersions(r *http.Request) (int32, int32, error) {
+	from, err := strconv.ParseInt(r.URL.Query().Get("from"), 10, 32)
+	if err != nil || from < 1 {
+		return 0, 0, ErrInvalidVersion
+	}
+
+	to, err := strconv.ParseInt(r.URL.Query().Get("to"), 10, 32)
+	if err != nil || to < 1 {
+		return 0, 0, ErrInvalidVersion
+	}
+
+	return int32(from), int32(to), nil
+}
+
+// DiffLines returns the line-based diff that turns a into b, using the longest common subsequence
+// of their lines so that unchanged lines are kept in place. The lines shared at the start and end
+// are skipped, and it returns ErrDiffTooLarge if more than MaxDiffLines lines of either side remain
+// to compare, as the comparison takes memory proportional to the product of both sides.
+func DiffLines(a string, b string) ([]DiffLine, error) {
+	oldLines := strings.Split(a, "\n")
+	newLines := strings.Split(b, "\n")
+
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	oldMiddle := oldLines[prefix : len(oldLines)-suffix]
+	newMiddle := newLines[prefix : len(newLines)-suffix]
+	if len(oldMiddle) > MaxDiffLines || len(newMiddle) > MaxDiffLines {
+		return nil, ErrDiffTooLarge
+	}
+
+	diff := make([]DiffLine, 0, max(len(oldLines), len(newLines)))
+	for _, line := range oldLines[:prefix] {
+		diff = append(diff, DiffLine{Op: DiffEqual, Text: line})
+	}
+	diff = appendLCSDiff(diff, oldMiddle, newMiddle)
+	for _, line := range oldLines[len(oldLines)-suffix:] {
+		diff = append(diff, DiffLine{Op: DiffEqual, Text: line})
+	}
+
+	return diff, nil
+}
+
+// appendLCSDiff appends the diff that turns oldLines into newLines to diff, by walking the table of
+// the longest common subsequences of their suffixes.
+func appendLCSDiff(diff []DiffLine, oldLines []string, newLines []string) []DiffLine {
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(oldLines) && j < len(newLines) {
+		switch {
+		case oldLines[i] == newLines[j]:
+			diff = append(diff, DiffLine{Op: DiffEqual, Text: oldLines[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, DiffLine{Op: DiffDelete, Text: oldLines[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{Op: DiffInsert, Text: newLines[j]})
+			j++
+		}
+	}
+	for ; i < len(oldLines); i++ {
+		diff = append(diff, DiffLine{Op: DiffDelete, Text: oldLines[i]})
+	}
+	for ; j < len(newLines); j++ {
+		diff = append(diff, DiffLine{Op: DiffInsert, Text: newLines[j]})
+	}
+
+	return diff
+}
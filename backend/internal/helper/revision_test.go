@@ -0,0 +1,165 @@
+package helper
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want []DiffLine
+	}{
+		{
+			name: "both empty",
+			a:    "",
+			b:    "",
+			want: []DiffLine{{Op: DiffEqual, Text: ""}},
+		},
+		{
+			name: "from empty",
+			a:    "",
+			b:    "one",
+			want: []DiffLine{{Op: DiffDelete, Text: ""}, {Op: DiffInsert, Text: "one"}},
+		},
+		{
+			name: "to empty",
+			a:    "one",
+			b:    "",
+			want: []DiffLine{{Op: DiffDelete, Text: "one"}, {Op: DiffInsert, Text: ""}},
+		},
+		{
+			name: "unchanged",
+			a:    "one\ntwo",
+			b:    "one\ntwo",
+			want: []DiffLine{{Op: DiffEqual, Text: "one"}, {Op: DiffEqual, Text: "two"}},
+		},
+		{
+			name: "trailing newline added",
+			a:    "one",
+			b:    "one\n",
+			want: []DiffLine{{Op: DiffEqual, Text: "one"}, {Op: DiffInsert, Text: ""}},
+		},
+		{
+			name: "trailing newline removed",
+			a:    "one\n",
+			b:    "one",
+			want: []DiffLine{{Op: DiffEqual, Text: "one"}, {Op: DiffDelete, Text: ""}},
+		},
+		{
+			name: "line changed in the middle",
+			a:    "one\ntwo\nthree",
+			b:    "one\n2\nthree",
+			want: []DiffLine{
+				{Op: DiffEqual, Text: "one"},
+				{Op: DiffDelete, Text: "two"},
+				{Op: DiffInsert, Text: "2"},
+				{Op: DiffEqual, Text: "three"},
+			},
+		},
+		{
+			name: "lines moved",
+			a:    "one\ntwo\nthree",
+			b:    "three\none\ntwo",
+			want: []DiffLine{
+				{Op: DiffInsert, Text: "three"},
+				{Op: DiffEqual, Text: "one"},
+				{Op: DiffEqual, Text: "two"},
+				{Op: DiffDelete, Text: "three"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DiffLines(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("DiffLines returned an error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffLines(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLinesLimit(t *testing.T) {
+	lines := func(prefix string, n int) string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = prefix
+		}
+		return strings.Join(out, "\n")
+	}
+
+	tests := []struct {
+		name    string
+		a       string
+		b       string
+		wantErr error
+	}{
+		{
+			name: "changed lines at the limit",
+			a:    lines("a", MaxDiffLines),
+			b:    lines("b", MaxDiffLines),
+		},
+		{
+			name:    "changed lines over the limit",
+			a:       lines("a", MaxDiffLines+1),
+			b:       lines("b", 1),
+			wantErr: ErrDiffTooLarge,
+		},
+		{
+			name: "shared lines do not count",
+			a:    lines("a", MaxDiffLines*2) + "\nold",
+			b:    lines("a", MaxDiffLines*2) + "\nnew",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DiffLines(tt.a, tt.b)
+			if err != tt.wantErr {
+				t.Errorf("DiffLines error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadVersions(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		wantFrom int32
+		wantTo   int32
+		wantErr  error
+	}{
+		{name: "from before to", query: "from=1&to=2", wantFrom: 1, wantTo: 2},
+		{name: "from after to", query: "from=3&to=1", wantFrom: 3, wantTo: 1},
+		{name: "same version", query: "from=2&to=2", wantFrom: 2, wantTo: 2},
+		{name: "missing from", query: "to=2", wantErr: ErrInvalidVersion},
+		{name: "missing to", query: "from=1", wantErr: ErrInvalidVersion},
+		{name: "empty values", query: "from=&to=", wantErr: ErrInvalidVersion},
+		{name: "zero version", query: "from=0&to=1", wantErr: ErrInvalidVersion},
+		{name: "negative version", query: "from=1&to=-1", wantErr: ErrInvalidVersion},
+		{name: "not a number", query: "from=one&to=2", wantErr: ErrInvalidVersion},
+		{name: "out of range", query: "from=1&to=4294967296", wantErr: ErrInvalidVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/revisions/diff?"+tt.query, nil)
+			from, to, err := ReadVersions(r)
+			if err != tt.wantErr {
+				t.Fatalf("ReadVersions error = %v, want %v", err, tt.wantErr)
+			}
+			if from != tt.wantFrom || to != tt.wantTo {
+				t.Errorf("ReadVersions = (%d, %d), want (%d, %d)", from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
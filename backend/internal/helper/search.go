@@ -0,0 +1,43 @@
+package helper
+
+import (
+	"html"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	headlineStartSel = "<mark>"
+	headlineStopSel  = "</mark>"
+)
+
+// SearchCursor holds the sort keys of the last result in a page of ranked search results.
+type SearchCursor struct {
+	Rank float32 `json:"rank"`
+	ID   int64   `json:"id"`
+}
+
+// DecodeSearchCursor returns the rank and id to continue after, or invalid values for the first
+// page.
+func DecodeSearchCursor(cursor string) (pgtype.Float4, pgtype.Int8, error) {
+	if cursor == "" {
+		return pgtype.Float4{}, pgtype.Int8{}, nil
+	}
+
+	var c SearchCursor
+	err := DecodeCursor(cursor, &c)
+	if err != nil {
+		return pgtype.Float4{}, pgtype.Int8{}, err
+	}
+
+	return pgtype.Float4{Float32: c.Rank, Valid: true}, pgtype.Int8{Int64: c.ID, Valid: true}, nil
+}
+
+// SanitizeHeadline escapes the snippet returned by ts_headline so that it is safe to render as
+// HTML, keeping only the <mark> tags that highlight the matched terms.
+func SanitizeHeadline(headline string) string {
+	escaped := html.EscapeString(headline)
+	escaped = strings.ReplaceAll(escaped, html.EscapeString(headlineStartSel), headlineStartSel)
+	return strings.ReplaceAll(escaped, html.EscapeString(headlineStopSel), headlineStopSel)
+}
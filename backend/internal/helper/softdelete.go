@@ -0,0 +1,12 @@
+package helper
+
+import "time"
+
+// RestoreWindow is how long a deleted post or comment can still be restored after its deletion.
+// Deleted content is kept until the purge retention period has passed, which must not be shorter.
+const RestoreWindow = 7 * 24 * time.Hour
+
+// CanRestore returns true if content deleted at deletedAt is still within the restore window.
+func CanRestore(deletedAt time.Time) bool {
+	return time.Since(deletedAt) <= RestoreWindow
+}
@@ -0,0 +1,76 @@
+package helper
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Sort orders supported by the post and comment list endpoints. SortLikes orders by the most
+// likes followed by the most recent update, and is the default.
+const (
+	SortLikes         = "likes"
+	SortNew           = "new"
+	SortTop           = "top"
+	SortHot           = "hot"
+	SortControversial = "controversial"
+	SortActive        = "active"
+)
+
+// sortWindows maps the time windows of the top sort order to how far back they reach. A zero
+// duration covers all time.
+var sortWindows = map[string]time.Duration{
+	"day":   24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+	"year":  365 * 24 * time.Hour,
+	"all":   0,
+}
+
+var ErrInvalidSort = errors.New("invalid sort")
+
+// Sort holds the sort order query parameters of a list request. Since is only set for the top
+// sort order with a time window, and excludes items created before it.
+type Sort struct {
+	Order string
+	Since pgtype.Timestamptz
+}
+
+// ReadSort parses the optional `sort` and `t` query parameters of the HTTP request.
+// The sort defaults to SortLikes, and `t` (day, week, month, year or all) is only accepted for
+// the top sort order.
+func ReadSort(r *http.Request) (Sort, error) {
+	sort := Sort{
+		Order: r.URL.Query().Get("sort"),
+	}
+
+	switch sort.Order {
+	case "":
+		sort.Order = SortLikes
+	case SortLikes, SortNew, SortTop, SortHot, SortControversial, SortActive:
+	default:
+		return Sort{}, ErrInvalidSort
+	}
+
+	window := r.URL.Query().Get("t")
+	if window == "" {
+		return sort, nil
+	}
+
+	if sort.Order != SortTop {
+		return Sort{}, ErrInvalidSort
+	}
+
+	duration, ok := sortWindows[window]
+	if !ok {
+		return Sort{}, ErrInvalidSort
+	}
+
+	if duration > 0 {
+		sort.Since = pgtype.Timestamptz{Time: time.Now().Add(-duration), Valid: true}
+	}
+
+	return sort, nil
+}
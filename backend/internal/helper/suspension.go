@@ -0,0 +1,68 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+)
+
+// Error codes returned instead of the HTTP status code when a suspended or banned user is refused.
+const (
+	ErrorCodeSuspended = 4031
+	ErrorCodeBanned    = 4032
+)
+
+// SuspendedError is returned when a suspended or banned user tries to take an action. A zero
+// TopicID means that the user is suspended from the whole forum, and a nil ExpiresAt means that the
+// user is banned permanently.
+type SuspendedError struct {
+	Reason    string
+	TopicID   int64
+	ExpiresAt *time.Time
+}
+
+// Error returns a message that explains the suspension to the user.
+func (e *SuspendedError) Error() string {
+	scope := "the forum"
+	if e.TopicID != 0 {
+		scope = fmt.Sprintf("topic %d", e.TopicID)
+	}
+
+	if e.ExpiresAt == nil {
+		return fmt.Sprintf("You are banned from %s: %s", scope, e.Reason)
+	}
+	return fmt.Sprintf("You are suspended from %s until %s: %s", scope, e.ExpiresAt.Format(time.RFC3339), e.Reason)
+}
+
+// Code returns ErrorCodeBanned for a permanent ban and ErrorCodeSuspended otherwise.
+func (e *SuspendedError) Code() int {
+	if e.ExpiresAt == nil {
+		return ErrorCodeBanned
+	}
+	return ErrorCodeSuspended
+}
+
+// AsSuspendedError returns the SuspendedError in err's chain, if any.
+func AsSuspendedError(err error) (*SuspendedError, bool) {
+	var suspended *SuspendedError
+	if errors.As(err, &suspended) {
+		return suspended, true
+	}
+	return nil, false
+}
+
+// WriteSuspendedError encodes the suspension into a 403 Forbidden HTTP response with its error code,
+// which replaces the status code in ErrorCode so that existing clients can tell it apart.
+func WriteSuspendedError(w http.ResponseWriter, err *SuspendedError) {
+	code := api.CodeSuspended
+	if err.ExpiresAt == nil {
+		code = api.CodeBanned
+	}
+
+	w.WriteHeader(http.StatusForbidden)
+	response := ParseErrorResponseMessage(err.Error(), err.Code(), code)
+	Write(w, response)
+}
@@ -0,0 +1,66 @@
+package helper
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+
+	_ "image/gif"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// ThumbnailSize is the longest side of a thumbnail in pixels.
+	ThumbnailSize = 320
+
+	// maxImagePixels bounds the images that are decoded, so that a small file that claims huge
+	// dimensions cannot exhaust the memory of the server.
+	maxImagePixels = 40_000_000
+)
+
+var ErrInvalidImage = errors.New("invalid image")
+
+// Thumbnail decodes the JPEG, PNG, GIF or WebP image and returns a JPEG thumbnail that fits within
+// ThumbnailSize, together with the width and height of the original image. Images that are
+// already small enough are re-encoded at their own size.
+func Thumbnail(data []byte) ([]byte, int, int, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, 0, ErrInvalidImage
+	}
+
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxImagePixels {
+		return nil, 0, 0, ErrInvalidImage
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, 0, ErrInvalidImage
+	}
+
+	width, height := config.Width, config.Height
+	if width > ThumbnailSize || height > ThumbnailSize {
+		if width >= height {
+			width, height = ThumbnailSize, max(1, config.Height*ThumbnailSize/config.Width)
+		} else {
+			width, height = max(1, config.Width*ThumbnailSize/config.Height), ThumbnailSize
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
+
+	var buf bytes.Buffer
+	err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return buf.Bytes(), config.Width, config.Height, nil
+}
@@ -0,0 +1,74 @@
+package helper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+)
+
+// validate is shared by all handlers, as the validator caches the rules of every struct it has
+// seen. It reports fields by their JSON name so that the names match the request body.
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	return v
+}
+
+// Validate checks the request body against its `validate` struct tags, and returns
+// validator.ValidationErrors describing every field that failed.
+func Validate(req any) error {
+	return validate.Struct(req)
+}
+
+// toFieldError converts the validator's error into a field error with a message that can be shown
+// to the user.
+func toFieldError(err validator.FieldError) api.FieldError {
+	return api.FieldError{
+		Field:   err.Field(),
+		Rule:    err.Tag(),
+		Message: fmt.Sprintf("%s %s", err.Field(), describeRule(err)),
+	}
+}
+
+// describeRule explains the rule that the field failed, in terms of characters for strings, items
+// for lists and the value itself for numbers.
+func describeRule(err validator.FieldError) string {
+	unit := ""
+	switch err.Kind() {
+	case reflect.String:
+		unit = " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = " items"
+	}
+
+	switch err.Tag() {
+	case "required", "required_if":
+		return "is required"
+	case "min", "gte":
+		return fmt.Sprintf("must be at least %s%s", err.Param(), unit)
+	case "max", "lte":
+		return fmt.Sprintf("must be at most %s%s", err.Param(), unit)
+	case "len":
+		return fmt.Sprintf("must be exactly %s%s", err.Param(), unit)
+	case "oneof":
+		return fmt.Sprintf("must be one of %s", strings.Join(strings.Fields(err.Param()), ", "))
+	case "unique":
+		return "must not contain duplicates"
+	default:
+		return "is invalid"
+	}
+}
@@ -0,0 +1,7 @@
+package mentions
+
+import "errors"
+
+var (
+	ErrInvalidPrefix = errors.New("invalid prefix")
+)
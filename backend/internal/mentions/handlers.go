@@ -0,0 +1,112 @@
+package mentions
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+)
+
+const (
+	InvalidPageMessage            = "Invalid limit or cursor"
+	InvalidLimitMessage           = "Invalid limit"
+	MissingUserIDMessage          = "Missing userID"
+	SuccessfulListMentionsMessage = "Successfully listed mentions"
+	SuccessfulAutocompleteMessage = "Successfully listed matching users"
+)
+
+// handler handles the mention related HTTP requests.
+// It is responsible for translating HTTP requests into service calls and formatting service
+// responses into HTTP responses.
+type handler struct {
+	service Service
+}
+
+// NewHandler creates a new mention handler.
+func NewHandler(service Service) *handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// ListMentions handles GET /api/me/mentions requests.
+// It parses the optional limit and cursor query strings, and passes them to the mention service to
+// return a page of the posts and comments that mention the user. It then serializes the page and
+// its pagination metadata into a JSON HTTP response.
+func (h *handler) ListMentions(w http.ResponseWriter, r *http.Request) {
+	page, err := helper.ReadPage(r)
+	if err != nil {
+		helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	mentions, meta, err := h.service.ListMentions(r.Context(), userId, page)
+	if err != nil {
+		if err == helper.ErrInvalidCursor {
+			helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonMentions, err := json.Marshal(mentions)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonMeta, err := json.Marshal(meta)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataMetaAndMessage(jsonMentions, jsonMeta, SuccessfulListMentionsMessage)
+	helper.Write(w, response)
+}
+
+// Autocomplete handles GET /api/mentions/autocomplete requests.
+// It parses the q and optional limit query strings, and passes them to the mention service to
+// return the users whose name starts with q, which then serializes the result into a JSON HTTP
+// response.
+func (h *handler) Autocomplete(w http.ResponseWriter, r *http.Request) {
+	limit := int64(DefaultAutocompleteLimit)
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.ParseInt(limitStr, 10, 32)
+		if err != nil || l < 1 || l > MaxAutocompleteLimit {
+			helper.WriteError(w, InvalidLimitMessage, http.StatusBadRequest, api.CodeInvalidLimit)
+			return
+		}
+		limit = l
+	}
+
+	users, err := h.service.Autocomplete(r.Context(), r.URL.Query().Get("q"), int32(limit))
+	if err != nil {
+		if err == ErrInvalidPrefix {
+			helper.WriteError(w, ErrInvalidPrefix.Error(), http.StatusBadRequest, api.CodeInvalidPrefix)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonUsers, err := json.Marshal(users)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonUsers, SuccessfulAutocompleteMessage)
+	helper.Write(w, response)
+}
@@ -0,0 +1,14 @@
+package mentions
+
+import "github.com/go-chi/chi/v5"
+
+// Routes group all mention related HTTP endpoints together, with the base prefix path /mentions.
+// The mentions of the user are listed under /me/mentions, next to the other endpoints about the
+// logged in user.
+// It connects the URLS to their respective handler methods.
+func Routes(router chi.Router, h *handler) {
+	router.Get("/me/mentions", h.ListMentions)
+	router.Route("/mentions", func(r chi.Router) {
+		r.Get("/autocomplete", h.Autocomplete)
+	})
+}
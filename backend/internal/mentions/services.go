@@ -0,0 +1,283 @@
+package mentions
+
+import (
+	"context"
+	"log"
+	"regexp"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	"github.com/haobuhaoo/gossip-with-go/internal/notifications"
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var prefixRegex = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,50}$`)
+
+// svc implements the Service interface.
+// It depends on the sql generated Queries type to interact with the PostgreSQL database, and on the
+// notification service to notify mentioned users.
+type svc struct {
+	repo          *repo.Queries
+	notifications notifications.Service
+}
+
+// NewService creates a new mention service.
+func NewService(repo *repo.Queries, notifications notifications.Service) Service {
+	return &svc{
+		repo:          repo,
+		notifications: notifications,
+	}
+}
+
+// SyncPost stores the users mentioned in the description of the post, and removes the mentions of
+// users who are no longer mentioned. Names that do not belong to a user are ignored. Users who are
+// newly mentioned are notified, except for the author of the post.
+func (s *svc) SyncPost(ctx context.Context, post repo.Post) error {
+	arg := repo.SyncPostMentionsParams{
+		Names:  helper.ParseMentions(post.Description),
+		PostID: pgtype.Int8{Int64: post.PostID, Valid: true},
+	}
+	userIds, err := s.repo.SyncPostMentions(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	notifyArg := repo.CreateNotificationParams{
+		ActorID: post.UserID,
+		Type:    notifications.TypeMention,
+		TopicID: pgtype.Int8{Int64: post.TopicID, Valid: true},
+		PostID:  pgtype.Int8{Int64: post.PostID, Valid: true},
+	}
+	s.notify(ctx, userIds, notifyArg)
+	return nil
+}
+
+// SyncComment stores the users mentioned in the description of the comment, and removes the
+// mentions of users who are no longer mentioned. Names that do not belong to a user are ignored.
+// Users who are newly mentioned are notified, except for the author of the comment.
+func (s *svc) SyncComment(ctx context.Context, comment repo.Comment) error {
+	post, err := s.repo.FindPostAuthor(ctx, comment.PostID)
+	if err != nil {
+		return err
+	}
+
+	arg := repo.SyncCommentMentionsParams{
+		Names:     helper.ParseMentions(comment.Description),
+		CommentID: pgtype.Int8{Int64: comment.CommentID, Valid: true},
+	}
+	userIds, err := s.repo.SyncCommentMentions(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	notifyArg := repo.CreateNotificationParams{
+		ActorID:   comment.UserID,
+		Type:      notifications.TypeMention,
+		TopicID:   pgtype.Int8{Int64: post.TopicID, Valid: true},
+		PostID:    pgtype.Int8{Int64: comment.PostID, Valid: true},
+		CommentID: pgtype.Int8{Int64: comment.CommentID, Valid: true},
+	}
+	s.notify(ctx, userIds, notifyArg)
+	return nil
+}
+
+// ForPosts returns the users mentioned in each of the posts given by the ids, keyed by post id.
+func (s *svc) ForPosts(ctx context.Context, postIds []int64) (map[int64][]User, error) {
+	if len(postIds) == 0 {
+		return map[int64][]User{}, nil
+	}
+
+	rows, err := s.repo.ListPostMentions(ctx, postIds)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make(map[int64][]User)
+	for _, row := range rows {
+		users[row.PostID.Int64] = append(users[row.PostID.Int64], User{UserID: row.UserID, Username: row.Name})
+	}
+	return users, nil
+}
+
+// ForComments returns the users mentioned in each of the comments given by the ids, keyed by
+// comment id.
+func (s *svc) ForComments(ctx context.Context, commentIds []int64) (map[int64][]User, error) {
+	if len(commentIds) == 0 {
+		return map[int64][]User{}, nil
+	}
+
+	rows, err := s.repo.ListCommentMentions(ctx, commentIds)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make(map[int64][]User)
+	for _, row := range rows {
+		users[row.CommentID.Int64] = append(users[row.CommentID.Int64], User{UserID: row.UserID, Username: row.Name})
+	}
+	return users, nil
+}
+
+// Render renders the description of a post or comment that has not been saved yet, linking the
+// mentions of names that belong to a user.
+func (s *svc) Render(ctx context.Context, description string) (string, error) {
+	names := helper.ParseMentions(description)
+	if len(names) == 0 {
+		return helper.RenderMarkdown(description), nil
+	}
+
+	rows, err := s.repo.FindUsersByNames(ctx, names)
+	if err != nil {
+		return "", err
+	}
+
+	found := make([]string, 0, len(rows))
+	for _, row := range rows {
+		found = append(found, row.Name)
+	}
+	return helper.RenderMarkdown(description, found...), nil
+}
+
+// ListMentions returns a page of the posts and comments that mention the user, most recent mention
+// first, together with the pagination metadata. Content that has been deleted is hidden.
+func (s *svc) ListMentions(ctx context.Context, userId int64, page helper.Page) ([]Mention, api.PageMeta, error) {
+	arg := repo.ListUserMentionsParams{
+		UserID:    userId,
+		PageLimit: page.Limit + 1,
+	}
+	if page.Cursor != "" {
+		var c mentionCursor
+		err := helper.DecodeCursor(page.Cursor, &c)
+		if err != nil {
+			return []Mention{}, api.PageMeta{}, err
+		}
+		arg.CursorMentionID = pgtype.Int8{Int64: c.MentionID, Valid: true}
+	}
+
+	rows, err := s.repo.ListUserMentions(ctx, arg)
+	if err != nil {
+		return []Mention{}, api.PageMeta{}, err
+	}
+
+	total, err := s.repo.CountUserMentions(ctx, userId)
+	if err != nil {
+		return []Mention{}, api.PageMeta{}, err
+	}
+
+	fetched := len(rows)
+	if fetched > int(page.Limit) {
+		rows = rows[:page.Limit]
+	}
+
+	mentions := make([]Mention, 0, len(rows))
+	for _, row := range rows {
+		mentions = append(mentions, toMention(row))
+	}
+
+	err = s.renderMentions(ctx, mentions)
+	if err != nil {
+		return []Mention{}, api.PageMeta{}, err
+	}
+
+	nextCursor := ""
+	if len(mentions) > 0 {
+		last := mentions[len(mentions)-1]
+		nextCursor = helper.EncodeCursor(mentionCursor{MentionID: last.MentionID})
+	}
+
+	return mentions, helper.NewPageMeta(fetched, page, total, nextCursor), nil
+}
+
+// Autocomplete returns up to limit users whose name starts with the given prefix, ignoring case,
+// with the shortest names first. The prefix must only hold characters that are allowed in
+// usernames.
+func (s *svc) Autocomplete(ctx context.Context, prefix string, limit int32) ([]User, error) {
+	if !prefixRegex.MatchString(prefix) {
+		return []User{}, ErrInvalidPrefix
+	}
+
+	arg := repo.AutocompleteUsersParams{
+		Prefix:    prefix,
+		PageLimit: limit,
+	}
+	rows, err := s.repo.AutocompleteUsers(ctx, arg)
+	if err != nil {
+		return []User{}, err
+	}
+
+	users := make([]User, 0, len(rows))
+	for _, row := range rows {
+		users = append(users, User{UserID: row.UserID, Username: row.Name})
+	}
+	return users, nil
+}
+
+// notify sends the notification to each of the mentioned users, other than the actor.
+func (s *svc) notify(ctx context.Context, userIds []int64, arg repo.CreateNotificationParams) {
+	for _, userId := range userIds {
+		if userId == arg.ActorID {
+			continue
+		}
+
+		arg.UserID = userId
+		err := s.notifications.Notify(ctx, arg)
+		if err != nil {
+			log.Printf("failed to notify mention of user %d: %v", userId, err)
+		}
+	}
+}
+
+// renderMentions renders the description of each mentioning post or comment, with the users that it
+// mentions linked to their profiles.
+func (s *svc) renderMentions(ctx context.Context, mentions []Mention) error {
+	postIds := make([]int64, 0, len(mentions))
+	commentIds := make([]int64, 0, len(mentions))
+	for _, mention := range mentions {
+		if mention.CommentID != nil {
+			commentIds = append(commentIds, *mention.CommentID)
+		} else {
+			postIds = append(postIds, mention.PostID)
+		}
+	}
+
+	postUsers, err := s.ForPosts(ctx, postIds)
+	if err != nil {
+		return err
+	}
+
+	commentUsers, err := s.ForComments(ctx, commentIds)
+	if err != nil {
+		return err
+	}
+
+	for i, mention := range mentions {
+		users := postUsers[mention.PostID]
+		if mention.CommentID != nil {
+			users = commentUsers[*mention.CommentID]
+		}
+		mentions[i].DescriptionHTML = helper.RenderMarkdown(mention.Description, Usernames(users)...)
+	}
+	return nil
+}
+
+// toMention converts a mentioning post or comment row into the Mention model. The description is
+// rendered once the mentions of the page are known.
+func toMention(row repo.ListUserMentionsRow) Mention {
+	mention := Mention{
+		MentionID:   row.MentionID,
+		Type:        TypePost,
+		TopicID:     row.TopicID,
+		PostID:      row.PostID,
+		Title:       row.Title,
+		Description: row.Description,
+		AuthorID:    row.AuthorID,
+		AuthorName:  row.AuthorName,
+		CreatedAt:   row.CreatedAt.Time,
+	}
+	if row.CommentID.Valid {
+		mention.Type = TypeComment
+		mention.CommentID = &row.CommentID.Int64
+	}
+	return mention
+}
@@ -0,0 +1,74 @@
+package mentions
+
+import (
+	"context"
+	"time"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+)
+
+// Types of content that a user can be mentioned in.
+const (
+	TypePost    = "post"
+	TypeComment = "comment"
+)
+
+// Number of autocomplete suggestions that are returned by default, and at most.
+const (
+	DefaultAutocompleteLimit = 10
+	MaxAutocompleteLimit     = 25
+)
+
+// Service defines the domain logic for mention related operations.
+// It is responsible for enforcing application rules and making database calls.
+type Service interface {
+	SyncPost(ctx context.Context, post repo.Post) error
+	SyncComment(ctx context.Context, comment repo.Comment) error
+	ForPosts(ctx context.Context, postIds []int64) (map[int64][]User, error)
+	ForComments(ctx context.Context, commentIds []int64) (map[int64][]User, error)
+	Render(ctx context.Context, description string) (string, error)
+	ListMentions(ctx context.Context, userId int64, page helper.Page) ([]Mention, api.PageMeta, error)
+	Autocomplete(ctx context.Context, prefix string, limit int32) ([]User, error)
+}
+
+// User model of a mentioned user that is passed to the frontend, which is also used for the
+// autocomplete suggestions.
+type User struct {
+	UserID   int64  `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// Usernames returns the names of the users, which are the mentions to link when rendering the
+// content that mentions them.
+func Usernames(users []User) []string {
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		names = append(names, user.Username)
+	}
+	return names
+}
+
+// Mention model of a post or comment that mentions the user that is passed to the frontend. Title is
+// the title of the post, which is also the post that a comment belongs to, and Description is the
+// content of the post or comment. CommentID is omitted for mentions in posts.
+type Mention struct {
+	MentionID       int64     `json:"mention_id"`
+	Type            string    `json:"type"`
+	TopicID         int64     `json:"topic_id"`
+	PostID          int64     `json:"post_id"`
+	CommentID       *int64    `json:"comment_id,omitempty"`
+	Title           string    `json:"title"`
+	Description     string    `json:"description"`
+	DescriptionHTML string    `json:"description_html"`
+	AuthorID        int64     `json:"author_id"`
+	AuthorName      string    `json:"author_name"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// mentionCursor holds the id of the last mention in a page, which is encoded into the opaque
+// next_cursor string.
+type mentionCursor struct {
+	MentionID int64 `json:"mention_id"`
+}
@@ -0,0 +1,215 @@
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+)
+
+const (
+	InvalidNotificationIdMessage       = "Invalid notification id"
+	InvalidUnreadMessage               = "Invalid unread filter"
+	InvalidPageMessage                 = "Invalid limit or cursor"
+	MissingUserIDMessage               = "Missing userID"
+	SuccessfulListNotificationsMessage = "Successfully listed all notifications"
+	SuccessfulMarkReadMessage          = "Successfully marked notification as read"
+	SuccessfulMarkAllReadMessage       = "Successfully marked all notifications as read"
+	SuccessfulListPreferencesMessage   = "Successfully listed notification preferences"
+	SuccessfulUpdatePreferencesMessage = "Successfully updated notification preferences"
+)
+
+// handler handles the notification related HTTP requests.
+// It is responsible for translating HTTP requests into service calls and formatting service
+// responses into HTTP responses.
+type handler struct {
+	service Service
+}
+
+// NewHandler creates a new notification handler.
+func NewHandler(service Service) *handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// ListNotifications handles GET /api/notifications requests.
+// It parses the optional unread, limit and cursor query strings, and passes them to the
+// notification service to return a page of the user's notifications. It then serializes the page
+// and its pagination metadata into a JSON HTTP response.
+func (h *handler) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	unreadOnly := false
+	if unreadStr := r.URL.Query().Get("unread"); unreadStr != "" {
+		unread, err := strconv.ParseBool(unreadStr)
+		if err != nil {
+			helper.WriteError(w, InvalidUnreadMessage, http.StatusBadRequest, api.CodeInvalidUnread)
+			return
+		}
+		unreadOnly = unread
+	}
+
+	page, err := helper.ReadPage(r)
+	if err != nil {
+		helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	notifications, meta, err := h.service.ListNotifications(r.Context(), userId, unreadOnly, page)
+	if err != nil {
+		if err == helper.ErrInvalidCursor {
+			helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonNotifications, err := json.Marshal(notifications)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonMeta, err := json.Marshal(meta)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataMetaAndMessage(jsonNotifications, jsonMeta, SuccessfulListNotificationsMessage)
+	helper.Write(w, response)
+}
+
+// MarkRead handles PUT /api/notifications/{id}/read requests.
+// It parses the id string, and passes it to the notification service to mark the notification as
+// read, which then serializes the result into a JSON HTTP response.
+func (h *handler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidNotificationIdMessage, http.StatusBadRequest, api.CodeInvalidNotificationID)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	err = h.service.MarkRead(r.Context(), userId, id)
+	if err != nil {
+		if err == ErrNotificationNotFound {
+			helper.WriteError(w, ErrNotificationNotFound.Error(), http.StatusNotFound, api.CodeNotificationNotFound)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseMessage(SuccessfulMarkReadMessage)
+	helper.Write(w, response)
+}
+
+// MarkAllRead handles PUT /api/notifications/read requests.
+// It passes the user id to the notification service to mark all unread notifications as read,
+// which then serializes the number of marked notifications into a JSON HTTP response.
+func (h *handler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	count, err := h.service.MarkAllRead(r.Context(), userId)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonCount, err := json.Marshal(map[string]int64{"marked": count})
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonCount, SuccessfulMarkAllReadMessage)
+	helper.Write(w, response)
+}
+
+// ListPreferences handles GET /api/notifications/preferences requests.
+// It passes the user id to the notification service to return whether the user receives each type
+// of notification, and serializes the result into a JSON HTTP response.
+func (h *handler) ListPreferences(w http.ResponseWriter, r *http.Request) {
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	prefs, err := h.service.ListPreferences(r.Context(), userId)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonPrefs, err := json.Marshal(prefs)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonPrefs, SuccessfulListPreferencesMessage)
+	helper.Write(w, response)
+}
+
+// UpdatePreferences handles PUT /api/notifications/preferences requests.
+// It reads and validates the request body, and passes it to the notification service to turn the
+// given notification types on or off. It then serializes the resulting preferences into a JSON
+// HTTP response.
+func (h *handler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	var req UpdatePreferencesRequest
+	err := helper.Read(r, &req)
+	if err != nil {
+		helper.WriteError(w, err.Error(), http.StatusBadRequest, api.CodeMalformedBody)
+		return
+	}
+
+	err = helper.Validate(req)
+	if err != nil {
+		helper.WriteValidationError(w, err)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	prefs, err := h.service.UpdatePreferences(r.Context(), userId, req.Preferences)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonPrefs, err := json.Marshal(prefs)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonPrefs, SuccessfulUpdatePreferencesMessage)
+	helper.Write(w, response)
+}
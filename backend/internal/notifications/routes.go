@@ -0,0 +1,16 @@
+package notifications
+
+import "github.com/go-chi/chi/v5"
+
+// Routes group all notification related HTTP endpoints together, with the base prefix path
+// /notifications.
+// It connects the URLS to their respective handler methods.
+func Routes(router chi.Router, h *handler) {
+	router.Route("/notifications", func(r chi.Router) {
+		r.Get("/", h.ListNotifications)
+		r.Put("/read", h.MarkAllRead)
+		r.Put("/{id}/read", h.MarkRead)
+		r.Get("/preferences", h.ListPreferences)
+		r.Put("/preferences", h.UpdatePreferences)
+	})
+}
@@ -0,0 +1,195 @@
+package notifications
+
+import (
+	"context"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// svc implements the Service interface.
+// It depends on the sql generated Queries type to interact with the PostgreSQL database.
+type svc struct {
+	repo *repo.Queries
+	db   *pgxpool.Pool
+}
+
+// NewService creates a new notification service.
+func NewService(repo *repo.Queries, db *pgxpool.Pool) Service {
+	return &svc{
+		repo: repo,
+		db:   db,
+	}
+}
+
+// Notify creates a notification for the user with the given arg params. No notification is
+// created when users act on their own content, when the user has turned off notifications of that
+// type, or when the same unread notification already exists.
+func (s *svc) Notify(ctx context.Context, arg repo.CreateNotificationParams) error {
+	err := s.repo.CreateNotification(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListNotifications returns a page of the notifications of the user from the database, newest
+// first, together with the pagination metadata. If unreadOnly is true, read notifications are
+// left out.
+func (s *svc) ListNotifications(ctx context.Context, userId int64, unreadOnly bool, page helper.Page) ([]Notification, api.PageMeta, error) {
+	arg := repo.ListNotificationsParams{
+		UserID:     userId,
+		UnreadOnly: unreadOnly,
+		PageLimit:  page.Limit + 1,
+	}
+	if page.Cursor != "" {
+		var c notificationCursor
+		err := helper.DecodeCursor(page.Cursor, &c)
+		if err != nil {
+			return []Notification{}, api.PageMeta{}, err
+		}
+		arg.CursorNotificationID = pgtype.Int8{Int64: c.NotificationID, Valid: true}
+	}
+
+	rows, err := s.repo.ListNotifications(ctx, arg)
+	if err != nil {
+		return []Notification{}, api.PageMeta{}, err
+	}
+
+	countArg := repo.CountNotificationsParams{
+		UserID:     userId,
+		UnreadOnly: unreadOnly,
+	}
+	total, err := s.repo.CountNotifications(ctx, countArg)
+	if err != nil {
+		return []Notification{}, api.PageMeta{}, err
+	}
+
+	fetched := len(rows)
+	if fetched > int(page.Limit) {
+		rows = rows[:page.Limit]
+	}
+
+	notifications := make([]Notification, 0, len(rows))
+	for _, row := range rows {
+		notifications = append(notifications, toNotification(row))
+	}
+
+	nextCursor := ""
+	if len(notifications) > 0 {
+		last := notifications[len(notifications)-1]
+		nextCursor = helper.EncodeCursor(notificationCursor{NotificationID: last.NotificationID})
+	}
+
+	return notifications, helper.NewPageMeta(fetched, page, total, nextCursor), nil
+}
+
+// MarkRead marks the notification given by the id as read. Only the recipient of the
+// notification may mark it.
+func (s *svc) MarkRead(ctx context.Context, userId int64, notificationId int64) error {
+	arg := repo.MarkNotificationReadParams{
+		NotificationID: notificationId,
+		UserID:         userId,
+	}
+	updRows, err := s.repo.MarkNotificationRead(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	if updRows == 0 {
+		return ErrNotificationNotFound
+	}
+
+	return nil
+}
+
+// MarkAllRead marks all unread notifications of the user as read and returns how many were
+// marked.
+func (s *svc) MarkAllRead(ctx context.Context, userId int64) (int64, error) {
+	return s.repo.MarkAllNotificationsRead(ctx, userId)
+}
+
+// ListPreferences returns whether the user receives each type of notification. Types that the
+// user has not set are enabled.
+func (s *svc) ListPreferences(ctx context.Context, userId int64) ([]Preference, error) {
+	rows, err := s.repo.ListNotificationPreferences(ctx, userId)
+	if err != nil {
+		return []Preference{}, err
+	}
+
+	enabled := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		enabled[row.Type] = row.Enabled
+	}
+
+	prefs := make([]Preference, 0, len(Types))
+	for _, t := range Types {
+		on, ok := enabled[t]
+		prefs = append(prefs, Preference{Type: t, Enabled: !ok || on})
+	}
+
+	return prefs, nil
+}
+
+// UpdatePreferences turns the given notification types on or off for the user and returns the
+// resulting preferences.
+// If there is an error in between, the whole transaction is rolled back.
+func (s *svc) UpdatePreferences(ctx context.Context, userId int64, prefs []PreferenceRequest) ([]Preference, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return []Preference{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.repo.WithTx(tx)
+
+	for _, pref := range prefs {
+		arg := repo.UpsertNotificationPreferenceParams{
+			UserID:  userId,
+			Type:    pref.Type,
+			Enabled: *pref.Enabled,
+		}
+		err = qtx.UpsertNotificationPreference(ctx, arg)
+		if err != nil {
+			return []Preference{}, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return []Preference{}, err
+	}
+
+	return s.ListPreferences(ctx, userId)
+}
+
+// toNotification converts a notification row into the Notification model.
+func toNotification(row repo.ListNotificationsRow) Notification {
+	notification := Notification{
+		NotificationID: row.NotificationID,
+		Type:           row.Type,
+		ActorID:        row.ActorID,
+		ActorName:      row.ActorName,
+		Message:        row.Message,
+		Read:           row.ReadAt.Valid,
+		CreatedAt:      row.CreatedAt.Time,
+	}
+	if row.TopicID.Valid {
+		notification.TopicID = &row.TopicID.Int64
+	}
+	if row.PostID.Valid {
+		notification.PostID = &row.PostID.Int64
+	}
+	if row.CommentID.Valid {
+		notification.CommentID = &row.CommentID.Int64
+	}
+	if row.ReadAt.Valid {
+		notification.ReadAt = &row.ReadAt.Time
+	}
+
+	return notification
+}
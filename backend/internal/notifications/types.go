@@ -0,0 +1,79 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+)
+
+// Types of events that generate a notification for a user.
+const (
+	TypePostComment  = "post_comment"
+	TypeCommentReply = "comment_reply"
+	TypeMention      = "mention"
+	TypePostVote     = "post_vote"
+	TypeCommentVote  = "comment_vote"
+
+	// TypeModerationWarning is sent by a moderator who resolved a report. It cannot be turned off.
+	TypeModerationWarning = "moderation_warning"
+)
+
+// Types lists every notification type that can be turned off, in the order that preferences are
+// returned.
+var Types = []string{TypePostComment, TypeCommentReply, TypeMention, TypePostVote, TypeCommentVote}
+
+// Service defines the domain logic for notification related operations.
+// It is responsible for enforcing application rules and making database calls.
+type Service interface {
+	Notify(ctx context.Context, arg repo.CreateNotificationParams) error
+	ListNotifications(ctx context.Context, userId int64, unreadOnly bool, page helper.Page) ([]Notification, api.PageMeta, error)
+	MarkRead(ctx context.Context, userId int64, notificationId int64) error
+	MarkAllRead(ctx context.Context, userId int64) (int64, error)
+	ListPreferences(ctx context.Context, userId int64) ([]Preference, error)
+	UpdatePreferences(ctx context.Context, userId int64, prefs []PreferenceRequest) ([]Preference, error)
+}
+
+// Notification model that is passed to the frontend. The actor is the user whose action caused
+// the notification, and TopicID, PostID and CommentID locate the content the action was on. They
+// are omitted when they do not apply. Message holds the text of a moderation warning.
+type Notification struct {
+	NotificationID int64      `json:"notification_id"`
+	Type           string     `json:"type"`
+	ActorID        int64      `json:"actor_id"`
+	ActorName      string     `json:"actor_name"`
+	TopicID        *int64     `json:"topic_id,omitempty"`
+	PostID         *int64     `json:"post_id,omitempty"`
+	CommentID      *int64     `json:"comment_id,omitempty"`
+	Message        string     `json:"message,omitempty"`
+	Read           bool       `json:"read"`
+	ReadAt         *time.Time `json:"read_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// Preference model that is passed to the frontend. It tells whether the user receives
+// notifications of the given type.
+type Preference struct {
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+}
+
+// PreferenceRequest handles a single entry of the preferences HTTP request body.
+type PreferenceRequest struct {
+	Type    string `json:"type" validate:"required,oneof=post_comment comment_reply mention post_vote comment_vote"`
+	Enabled *bool  `json:"enabled" validate:"required"`
+}
+
+// UpdatePreferencesRequest handles the notification related HTTP request body for turning
+// notification types on or off. Types that are left out keep their current setting.
+type UpdatePreferencesRequest struct {
+	Preferences []PreferenceRequest `json:"preferences" validate:"required,min=1,dive"`
+}
+
+// notificationCursor holds the id of the last notification in a page, which is encoded into the
+// opaque next_cursor string.
+type notificationCursor struct {
+	NotificationID int64 `json:"notification_id"`
+}
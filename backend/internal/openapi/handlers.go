@@ -0,0 +1,29 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+)
+
+// handler handles the OpenAPI document HTTP requests.
+type handler struct{}
+
+// NewHandler creates a new OpenAPI handler.
+func NewHandler() *handler {
+	return &handler{}
+}
+
+// Document handles GET /api/openapi.json requests.
+// It builds the OpenAPI document of the API and writes it as is, without the response envelope.
+func (h *handler) Document(w http.ResponseWriter, r *http.Request) {
+	document, err := json.Marshal(Spec())
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(document)
+}
@@ -0,0 +1,10 @@
+package openapi
+
+import "github.com/go-chi/chi/v5"
+
+// Routes serves the OpenAPI document at /api/openapi.json. It is mounted outside of the /api group,
+// so that it can be read without logging in.
+// It connects the URLS to their respective handler methods.
+func Routes(router chi.Router, h *handler) {
+	router.Get(Path, h.Document)
+}
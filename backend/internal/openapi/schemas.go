@@ -0,0 +1,126 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+)
+
+// nullableSchemas maps the pgtype types, which marshal to their value or to null, to their schema.
+var nullableSchemas = map[reflect.Type]Schema{
+	reflect.TypeFor[pgtype.Int2]():        {Type: "integer", Format: "int32", Nullable: true},
+	reflect.TypeFor[pgtype.Int4]():        {Type: "integer", Format: "int32", Nullable: true},
+	reflect.TypeFor[pgtype.Int8]():        {Type: "integer", Format: "int64", Nullable: true},
+	reflect.TypeFor[pgtype.Float8]():      {Type: "number", Format: "double", Nullable: true},
+	reflect.TypeFor[pgtype.Bool]():        {Type: "boolean", Nullable: true},
+	reflect.TypeFor[pgtype.Text]():        {Type: "string", Nullable: true},
+	reflect.TypeFor[pgtype.Timestamptz](): {Type: "string", Format: "date-time", Nullable: true},
+}
+
+// schemaOf returns the schema of the values of type t. Named struct types are added to schemas
+// under their qualified name, e.g. posts.Post, and are referred to by it.
+func schemaOf(t reflect.Type, schemas map[string]*Schema) *Schema {
+	if s, ok := nullableSchemas[t]; ok {
+		return &s
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := schemaOf(t.Elem(), schemas)
+		if s.Ref != "" {
+			return &Schema{AllOf: []*Schema{s}, Nullable: true}
+		}
+		s.Nullable = true
+		return s
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: schemaOf(t.Elem(), schemas)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaOf(t.Elem(), schemas)}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return structSchema(t, schemas)
+		}
+
+		name := t.String()
+		if _, ok := schemas[name]; !ok {
+			schemas[name] = &Schema{}
+			*schemas[name] = *structSchema(t, schemas)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+
+	return &Schema{}
+}
+
+// structSchema returns the object schema of the struct type t, with a property for each field
+// that is marshaled to JSON. Fields of embedded structs are promoted, and fields that are validated
+// as required, rather than their elements, are listed as required.
+func structSchema(t reflect.Type, schemas map[string]*Schema) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embedded := structSchema(field.Type, schemas)
+			for prop, propSchema := range embedded.Properties {
+				s.Properties[prop] = propSchema
+			}
+			s.Required = append(s.Required, embedded.Required...)
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		s.Properties[name] = schemaOf(field.Type, schemas)
+
+		for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+			if rule == "dive" {
+				break
+			}
+			if rule == "required" {
+				s.Required = append(s.Required, name)
+				break
+			}
+		}
+	}
+	return s
+}
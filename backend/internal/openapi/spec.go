@@ -0,0 +1,325 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/attachments"
+	"github.com/haobuhaoo/gossip-with-go/internal/audit"
+	"github.com/haobuhaoo/gossip-with-go/internal/auth"
+	"github.com/haobuhaoo/gossip-with-go/internal/bookmarks"
+	"github.com/haobuhaoo/gossip-with-go/internal/comments"
+	"github.com/haobuhaoo/gossip-with-go/internal/mentions"
+	"github.com/haobuhaoo/gossip-with-go/internal/notifications"
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+	"github.com/haobuhaoo/gossip-with-go/internal/posts"
+	"github.com/haobuhaoo/gossip-with-go/internal/render"
+	"github.com/haobuhaoo/gossip-with-go/internal/reports"
+	"github.com/haobuhaoo/gossip-with-go/internal/roles"
+	"github.com/haobuhaoo/gossip-with-go/internal/search"
+	"github.com/haobuhaoo/gossip-with-go/internal/stream"
+	"github.com/haobuhaoo/gossip-with-go/internal/suspensions"
+	"github.com/haobuhaoo/gossip-with-go/internal/topics"
+	"github.com/haobuhaoo/gossip-with-go/internal/users"
+)
+
+// Path is where the document is served.
+const Path = "/api/openapi.json"
+
+// pathParamRegex matches the parameters of a route pattern, e.g. {id}.
+var pathParamRegex = regexp.MustCompile(`\{(\w+)\}`)
+
+// Endpoints lists every route of the router. A route that is added to the router must be added
+// here too, which the tests of the main package check.
+var Endpoints = []Route{
+	{Method: http.MethodGet, Path: Path, Tag: "docs", Summary: "Get this OpenAPI document", Public: true, Produces: "application/json"},
+	{Method: http.MethodGet, Path: "/healthz", Tag: "health", Summary: "Check that the server is alive", Public: true},
+	{Method: http.MethodGet, Path: "/readyz", Tag: "health", Summary: "Check that the server and its database are ready to serve requests", Public: true},
+
+	{Method: http.MethodPost, Path: "/auth/login", Tag: "auth", Summary: "Log in with a name and password", Public: true, Body: auth.LoginRequest{}, Data: auth.LoginResponse{}},
+	{Method: http.MethodPost, Path: "/auth/refresh", Tag: "auth", Summary: "Exchange a refresh token for new tokens", Public: true, Body: auth.RefreshRequest{}, Data: auth.LoginResponse{}},
+	{Method: http.MethodPost, Path: "/auth/logout", Tag: "auth", Summary: "Revoke the access token and refresh token", Body: auth.LogoutRequest{}},
+	{Method: http.MethodGet, Path: "/users/{name}", Tag: "users", Summary: "Find a user by name", Public: true, Data: users.User{}},
+	{Method: http.MethodPost, Path: "/users", Tag: "users", Summary: "Register a new user", Public: true, Body: users.CreateUserRequest{}, Data: users.User{}},
+
+	{Method: http.MethodGet, Path: "/api/me", Tag: "auth", Summary: "Get the logged in user", Data: users.User{}},
+	{Method: http.MethodPut, Path: "/api/me/password", Tag: "auth", Summary: "Change the password of the logged in user", Body: auth.ChangePasswordRequest{}, Data: auth.LoginResponse{}},
+	{Method: http.MethodPost, Path: "/api/me/logout-all", Tag: "auth", Summary: "Log out of every session"},
+	{Method: http.MethodGet, Path: "/api/me/saved", Tag: "bookmarks", Summary: "List the saved posts and comments", Query: []Param{{Name: "topic", Type: "integer", Description: "Only list the content of the topic"}}, Data: []bookmarks.Bookmark{}, Paged: true},
+	{Method: http.MethodGet, Path: "/api/me/mentions", Tag: "mentions", Summary: "List the posts and comments that mention the user", Data: []mentions.Mention{}, Paged: true},
+
+	{Method: http.MethodGet, Path: "/api/notifications", Tag: "notifications", Summary: "List the notifications", Query: []Param{{Name: "unread", Type: "boolean", Description: "Only list unread notifications"}}, Data: []notifications.Notification{}, Paged: true},
+	{Method: http.MethodPut, Path: "/api/notifications/read", Tag: "notifications", Summary: "Mark all notifications as read", Data: map[string]int64{}},
+	{Method: http.MethodPut, Path: "/api/notifications/{id}/read", Tag: "notifications", Summary: "Mark a notification as read"},
+	{Method: http.MethodGet, Path: "/api/notifications/preferences", Tag: "notifications", Summary: "List the notification preferences", Data: []notifications.Preference{}},
+	{Method: http.MethodPut, Path: "/api/notifications/preferences", Tag: "notifications", Summary: "Turn notification types on or off", Body: notifications.UpdatePreferencesRequest{}, Data: []notifications.Preference{}},
+
+	{Method: http.MethodGet, Path: "/api/stream", Tag: "stream", Summary: "Follow live changes as server-sent events", Query: []Param{{Name: "topic", Type: "integer", Description: "Follow the posts of the topic"}, {Name: "post", Type: "integer", Description: "Follow the comments of the post"}}, Data: stream.Event{}, Produces: "text/event-stream"},
+
+	{Method: http.MethodGet, Path: "/api/topics", Tag: "topics", Summary: "List the topics", Data: []repo.Topic{}, Paged: true},
+	{Method: http.MethodGet, Path: "/api/topics/search", Tag: "topics", Summary: "Search the topics", Query: []Param{{Name: "q", Required: true, Description: "Search terms"}}, Data: []topics.TopicSearchResult{}, Paged: true},
+	{Method: http.MethodGet, Path: "/api/topics/subscriptions", Tag: "topics", Summary: "List the subscribed topics", Data: []repo.Topic{}, Paged: true},
+	{Method: http.MethodGet, Path: "/api/topics/{id}", Tag: "topics", Summary: "Find a topic by id", Data: repo.Topic{}},
+	{Method: http.MethodPost, Path: "/api/topics", Tag: "topics", Summary: "Create a topic", Body: topics.CreateTopicRequest{}, Data: repo.Topic{}},
+	{Method: http.MethodPut, Path: "/api/topics/{id}", Tag: "topics", Summary: "Update a topic", Body: topics.UpdateTopicRequest{}, Data: repo.Topic{}},
+	{Method: http.MethodDelete, Path: "/api/topics/{id}", Tag: "topics", Summary: "Delete a topic"},
+	{Method: http.MethodPost, Path: "/api/topics/{id}/subscription", Tag: "topics", Summary: "Subscribe to a topic"},
+	{Method: http.MethodDelete, Path: "/api/topics/{id}/subscription", Tag: "topics", Summary: "Unsubscribe from a topic"},
+
+	{Method: http.MethodGet, Path: "/api/mentions/autocomplete", Tag: "mentions", Summary: "Suggest users to mention by name prefix", Query: []Param{{Name: "q", Required: true, Description: "Prefix of the name"}, {Name: "limit", Type: "integer", Description: "Number of users to return"}}, Data: []mentions.User{}},
+
+	{Method: http.MethodGet, Path: "/api/feed", Tag: "posts", Summary: "List the posts of the subscribed topics", Data: []posts.Post{}, Paged: true, Sorted: true},
+	{Method: http.MethodGet, Path: "/api/posts/all/{topicId}", Tag: "posts", Summary: "List the posts of a topic", Data: []posts.Post{}, Paged: true, Sorted: true},
+	{Method: http.MethodGet, Path: "/api/posts/{topicId}/search", Tag: "posts", Summary: "Search the posts of a topic", Query: []Param{{Name: "q", Required: true, Description: "Search terms"}}, Data: []posts.Post{}, Paged: true},
+	{Method: http.MethodGet, Path: "/api/posts/{id}/revisions", Tag: "posts", Summary: "List the revisions of a post", Data: []posts.Revision{}},
+	{Method: http.MethodGet, Path: "/api/posts/{id}/revisions/diff", Tag: "posts", Summary: "Compare two revisions of a post", Query: versionParams, Data: posts.RevisionDiff{}},
+	{Method: http.MethodGet, Path: "/api/posts/{topicId}/{postId}", Tag: "posts", Summary: "Find a post by id", Data: posts.Post{}},
+	{Method: http.MethodPost, Path: "/api/posts/{id}/likes", Tag: "posts", Summary: "Like a post"},
+	{Method: http.MethodPost, Path: "/api/posts/{id}/dislikes", Tag: "posts", Summary: "Dislike a post"},
+	{Method: http.MethodPost, Path: "/api/posts/{id}/restore", Tag: "posts", Summary: "Restore a deleted post", Data: repo.Post{}},
+	{Method: http.MethodPost, Path: "/api/posts/{id}/poll/vote", Tag: "posts", Summary: "Vote in the poll of a post", Body: posts.VotePollRequest{}, Data: posts.Poll{}},
+	{Method: http.MethodPost, Path: "/api/posts", Tag: "posts", Summary: "Create a post", Body: posts.CreatePostRequest{}, Data: repo.Post{}},
+	{Method: http.MethodPut, Path: "/api/posts/{id}", Tag: "posts", Summary: "Update a post", Body: posts.UpdatePostRequest{}, Data: repo.Post{}},
+	{Method: http.MethodDelete, Path: "/api/posts/{id}/remove", Tag: "posts", Summary: "Remove the vote on a post"},
+	{Method: http.MethodDelete, Path: "/api/posts/{id}", Tag: "posts", Summary: "Delete a post"},
+
+	{Method: http.MethodGet, Path: "/api/comments/all/{topicId}/{postId}", Tag: "comments", Summary: "List the comments of a post with their replies", Data: []comments.Comment{}, Paged: true, Sorted: true},
+	{Method: http.MethodGet, Path: "/api/comments/{topicId}/search", Tag: "comments", Summary: "Search the comments of a topic", Query: []Param{{Name: "q", Required: true, Description: "Search terms"}}, Data: []comments.Comment{}, Paged: true},
+	{Method: http.MethodGet, Path: "/api/comments/{id}/revisions", Tag: "comments", Summary: "List the revisions of a comment", Data: []comments.Revision{}},
+	{Method: http.MethodGet, Path: "/api/comments/{id}/revisions/diff", Tag: "comments", Summary: "Compare two revisions of a comment", Query: versionParams, Data: comments.RevisionDiff{}},
+	{Method: http.MethodPost, Path: "/api/comments/{id}/likes", Tag: "comments", Summary: "Like a comment"},
+	{Method: http.MethodPost, Path: "/api/comments/{id}/dislikes", Tag: "comments", Summary: "Dislike a comment"},
+	{Method: http.MethodPost, Path: "/api/comments/{id}/restore", Tag: "comments", Summary: "Restore a deleted comment", Data: repo.Comment{}},
+	{Method: http.MethodPost, Path: "/api/comments", Tag: "comments", Summary: "Create a comment or reply", Body: comments.CreateCommentRequest{}, Data: repo.Comment{}},
+	{Method: http.MethodPut, Path: "/api/comments/{id}", Tag: "comments", Summary: "Update a comment", Body: comments.UpdateCommentRequest{}, Data: repo.Comment{}},
+	{Method: http.MethodDelete, Path: "/api/comments/{id}/remove", Tag: "comments", Summary: "Remove the vote on a comment"},
+	{Method: http.MethodDelete, Path: "/api/comments/{id}", Tag: "comments", Summary: "Delete a comment"},
+
+	{Method: http.MethodGet, Path: "/api/attachments/posts/{postId}", Tag: "attachments", Summary: "List the attachments of a post", Data: []attachments.Attachment{}},
+	{Method: http.MethodGet, Path: "/api/attachments/comments/{commentId}", Tag: "attachments", Summary: "List the attachments of a comment", Data: []attachments.Attachment{}},
+	{Method: http.MethodGet, Path: "/api/attachments/{id}", Tag: "attachments", Summary: "Download an attachment", Produces: "application/octet-stream"},
+	{Method: http.MethodGet, Path: "/api/attachments/{id}/thumbnail", Tag: "attachments", Summary: "Download the thumbnail of an attachment", Produces: "application/octet-stream"},
+	{Method: http.MethodPost, Path: "/api/attachments/posts/{postId}", Tag: "attachments", Summary: "Upload an attachment to a post", Upload: true, Data: attachments.Attachment{}},
+	{Method: http.MethodPost, Path: "/api/attachments/comments/{commentId}", Tag: "attachments", Summary: "Upload an attachment to a comment", Upload: true, Data: attachments.Attachment{}},
+	{Method: http.MethodDelete, Path: "/api/attachments/{id}", Tag: "attachments", Summary: "Delete an attachment"},
+
+	{Method: http.MethodPost, Path: "/api/bookmarks/posts/{postId}", Tag: "bookmarks", Summary: "Save a post"},
+	{Method: http.MethodDelete, Path: "/api/bookmarks/posts/{postId}", Tag: "bookmarks", Summary: "Remove a saved post"},
+	{Method: http.MethodPost, Path: "/api/bookmarks/comments/{commentId}", Tag: "bookmarks", Summary: "Save a comment"},
+	{Method: http.MethodDelete, Path: "/api/bookmarks/comments/{commentId}", Tag: "bookmarks", Summary: "Remove a saved comment"},
+
+	{Method: http.MethodPost, Path: "/api/reports", Tag: "reports", Summary: "Report a post or comment", Body: reports.CreateReportRequest{}, Data: repo.Report{}},
+	{Method: http.MethodGet, Path: "/api/reports/queue", Tag: "reports", Summary: "List the open reports that the user can moderate", Data: []reports.QueueItem{}, Paged: true},
+	{Method: http.MethodPost, Path: "/api/reports/{id}/resolve", Tag: "reports", Summary: "Resolve a report", Body: reports.ResolveReportRequest{}, Data: reports.Resolution{}},
+
+	{Method: http.MethodGet, Path: "/api/suspensions", Tag: "suspensions", Summary: "List the suspensions that the user can moderate", Data: []suspensions.Suspension{}, Paged: true},
+	{Method: http.MethodPost, Path: "/api/suspensions", Tag: "suspensions", Summary: "Suspend or ban a user", Body: suspensions.CreateSuspensionRequest{}, Data: repo.Suspension{}},
+	{Method: http.MethodDelete, Path: "/api/suspensions/{id}", Tag: "suspensions", Summary: "Lift a suspension"},
+
+	{Method: http.MethodGet, Path: "/api/search", Tag: "search", Summary: "Search topics, posts, comments and users", Query: searchParams, Data: []search.Result{}, Paged: true},
+
+	{Method: http.MethodPost, Path: "/api/render/preview", Tag: "render", Summary: "Render a description to sanitized HTML", Body: render.PreviewRequest{}, Data: render.Preview{}},
+
+	{Method: http.MethodPost, Path: "/api/admin/users/{id}/logout", Tag: "admin", Summary: "Log a user out of every session"},
+	{Method: http.MethodPut, Path: "/api/admin/users/{id}/role", Tag: "admin", Summary: "Change the role of a user", Body: roles.SetUserRoleRequest{}, Data: users.User{}},
+	{Method: http.MethodGet, Path: "/api/admin/topics/{topicId}/moderators", Tag: "admin", Summary: "List the moderators of a topic", Data: []roles.TopicModerator{}},
+	{Method: http.MethodPost, Path: "/api/admin/topics/{topicId}/moderators", Tag: "admin", Summary: "Add a moderator to a topic", Body: roles.AddTopicModeratorRequest{}},
+	{Method: http.MethodDelete, Path: "/api/admin/topics/{topicId}/moderators/{userId}", Tag: "admin", Summary: "Remove a moderator from a topic"},
+	{Method: http.MethodGet, Path: "/api/admin/audit", Tag: "admin", Summary: "List the audit log", Query: auditParams, Data: []audit.Log{}, Paged: true},
+}
+
+// versionParams are the query parameters of the revision diff routes.
+var versionParams = []Param{
+	{Name: "from", Type: "integer", Required: true, Description: "Version to compare from"},
+	{Name: "to", Type: "integer", Required: true, Description: "Version to compare to"},
+}
+
+// searchParams are the query parameters of the global search route.
+var searchParams = []Param{
+	{Name: "q", Required: true, Description: "Search terms"},
+	{Name: "type", Description: "Only return results of the type: topic, post, comment or user"},
+	{Name: "author", Description: "Only return items created by the username"},
+	{Name: "topic", Type: "integer", Description: "Only return items under the topic"},
+	{Name: "from", Description: "Only return items created on or after the date"},
+	{Name: "to", Description: "Only return items created on or before the date"},
+	{Name: "min_score", Type: "integer", Description: "Only return items with at least this many likes minus dislikes"},
+}
+
+// auditParams are the query parameters of the audit log route.
+var auditParams = []Param{
+	{Name: "actor", Type: "integer", Description: "Only list actions taken by the user"},
+	{Name: "action", Description: "Only list actions of the type, e.g. post.delete"},
+	{Name: "target_type", Description: "Only list actions on the type of target"},
+	{Name: "target_id", Type: "integer", Description: "Only list actions on the target"},
+	{Name: "topic", Type: "integer", Description: "Only list actions within the topic"},
+	{Name: "from", Description: "Only list actions taken at or after the RFC 3339 time"},
+	{Name: "to", Description: "Only list actions taken at or before the RFC 3339 time"},
+}
+
+// Spec builds the OpenAPI document of the routes. Every JSON response is wrapped in the
+// api.Response envelope, with the route data in payload.data.
+func Spec() Document {
+	schemas := map[string]*Schema{}
+	envelope := schemaOf(reflect.TypeFor[api.Response](), schemas)
+	pageMeta := schemaOf(reflect.TypeFor[api.PageMeta](), schemas)
+
+	doc := Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       "Gossip with Go API",
+			Description: "Every JSON response is an api.Response envelope. Errors carry the message in messages, the error code from the catalogue in code, the invalid fields in details and, for internal errors, the requestId they were logged with, and requests over the rate limit are rejected with 429 and a Retry-After header.",
+			Version:     "1.0.0",
+		},
+		Servers:  []Server{{URL: "/"}},
+		Tags:     []Tag{},
+		Paths:    map[string]map[string]*Operation{},
+		Security: []map[string][]string{{"bearerAuth": {}}},
+		Components: Components{
+			Schemas: schemas,
+			Responses: map[string]*Response{
+				"Error": {
+					Description: "The request failed",
+					Content:     map[string]*MediaType{"application/json": {Schema: envelope}},
+				},
+			},
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	seenTags := map[string]bool{}
+	for _, route := range Endpoints {
+		if !seenTags[route.Tag] {
+			seenTags[route.Tag] = true
+			doc.Tags = append(doc.Tags, Tag{Name: route.Tag})
+		}
+
+		if doc.Paths[route.Path] == nil {
+			doc.Paths[route.Path] = map[string]*Operation{}
+		}
+		doc.Paths[route.Path][strings.ToLower(route.Method)] = buildOperation(route, envelope, pageMeta, schemas)
+	}
+
+	return doc
+}
+
+// buildOperation returns the operation of the route, with its parameters, request body and
+// successful response wrapped in the envelope.
+func buildOperation(route Route, envelope *Schema, pageMeta *Schema, schemas map[string]*Schema) *Operation {
+	op := &Operation{
+		OperationID: operationID(route),
+		Summary:     route.Summary,
+		Tags:        []string{route.Tag},
+		Responses: map[string]*Response{
+			"default": {Ref: "#/components/responses/Error"},
+		},
+	}
+	if route.Public {
+		op.Security = &[]map[string][]string{}
+	}
+
+	for _, match := range pathParamRegex.FindAllStringSubmatch(route.Path, -1) {
+		schema := &Schema{Type: "integer", Format: "int64"}
+		if match[1] == "name" {
+			schema = &Schema{Type: "string"}
+		}
+		op.Parameters = append(op.Parameters, Parameter{Name: match[1], In: "path", Required: true, Schema: schema})
+	}
+
+	query := route.Query
+	if route.Sorted {
+		query = append(query,
+			Param{Name: "sort", Description: "Sort order: likes, new, top, hot, controversial or active"},
+			Param{Name: "t", Description: "Time window of the top sort order: day, week, month, year or all"},
+		)
+	}
+	if route.Paged {
+		query = append(query,
+			Param{Name: "limit", Type: "integer", Description: "Number of items per page"},
+			Param{Name: "cursor", Description: "The next_cursor of the previous page"},
+		)
+	}
+	for _, param := range query {
+		paramType := param.Type
+		if paramType == "" {
+			paramType = "string"
+		}
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:        param.Name,
+			In:          "query",
+			Description: param.Description,
+			Required:    param.Required,
+			Schema:      &Schema{Type: paramType},
+		})
+	}
+
+	if route.Body != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{"application/json": {Schema: schemaOf(reflect.TypeOf(route.Body), schemas)}},
+		}
+	}
+	if route.Upload {
+		upload := &Schema{
+			Type:       "object",
+			Properties: map[string]*Schema{"file": {Type: "string", Format: "binary"}},
+			Required:   []string{"file"},
+		}
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{"multipart/form-data": {Schema: upload}},
+		}
+	}
+
+	success := &Response{Description: "Successful response"}
+	switch {
+	case route.Produces == "":
+		success.Content = map[string]*MediaType{"application/json": {Schema: envelopeOf(route, envelope, pageMeta, schemas)}}
+	case route.Data != nil:
+		success.Content = map[string]*MediaType{route.Produces: {Schema: schemaOf(reflect.TypeOf(route.Data), schemas)}}
+	default:
+		success.Content = map[string]*MediaType{route.Produces: {Schema: &Schema{Type: "string", Format: "binary"}}}
+	}
+	op.Responses["200"] = success
+
+	return op
+}
+
+// envelopeOf returns the schema of the envelope with the data of the route, and the pagination
+// metadata for paged routes.
+func envelopeOf(route Route, envelope *Schema, pageMeta *Schema, schemas map[string]*Schema) *Schema {
+	if route.Data == nil {
+		return envelope
+	}
+
+	payload := &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{"data": schemaOf(reflect.TypeOf(route.Data), schemas)},
+	}
+	if route.Paged {
+		payload.Properties["meta"] = pageMeta
+	}
+
+	return &Schema{AllOf: []*Schema{
+		envelope,
+		{Type: "object", Properties: map[string]*Schema{"payload": payload}},
+	}}
+}
+
+// operationID returns a unique id of the route built from its method and path, e.g.
+// post_api_posts_id_likes.
+func operationID(route Route) string {
+	id := strings.ToLower(route.Method)
+	for _, part := range strings.Split(route.Path, "/") {
+		part = strings.Trim(part, "{}")
+		part = strings.NewReplacer(".", "_", "-", "_").Replace(part)
+		if part != "" {
+			id += "_" + part
+		}
+	}
+	return id
+}
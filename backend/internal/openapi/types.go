@@ -0,0 +1,139 @@
+package openapi
+
+// Version is the OpenAPI version that the document follows.
+const Version = "3.0.3"
+
+// Document is the root of an OpenAPI document. Paths are keyed by the route pattern, and each
+// path item by the lower case HTTP method.
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Servers    []Server                         `json:"servers"`
+	Tags       []Tag                            `json:"tags"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+	Security   []map[string][]string            `json:"security"`
+}
+
+// Info describes the API.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+}
+
+// Server is a base URL that the paths are relative to.
+type Server struct {
+	URL string `json:"url"`
+}
+
+// Tag groups the operations of a package.
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// Components holds the schemas of the request and response types, which operations refer to by
+// name, together with the shared responses and the bearer token security scheme.
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	Responses       map[string]*Response       `json:"responses"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+// Operation describes a single HTTP method on a path. Security is an empty list for operations
+// that do not take a bearer token.
+type Operation struct {
+	OperationID string                 `json:"operationId"`
+	Summary     string                 `json:"summary"`
+	Tags        []string               `json:"tags"`
+	Parameters  []Parameter            `json:"parameters,omitempty"`
+	RequestBody *RequestBody           `json:"requestBody,omitempty"`
+	Responses   map[string]*Response   `json:"responses"`
+	Security    *[]map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter is a path or query parameter of an operation.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is the body of an operation, keyed by its content type.
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response is a response of an operation, keyed by its content type. Ref refers to a shared
+// response instead.
+type Response struct {
+	Ref         string                `json:"$ref,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// Header is a header of a response.
+type Header struct {
+	Description string  `json:"description"`
+	Schema      *Schema `json:"schema"`
+}
+
+// MediaType holds the schema of a request or response body.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// SecurityScheme describes how requests are authenticated.
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat"`
+}
+
+// Schema is a JSON schema of a value. Ref refers to a named schema in the components instead.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+}
+
+// Route describes an endpoint of the router, from which its operation in the document is built.
+// Path parameters are read from the braces in Path, and are integers unless they are named `name`.
+// Body and Data are zero values of the request body type and of the type in the `data` field of
+// the response envelope. Paged routes take the `limit` and `cursor` query parameters and return
+// api.PageMeta in the `meta` field, and Sorted routes take the `sort` and `t` query parameters.
+// Produces is set for routes that respond with something else than the JSON envelope, and Upload
+// for routes that take a multipart file upload. Public routes do not take a bearer token.
+type Route struct {
+	Method   string
+	Path     string
+	Tag      string
+	Summary  string
+	Public   bool
+	Query    []Param
+	Body     any
+	Data     any
+	Paged    bool
+	Sorted   bool
+	Upload   bool
+	Produces string
+}
+
+// Param is a query parameter of a route. Type is the JSON schema type, which defaults to string.
+type Param struct {
+	Name        string
+	Type        string
+	Required    bool
+	Description string
+}
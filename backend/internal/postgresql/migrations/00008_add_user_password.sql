@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE Users ADD COLUMN password_hash TEXT;
+ALTER TABLE Users ADD COLUMN must_change_password BOOLEAN NOT NULL DEFAULT false;
+
+-- Existing users are left without a password, so that they cannot log in until an operator issues
+-- them a temporary password, and are forced to set a new password before using the rest of the API.
+UPDATE Users SET must_change_password = true;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE Users DROP COLUMN IF EXISTS must_change_password;
+ALTER TABLE Users DROP COLUMN IF EXISTS password_hash;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE Users ADD COLUMN tokens_revoked_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS Refresh_Tokens (
+    token_id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    token_hash TEXT UNIQUE NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    FOREIGN KEY (user_id) REFERENCES Users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS Refresh_Tokens_user_id_idx ON Refresh_Tokens (user_id);
+
+CREATE TABLE IF NOT EXISTS Revoked_Tokens (
+    jti TEXT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    FOREIGN KEY (user_id) REFERENCES Users(user_id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS Revoked_Tokens;
+DROP TABLE IF EXISTS Refresh_Tokens;
+ALTER TABLE Users DROP COLUMN IF EXISTS tokens_revoked_at;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE Users ADD COLUMN role TEXT NOT NULL DEFAULT 'member';
+ALTER TABLE Users ADD CONSTRAINT role_valid CHECK (role IN ('admin', 'moderator', 'member'));
+
+CREATE TABLE IF NOT EXISTS Topic_Moderators (
+    topic_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT Topic_Moderators_pk PRIMARY KEY (topic_id, user_id),
+    FOREIGN KEY (topic_id) REFERENCES Topics(topic_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES Users(user_id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS Topic_Moderators;
+ALTER TABLE Users DROP CONSTRAINT IF EXISTS role_valid;
+ALTER TABLE Users DROP COLUMN IF EXISTS role;
+-- +goose StatementEnd
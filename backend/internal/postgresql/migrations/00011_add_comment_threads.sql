@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE Comments ADD COLUMN parent_comment_id BIGINT REFERENCES Comments(comment_id);
+ALTER TABLE Comments ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS Comments_parent_comment_id_idx ON Comments (parent_comment_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS Comments_parent_comment_id_idx;
+ALTER TABLE Comments DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE Comments DROP COLUMN IF EXISTS parent_comment_id;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE Topics ADD COLUMN search_vector TSVECTOR
+GENERATED ALWAYS AS (to_tsvector('english', title)) STORED;
+
+ALTER TABLE Posts ADD COLUMN search_vector TSVECTOR
+GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', title), 'A') ||
+    setweight(to_tsvector('english', description), 'B')
+) STORED;
+
+ALTER TABLE Comments ADD COLUMN search_vector TSVECTOR
+GENERATED ALWAYS AS (to_tsvector('english', description)) STORED;
+
+CREATE INDEX IF NOT EXISTS Topics_search_vector_idx ON Topics USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS Posts_search_vector_idx ON Posts USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS Comments_search_vector_idx ON Comments USING GIN (search_vector);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS Comments_search_vector_idx;
+DROP INDEX IF EXISTS Posts_search_vector_idx;
+DROP INDEX IF EXISTS Topics_search_vector_idx;
+ALTER TABLE Comments DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE Posts DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE Topics DROP COLUMN IF EXISTS search_vector;
+-- +goose StatementEnd
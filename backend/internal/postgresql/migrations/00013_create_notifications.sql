@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS Notifications (
+    notification_id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    actor_id BIGINT NOT NULL,
+    type TEXT NOT NULL,
+    topic_id BIGINT,
+    post_id BIGINT,
+    comment_id BIGINT,
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT notification_type_valid CHECK (
+        type IN ('post_comment', 'comment_reply', 'mention', 'post_vote', 'comment_vote')
+    ),
+    FOREIGN KEY (user_id) REFERENCES Users(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (actor_id) REFERENCES Users(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (topic_id) REFERENCES Topics(topic_id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES Posts(post_id) ON DELETE CASCADE,
+    FOREIGN KEY (comment_id) REFERENCES Comments(comment_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS Notifications_user_id_idx ON Notifications (user_id, notification_id);
+
+CREATE TABLE IF NOT EXISTS Notification_Preferences (
+    user_id BIGINT NOT NULL,
+    type TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    CONSTRAINT Notification_Preferences_pk PRIMARY KEY (user_id, type),
+    CONSTRAINT notification_preference_type_valid CHECK (
+        type IN ('post_comment', 'comment_reply', 'mention', 'post_vote', 'comment_vote')
+    ),
+    FOREIGN KEY (user_id) REFERENCES Users(user_id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS Notification_Preferences;
+DROP TABLE IF EXISTS Notifications;
+-- +goose StatementEnd
@@ -0,0 +1,70 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS Reports (
+    report_id BIGSERIAL PRIMARY KEY,
+    reporter_id BIGINT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id BIGINT NOT NULL,
+    topic_id BIGINT,
+    reason TEXT NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'open',
+    action TEXT,
+    resolved_by BIGINT,
+    resolution_note TEXT NOT NULL DEFAULT '',
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT report_target_type_valid CHECK (target_type IN ('post', 'comment', 'user')),
+    CONSTRAINT report_reason_valid CHECK (
+        reason IN ('spam', 'harassment', 'hate_speech', 'violence', 'sexual_content', 'misinformation', 'off_topic', 'other')
+    ),
+    CONSTRAINT report_status_valid CHECK (status IN ('open', 'dismissed', 'actioned')),
+    CONSTRAINT report_action_valid CHECK (
+        action IN ('dismiss', 'remove_content', 'warn_author', 'suspend_author')
+    ),
+    FOREIGN KEY (reporter_id) REFERENCES Users(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (topic_id) REFERENCES Topics(topic_id) ON DELETE CASCADE,
+    FOREIGN KEY (resolved_by) REFERENCES Users(user_id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS Reports_open_reporter_target_idx
+ON Reports (reporter_id, target_type, target_id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS Reports_open_target_idx
+ON Reports (target_type, target_id) WHERE status = 'open';
+
+CREATE TABLE IF NOT EXISTS Suspensions (
+    suspension_id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    topic_id BIGINT,
+    reason TEXT NOT NULL,
+    expires_at TIMESTAMPTZ,
+    created_by BIGINT,
+    report_id BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    FOREIGN KEY (user_id) REFERENCES Users(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (topic_id) REFERENCES Topics(topic_id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES Users(user_id) ON DELETE SET NULL,
+    FOREIGN KEY (report_id) REFERENCES Reports(report_id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS Suspensions_user_id_idx ON Suspensions (user_id);
+
+ALTER TABLE Notifications ADD COLUMN message TEXT NOT NULL DEFAULT '';
+ALTER TABLE Notifications DROP CONSTRAINT notification_type_valid;
+ALTER TABLE Notifications ADD CONSTRAINT notification_type_valid CHECK (
+    type IN ('post_comment', 'comment_reply', 'mention', 'post_vote', 'comment_vote', 'moderation_warning')
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM Notifications WHERE type = 'moderation_warning';
+ALTER TABLE Notifications DROP CONSTRAINT notification_type_valid;
+ALTER TABLE Notifications ADD CONSTRAINT notification_type_valid CHECK (
+    type IN ('post_comment', 'comment_reply', 'mention', 'post_vote', 'comment_vote')
+);
+ALTER TABLE Notifications DROP COLUMN IF EXISTS message;
+
+DROP TABLE IF EXISTS Suspensions;
+DROP TABLE IF EXISTS Reports;
+-- +goose StatementEnd
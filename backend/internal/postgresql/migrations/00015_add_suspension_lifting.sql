@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE Suspensions ADD COLUMN lifted_at TIMESTAMPTZ;
+ALTER TABLE Suspensions ADD COLUMN lifted_by BIGINT REFERENCES Users(user_id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE Suspensions DROP COLUMN IF EXISTS lifted_by;
+ALTER TABLE Suspensions DROP COLUMN IF EXISTS lifted_at;
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS Audit_Log (
+    audit_id BIGSERIAL PRIMARY KEY,
+    actor_id BIGINT NOT NULL,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id BIGINT NOT NULL,
+    topic_id BIGINT,
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT audit_target_type_valid CHECK (
+        target_type IN ('topic', 'post', 'comment', 'user', 'report')
+    )
+);
+
+CREATE INDEX IF NOT EXISTS Audit_Log_actor_id_idx ON Audit_Log (actor_id, audit_id);
+CREATE INDEX IF NOT EXISTS Audit_Log_target_idx ON Audit_Log (target_type, target_id, audit_id);
+CREATE INDEX IF NOT EXISTS Audit_Log_topic_id_idx ON Audit_Log (topic_id, audit_id);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'Audit_Log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+BEFORE UPDATE OR DELETE ON Audit_Log
+FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate
+BEFORE TRUNCATE ON Audit_Log
+FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS Audit_Log;
+DROP FUNCTION IF EXISTS audit_log_append_only();
+-- +goose StatementEnd
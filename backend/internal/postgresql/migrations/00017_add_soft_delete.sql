@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE Posts ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE Posts ADD COLUMN deleted_by BIGINT REFERENCES Users(user_id) ON DELETE SET NULL;
+ALTER TABLE Comments ADD COLUMN deleted_by BIGINT REFERENCES Users(user_id) ON DELETE SET NULL;
+
+ALTER TABLE Posts DROP CONSTRAINT posts_title_key;
+CREATE UNIQUE INDEX IF NOT EXISTS Posts_title_idx ON Posts (title) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS Posts_deleted_at_idx ON Posts (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS Comments_deleted_at_idx ON Comments (deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM Posts WHERE deleted_at IS NOT NULL;
+DELETE FROM Comments c WHERE c.deleted_at IS NOT NULL
+AND NOT EXISTS (SELECT 1 FROM Comments r WHERE r.parent_comment_id = c.comment_id);
+UPDATE Comments SET description = '' WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS Comments_deleted_at_idx;
+DROP INDEX IF EXISTS Posts_deleted_at_idx;
+DROP INDEX IF EXISTS Posts_title_idx;
+ALTER TABLE Posts ADD CONSTRAINT posts_title_key UNIQUE (title);
+
+ALTER TABLE Comments DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE Posts DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE Posts DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE Posts ADD COLUMN edit_count INT NOT NULL DEFAULT 0;
+ALTER TABLE Comments ADD COLUMN edit_count INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS Post_Revisions (
+    revision_id BIGSERIAL PRIMARY KEY,
+    post_id BIGINT NOT NULL,
+    version INT NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL,
+    replaced_by BIGINT,
+    created_at TIMESTAMPTZ NOT NULL,
+    replaced_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT Post_Revisions_version_key UNIQUE (post_id, version),
+    FOREIGN KEY (post_id) REFERENCES Posts(post_id) ON DELETE CASCADE,
+    FOREIGN KEY (replaced_by) REFERENCES Users(user_id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS Comment_Revisions (
+    revision_id BIGSERIAL PRIMARY KEY,
+    comment_id BIGINT NOT NULL,
+    version INT NOT NULL,
+    description TEXT NOT NULL,
+    replaced_by BIGINT,
+    created_at TIMESTAMPTZ NOT NULL,
+    replaced_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT Comment_Revisions_version_key UNIQUE (comment_id, version),
+    FOREIGN KEY (comment_id) REFERENCES Comments(comment_id) ON DELETE CASCADE,
+    FOREIGN KEY (replaced_by) REFERENCES Users(user_id) ON DELETE SET NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS Comment_Revisions;
+DROP TABLE IF EXISTS Post_Revisions;
+ALTER TABLE Comments DROP COLUMN IF EXISTS edit_count;
+ALTER TABLE Posts DROP COLUMN IF EXISTS edit_count;
+-- +goose StatementEnd
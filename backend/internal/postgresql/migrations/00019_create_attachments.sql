@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS Attachments (
+    attachment_id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    post_id BIGINT,
+    comment_id BIGINT,
+    storage_key TEXT NOT NULL UNIQUE,
+    thumbnail_key TEXT UNIQUE,
+    filename TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    width INT,
+    height INT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT Attachments_owner_check CHECK ((post_id IS NULL) <> (comment_id IS NULL)),
+    FOREIGN KEY (user_id) REFERENCES Users(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES Posts(post_id) ON DELETE CASCADE,
+    FOREIGN KEY (comment_id) REFERENCES Comments(comment_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS Attachments_post_id_idx ON Attachments (post_id) WHERE post_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS Attachments_comment_id_idx ON Attachments (comment_id) WHERE comment_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS Attachments;
+-- +goose StatementEnd
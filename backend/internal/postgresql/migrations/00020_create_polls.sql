@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS Polls (
+    post_id BIGINT PRIMARY KEY,
+    question TEXT NOT NULL,
+    multiple_choice BOOLEAN NOT NULL DEFAULT false,
+    closes_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    FOREIGN KEY (post_id) REFERENCES Posts(post_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS Poll_Options (
+    option_id BIGSERIAL PRIMARY KEY,
+    post_id BIGINT NOT NULL,
+    position INT NOT NULL,
+    label TEXT NOT NULL,
+    CONSTRAINT Poll_Options_position_key UNIQUE (post_id, position),
+    CONSTRAINT Poll_Options_label_key UNIQUE (post_id, label),
+    FOREIGN KEY (post_id) REFERENCES Polls(post_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS Poll_Ballots (
+    post_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT Poll_Ballots_pk PRIMARY KEY (post_id, user_id),
+    FOREIGN KEY (post_id) REFERENCES Polls(post_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES Users(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS Poll_Ballot_Options (
+    post_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    option_id BIGINT NOT NULL,
+    CONSTRAINT Poll_Ballot_Options_pk PRIMARY KEY (post_id, user_id, option_id),
+    FOREIGN KEY (post_id, user_id) REFERENCES Poll_Ballots(post_id, user_id) ON DELETE CASCADE,
+    FOREIGN KEY (option_id) REFERENCES Poll_Options(option_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS Poll_Ballot_Options_option_id_idx ON Poll_Ballot_Options (option_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS Poll_Ballot_Options;
+DROP TABLE IF EXISTS Poll_Ballots;
+DROP TABLE IF EXISTS Poll_Options;
+DROP TABLE IF EXISTS Polls;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS Bookmarks (
+    bookmark_id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    post_id BIGINT,
+    comment_id BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT Bookmarks_post_key UNIQUE (user_id, post_id),
+    CONSTRAINT Bookmarks_comment_key UNIQUE (user_id, comment_id),
+    CONSTRAINT Bookmarks_target_check CHECK ((post_id IS NULL) <> (comment_id IS NULL)),
+    FOREIGN KEY (user_id) REFERENCES Users(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES Posts(post_id) ON DELETE CASCADE,
+    FOREIGN KEY (comment_id) REFERENCES Comments(comment_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS Bookmarks_post_id_idx ON Bookmarks (post_id) WHERE post_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS Bookmarks_comment_id_idx ON Bookmarks (comment_id) WHERE comment_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS Bookmarks;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS Mentions (
+    mention_id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    post_id BIGINT,
+    comment_id BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT Mentions_post_key UNIQUE (post_id, user_id),
+    CONSTRAINT Mentions_comment_key UNIQUE (comment_id, user_id),
+    CONSTRAINT Mentions_target_check CHECK ((post_id IS NULL) <> (comment_id IS NULL)),
+    FOREIGN KEY (user_id) REFERENCES Users(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES Posts(post_id) ON DELETE CASCADE,
+    FOREIGN KEY (comment_id) REFERENCES Comments(comment_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS Mentions_user_id_idx ON Mentions (user_id, mention_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS Mentions;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Databases migrated before 00008 was fixed gave every existing user the hash of the same published
+-- temporary password. Users who have not changed it yet are left without a password instead.
+ALTER TABLE Users ALTER COLUMN password_hash DROP NOT NULL;
+
+UPDATE Users SET password_hash = NULL
+WHERE password_hash = '$2a$10$D/ChgCQtjoPnrN28dPDuDer3RQp297j/JjbJxztKGRCk57Ow.A/Gm';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- The shared password is not restored, so users without a password keep none.
+SELECT 1;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Databases migrated before 00010 was fixed made the seeded admin user an admin. Unless it has been
+-- given a password since, it is demoted, and the first admin is granted with the grant-admin command.
+UPDATE Users SET role = 'member' WHERE name = 'admin' AND role = 'admin' AND password_hash IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- The role is not granted back, as roles are only granted with the grant-admin command.
+SELECT 1;
+-- +goose StatementEnd
@@ -0,0 +1,73 @@
+-- +goose Up
+-- +goose StatementBegin
+-- search_results returns the topics, posts, comments and users that match the search query and the
+-- optional filters, so that the search and its count share one definition. Users have no creation
+-- date, so the date filters do not apply to them.
+CREATE OR REPLACE FUNCTION search_results(
+    search_query TEXT,
+    search_type TEXT,
+    search_author TEXT,
+    search_topic_id BIGINT,
+    search_after TIMESTAMPTZ,
+    search_before TIMESTAMPTZ,
+    search_min_score BIGINT
+)
+RETURNS TABLE (
+    result_type TEXT,
+    result_id BIGINT,
+    topic_id BIGINT,
+    post_id BIGINT,
+    user_id BIGINT,
+    username TEXT,
+    title TEXT,
+    content TEXT,
+    score BIGINT,
+    rank REAL,
+    created_at TIMESTAMPTZ
+)
+LANGUAGE sql STABLE AS $$
+SELECT t.result_type, t.result_id, t.topic_id, t.post_id, t.user_id, t.username, t.title,
+t.content, t.score, t.rank, t.created_at
+FROM (
+    SELECT 'user'::text AS result_type, u.user_id AS result_id, NULL::bigint AS topic_id,
+    NULL::bigint AS post_id, u.user_id, u.name AS username, NULL::text AS title,
+    u.name AS content, 0::bigint AS score,
+    (CASE WHEN lower(u.name) = lower(search_query) THEN 1 ELSE 0.1 END)::real AS rank,
+    NULL::timestamptz AS created_at
+    FROM Users u
+    WHERE strpos(lower(u.name), lower(search_query)) > 0
+    UNION ALL
+    SELECT 'topic', t.topic_id, t.topic_id, NULL, t.user_id, u.name, t.title, t.title, 0,
+    ts_rank(t.search_vector, websearch_to_tsquery('english', search_query)), t.created_at
+    FROM Topics t
+    JOIN Users u ON u.user_id = t.user_id
+    WHERE t.search_vector @@ websearch_to_tsquery('english', search_query)
+    UNION ALL
+    SELECT 'post', p.post_id, p.topic_id, p.post_id, p.user_id, u.name, p.title, p.description,
+    (SELECT COALESCE(SUM(v.vote), 0) FROM Post_Votes v WHERE v.post_id = p.post_id),
+    ts_rank(p.search_vector, websearch_to_tsquery('english', search_query)), p.created_at
+    FROM Posts p
+    JOIN Users u ON u.user_id = p.user_id
+    WHERE p.deleted_at IS NULL AND p.search_vector @@ websearch_to_tsquery('english', search_query)
+    UNION ALL
+    SELECT 'comment', c.comment_id, p.topic_id, c.post_id, c.user_id, u.name, p.title, c.description,
+    (SELECT COALESCE(SUM(v.vote), 0) FROM Comment_Votes v WHERE v.comment_id = c.comment_id),
+    ts_rank(c.search_vector, websearch_to_tsquery('english', search_query)), c.created_at
+    FROM Comments c
+    JOIN Posts p ON p.post_id = c.post_id
+    JOIN Users u ON u.user_id = c.user_id
+    WHERE c.deleted_at IS NULL AND p.deleted_at IS NULL AND c.search_vector @@ websearch_to_tsquery('english', search_query)
+) AS t
+WHERE (search_type IS NULL OR t.result_type = search_type)
+AND (search_author IS NULL OR t.username = search_author)
+AND (search_topic_id IS NULL OR t.topic_id = search_topic_id)
+AND (search_after IS NULL OR t.result_type = 'user' OR t.created_at >= search_after)
+AND (search_before IS NULL OR t.result_type = 'user' OR t.created_at < search_before)
+AND (search_min_score IS NULL OR t.score >= search_min_score)
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS search_results(TEXT, TEXT, TEXT, BIGINT, TIMESTAMPTZ, TIMESTAMPTZ, BIGINT);
+-- +goose StatementEnd
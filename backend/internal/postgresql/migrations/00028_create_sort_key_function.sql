@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- sort_key returns the value that posts and comments are ordered by, in descending order, for the
+-- given sort: new, top, hot, controversial, active, or the number of likes by default.
+CREATE OR REPLACE FUNCTION sort_key(
+    sort TEXT,
+    likes BIGINT,
+    dislikes BIGINT,
+    created_at TIMESTAMPTZ,
+    active_at TIMESTAMPTZ
+)
+RETURNS FLOAT8
+LANGUAGE sql STABLE AS $$
+SELECT (CASE sort
+    WHEN 'new' THEN extract(epoch FROM created_at)
+    WHEN 'top' THEN likes - dislikes
+    WHEN 'hot' THEN sign(likes - dislikes) * log(greatest(abs(likes - dislikes), 1))
+        + (extract(epoch FROM created_at) - 1134028003) / 45000
+    WHEN 'controversial' THEN CASE WHEN likes = 0 OR dislikes = 0 THEN 0
+        ELSE power(likes + dislikes, least(likes, dislikes)::float8 / greatest(likes, dislikes))
+    END
+    WHEN 'active' THEN extract(epoch FROM active_at)
+    ELSE likes
+END)::float8
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS sort_key(TEXT, BIGINT, BIGINT, TIMESTAMPTZ, TIMESTAMPTZ);
+-- +goose StatementEnd
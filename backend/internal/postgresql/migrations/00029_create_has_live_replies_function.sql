@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- has_live_replies returns true if any reply under the comment, at any depth, has not been deleted,
+-- so that a deleted comment is only shown as a tombstone while its thread still has live replies.
+CREATE OR REPLACE FUNCTION has_live_replies(parent_id BIGINT)
+RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+WITH RECURSIVE replies AS (
+    SELECT comment_id, deleted_at FROM Comments WHERE parent_comment_id = parent_id
+    UNION ALL
+    SELECT c.comment_id, c.deleted_at FROM Comments c
+    JOIN replies r ON c.parent_comment_id = r.comment_id
+)
+SELECT EXISTS (SELECT 1 FROM replies WHERE deleted_at IS NULL)
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP FUNCTION IF EXISTS has_live_replies(BIGINT);
+-- +goose StatementEnd
@@ -8,13 +8,63 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type Attachment struct {
+	AttachmentID int64              `json:"attachment_id"`
+	UserID       int64              `json:"user_id"`
+	PostID       pgtype.Int8        `json:"post_id"`
+	CommentID    pgtype.Int8        `json:"comment_id"`
+	StorageKey   string             `json:"storage_key"`
+	ThumbnailKey pgtype.Text        `json:"thumbnail_key"`
+	Filename     string             `json:"filename"`
+	ContentType  string             `json:"content_type"`
+	SizeBytes    int64              `json:"size_bytes"`
+	Width        pgtype.Int4        `json:"width"`
+	Height       pgtype.Int4        `json:"height"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type AuditLog struct {
+	AuditID    int64              `json:"audit_id"`
+	ActorID    int64              `json:"actor_id"`
+	Action     string             `json:"action"`
+	TargetType string             `json:"target_type"`
+	TargetID   int64              `json:"target_id"`
+	TopicID    pgtype.Int8        `json:"topic_id"`
+	Before     []byte             `json:"before"`
+	After      []byte             `json:"after"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type Bookmark struct {
+	BookmarkID int64              `json:"bookmark_id"`
+	UserID     int64              `json:"user_id"`
+	PostID     pgtype.Int8        `json:"post_id"`
+	CommentID  pgtype.Int8        `json:"comment_id"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
 type Comment struct {
+	CommentID       int64              `json:"comment_id"`
+	PostID          int64              `json:"post_id"`
+	UserID          int64              `json:"user_id"`
+	Description     string             `json:"description"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	ParentCommentID pgtype.Int8        `json:"parent_comment_id"`
+	DeletedAt       pgtype.Timestamptz `json:"deleted_at"`
+	SearchVector    interface{}        `json:"-"`
+	DeletedBy       pgtype.Int8        `json:"deleted_by"`
+	EditCount       int32              `json:"edit_count"`
+}
+
+type CommentRevision struct {
+	RevisionID  int64              `json:"revision_id"`
 	CommentID   int64              `json:"comment_id"`
-	PostID      int64              `json:"post_id"`
-	UserID      int64              `json:"user_id"`
+	Version     int32              `json:"version"`
 	Description string             `json:"description"`
+	ReplacedBy  pgtype.Int8        `json:"replaced_by"`
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	ReplacedAt  pgtype.Timestamptz `json:"replaced_at"`
 }
 
 type CommentVote struct {
@@ -24,14 +74,83 @@ type CommentVote struct {
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
+type Mention struct {
+	MentionID int64              `json:"mention_id"`
+	UserID    int64              `json:"user_id"`
+	PostID    pgtype.Int8        `json:"post_id"`
+	CommentID pgtype.Int8        `json:"comment_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type Notification struct {
+	NotificationID int64              `json:"notification_id"`
+	UserID         int64              `json:"user_id"`
+	ActorID        int64              `json:"actor_id"`
+	Type           string             `json:"type"`
+	TopicID        pgtype.Int8        `json:"topic_id"`
+	PostID         pgtype.Int8        `json:"post_id"`
+	CommentID      pgtype.Int8        `json:"comment_id"`
+	ReadAt         pgtype.Timestamptz `json:"read_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	Message        string             `json:"message"`
+}
+
+type NotificationPreference struct {
+	UserID  int64  `json:"user_id"`
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+}
+
 type Post struct {
+	PostID       int64              `json:"post_id"`
+	TopicID      int64              `json:"topic_id"`
+	UserID       int64              `json:"user_id"`
+	Title        string             `json:"title"`
+	Description  string             `json:"description"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	SearchVector interface{}        `json:"-"`
+	DeletedAt    pgtype.Timestamptz `json:"deleted_at"`
+	DeletedBy    pgtype.Int8        `json:"deleted_by"`
+	EditCount    int32              `json:"edit_count"`
+}
+
+type Poll struct {
+	PostID         int64              `json:"post_id"`
+	Question       string             `json:"question"`
+	MultipleChoice bool               `json:"multiple_choice"`
+	ClosesAt       pgtype.Timestamptz `json:"closes_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+type PollBallot struct {
+	PostID    int64              `json:"post_id"`
+	UserID    int64              `json:"user_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type PollBallotOption struct {
+	PostID   int64 `json:"post_id"`
+	UserID   int64 `json:"user_id"`
+	OptionID int64 `json:"option_id"`
+}
+
+type PollOption struct {
+	OptionID int64  `json:"option_id"`
+	PostID   int64  `json:"post_id"`
+	Position int32  `json:"position"`
+	Label    string `json:"label"`
+}
+
+type PostRevision struct {
+	RevisionID  int64              `json:"revision_id"`
 	PostID      int64              `json:"post_id"`
-	TopicID     int64              `json:"topic_id"`
-	UserID      int64              `json:"user_id"`
+	Version     int32              `json:"version"`
 	Title       string             `json:"title"`
 	Description string             `json:"description"`
+	ReplacedBy  pgtype.Int8        `json:"replaced_by"`
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	ReplacedAt  pgtype.Timestamptz `json:"replaced_at"`
 }
 
 type PostVote struct {
@@ -41,14 +160,76 @@ type PostVote struct {
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
+type RefreshToken struct {
+	TokenID   int64              `json:"token_id"`
+	UserID    int64              `json:"user_id"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	RevokedAt pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type RevokedToken struct {
+	Jti       string             `json:"jti"`
+	UserID    int64              `json:"user_id"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type RateLimit struct {
+	BucketKey string             `json:"bucket_key"`
+	Tokens    float64            `json:"tokens"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Report struct {
+	ReportID       int64              `json:"report_id"`
+	ReporterID     int64              `json:"reporter_id"`
+	TargetType     string             `json:"target_type"`
+	TargetID       int64              `json:"target_id"`
+	TopicID        pgtype.Int8        `json:"topic_id"`
+	Reason         string             `json:"reason"`
+	Details        string             `json:"details"`
+	Status         string             `json:"status"`
+	Action         pgtype.Text        `json:"action"`
+	ResolvedBy     pgtype.Int8        `json:"resolved_by"`
+	ResolutionNote string             `json:"resolution_note"`
+	ResolvedAt     pgtype.Timestamptz `json:"resolved_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+type Suspension struct {
+	SuspensionID int64              `json:"suspension_id"`
+	UserID       int64              `json:"user_id"`
+	TopicID      pgtype.Int8        `json:"topic_id"`
+	Reason       string             `json:"reason"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
+	CreatedBy    pgtype.Int8        `json:"created_by"`
+	ReportID     pgtype.Int8        `json:"report_id"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	LiftedAt     pgtype.Timestamptz `json:"lifted_at"`
+	LiftedBy     pgtype.Int8        `json:"lifted_by"`
+}
+
 type Topic struct {
+	TopicID      int64              `json:"topic_id"`
+	UserID       int64              `json:"user_id"`
+	Title        string             `json:"title"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	SearchVector interface{}        `json:"-"`
+}
+
+type TopicModerator struct {
 	TopicID   int64              `json:"topic_id"`
 	UserID    int64              `json:"user_id"`
-	Title     string             `json:"title"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
 type User struct {
-	UserID int64  `json:"user_id"`
-	Name   string `json:"name"`
+	UserID             int64              `json:"user_id"`
+	Name               string             `json:"name"`
+	PasswordHash       pgtype.Text        `json:"password_hash"`
+	MustChangePassword bool               `json:"must_change_password"`
+	TokensRevokedAt    pgtype.Timestamptz `json:"tokens_revoked_at"`
+	Role               string             `json:"role"`
 }
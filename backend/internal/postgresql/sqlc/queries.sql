@@ -6,11 +6,77 @@ SELECT * FROM Users WHERE name = $1;
 SELECT * FROM Users WHERE user_id = $1;
 
 -- name: CreateUser :one
-INSERT INTO Users (name) VALUES ($1) RETURNING *;
+INSERT INTO Users (name, password_hash) VALUES ($1, $2) RETURNING *;
+
+-- name: UpdateUserPassword :one
+UPDATE Users SET password_hash = $2, must_change_password = false WHERE user_id = $1 RETURNING *;
+
+-- name: ResetUserPassword :one
+UPDATE Users SET password_hash = $2, must_change_password = true WHERE name = $1 RETURNING *;
+
+-- name: RevokeUserTokens :exec
+UPDATE Users SET tokens_revoked_at = now() WHERE user_id = $1;
+
+-- Tokens Queries
+-- name: CreateRefreshToken :one
+INSERT INTO Refresh_Tokens (user_id, token_hash, expires_at) VALUES ($1, $2, $3) RETURNING *;
+
+-- name: FindRefreshToken :one
+SELECT * FROM Refresh_Tokens WHERE token_hash = $1;
+
+-- name: RevokeRefreshToken :execrows
+UPDATE Refresh_Tokens SET revoked_at = now() WHERE token_id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeUserRefreshTokens :exec
+UPDATE Refresh_Tokens SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeAccessToken :exec
+INSERT INTO Revoked_Tokens (jti, user_id, expires_at) VALUES ($1, $2, $3) ON CONFLICT (jti) DO NOTHING;
+
+-- name: IsTokenRevoked :one
+SELECT (
+    EXISTS (SELECT 1 FROM Revoked_Tokens WHERE jti = sqlc.arg(jti))
+    OR EXISTS (
+        SELECT 1 FROM Users
+        WHERE user_id = sqlc.arg(user_id) AND date_trunc('second', tokens_revoked_at) > sqlc.arg(issued_at)::timestamptz
+    )
+)::boolean AS revoked;
+
+-- name: PurgeRevokedTokens :execrows
+DELETE FROM Revoked_Tokens WHERE expires_at < now();
+
+-- Roles Queries
+-- name: UpdateUserRole :one
+UPDATE Users SET role = $2 WHERE user_id = $1 RETURNING *;
+
+-- name: ListTopicModerators :many
+SELECT m.topic_id, m.user_id, u.name AS username, m.created_at
+FROM Topic_Moderators m
+JOIN Users u ON u.user_id = m.user_id
+WHERE m.topic_id = $1
+ORDER BY u.name;
+
+-- name: AddTopicModerator :exec
+INSERT INTO Topic_Moderators (topic_id, user_id) VALUES ($1, $2) ON CONFLICT (topic_id, user_id) DO NOTHING;
+
+-- name: RemoveTopicModerator :execrows
+DELETE FROM Topic_Moderators WHERE topic_id = $1 AND user_id = $2;
+
+-- name: CanModerateTopic :one
+SELECT (
+    EXISTS (SELECT 1 FROM Users WHERE user_id = $1 AND role IN ('admin', 'moderator'))
+    OR EXISTS (SELECT 1 FROM Topic_Moderators WHERE user_id = $1 AND topic_id = $2)
+)::boolean AS can_moderate;
 
 -- Topics Queries
 -- name: ListTopics :many
-SELECT * FROM Topics ORDER BY title;
+SELECT * FROM Topics
+WHERE sqlc.narg(cursor_title)::text IS NULL OR title > sqlc.narg(cursor_title)::text
+ORDER BY title
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountTopics :one
+SELECT COUNT(*) FROM Topics;
 
 -- name: FindTopicByID :one
 SELECT * FROM Topics WHERE topic_id = $1;
@@ -19,87 +85,380 @@ SELECT * FROM Topics WHERE topic_id = $1;
 INSERT INTO Topics (user_id, title) VALUES ($1, $2) RETURNING *;
 
 -- name: UpdateTopic :one
-UPDATE Topics SET title = $3 WHERE topic_id = $1 AND user_id = $2 RETURNING *;
+UPDATE Topics SET title = $2 WHERE topic_id = $1 RETURNING *;
 
 -- name: DeleteTopic :execrows
-DELETE FROM Topics WHERE topic_id = $1 AND user_id = $2;
+DELETE FROM Topics WHERE topic_id = $1;
+
+-- name: DeleteTopicAttachments :many
+DELETE FROM Attachments a
+WHERE a.post_id IN (SELECT post_id FROM Posts WHERE topic_id = $1)
+OR a.comment_id IN (
+    SELECT c.comment_id FROM Comments c
+    JOIN Posts p ON p.post_id = c.post_id
+    WHERE p.topic_id = $1
+)
+RETURNING a.storage_key, a.thumbnail_key;
+
+-- name: SubscribeTopic :exec
+INSERT INTO Topic_Subscriptions (user_id, topic_id) VALUES ($1, $2)
+ON CONFLICT (user_id, topic_id) DO NOTHING;
+
+-- name: UnsubscribeTopic :execrows
+DELETE FROM Topic_Subscriptions WHERE user_id = $1 AND topic_id = $2;
+
+-- name: ListSubscribedTopics :many
+SELECT t.* FROM Topics t
+JOIN Topic_Subscriptions s ON s.topic_id = t.topic_id
+WHERE s.user_id = sqlc.arg(user_id)
+AND (sqlc.narg(cursor_title)::text IS NULL OR t.title > sqlc.narg(cursor_title)::text)
+ORDER BY t.title
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountSubscribedTopics :one
+SELECT COUNT(*) FROM Topic_Subscriptions WHERE user_id = $1;
 
 -- name: SearchTopic :many
-SELECT * FROM Topics WHERE title ILIKE '%' || $1 ||'%' ORDER BY title;
+SELECT t.topic_id, t.user_id, t.title, t.created_at, t.rank,
+ts_headline('english', t.title, websearch_to_tsquery('english', sqlc.arg(query)::text),
+    'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS headline
+FROM (
+    SELECT topic_id, user_id, title, created_at,
+    ts_rank(search_vector, websearch_to_tsquery('english', sqlc.arg(query)::text)) AS rank
+    FROM Topics
+    WHERE search_vector @@ websearch_to_tsquery('english', sqlc.arg(query)::text)
+) AS t
+WHERE sqlc.narg(cursor_topic_id)::bigint IS NULL
+OR (t.rank, t.topic_id) < (sqlc.narg(cursor_rank)::real, sqlc.narg(cursor_topic_id)::bigint)
+ORDER BY t.rank DESC, t.topic_id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountSearchTopic :one
+SELECT COUNT(*) FROM Topics
+WHERE search_vector @@ websearch_to_tsquery('english', sqlc.arg(query)::text);
 
 -- Posts Queries
 -- name: FindPostsByTopic :many
-SELECT DISTINCT p.post_id, p.topic_id, p.user_id, u.name AS username,
-p.title, p.description, p.created_at, p.updated_at,
-COUNT(v.vote) FILTER (WHERE v.vote = 1) OVER (PARTITION BY p.post_id) AS likes,
-COUNT(v.vote) FILTER (WHERE v.vote = -1) OVER (PARTITION BY p.post_id) AS dislikes,
-MAX(uv.vote) OVER (PARTITION BY p.post_id) AS user_vote
-FROM Posts p
-JOIN Users u ON u.user_id = p.user_id
-LEFT JOIN Post_Votes v ON p.post_id = v.post_id
-LEFT JOIN Post_Votes uv ON p.post_id = uv.post_id AND uv.user_id = $2
-WHERE p.topic_id = $1
-ORDER BY likes DESC, p.updated_at DESC;
+SELECT * FROM (
+    SELECT a.*, sort_key(sqlc.arg(sort)::text, a.likes, a.dislikes, a.created_at, a.last_activity_at) AS sort_key
+    FROM (
+        SELECT p.post_id, p.topic_id, p.user_id, u.name AS username,
+        p.title, p.description, p.created_at, p.updated_at, p.edit_count,
+        COUNT(v.vote) FILTER (WHERE v.vote = 1) AS likes,
+        COUNT(v.vote) FILTER (WHERE v.vote = -1) AS dislikes,
+        MAX(uv.vote) AS user_vote,
+        EXISTS (SELECT 1 FROM Bookmarks s WHERE s.post_id = p.post_id AND s.user_id = sqlc.arg(user_id)) AS saved,
+        (SELECT COALESCE(MAX(c.created_at), p.created_at) FROM Comments c
+        WHERE c.post_id = p.post_id AND c.deleted_at IS NULL)::timestamptz AS last_activity_at
+        FROM Posts p
+        JOIN Users u ON u.user_id = p.user_id
+        LEFT JOIN Post_Votes v ON p.post_id = v.post_id
+        LEFT JOIN Post_Votes uv ON p.post_id = uv.post_id AND uv.user_id = sqlc.arg(user_id)
+        WHERE p.topic_id = sqlc.arg(topic_id)
+        AND (sqlc.narg(since)::timestamptz IS NULL OR p.created_at >= sqlc.narg(since)::timestamptz)
+        AND p.deleted_at IS NULL
+        GROUP BY p.post_id, u.name
+    ) AS a
+) AS t
+WHERE sqlc.narg(cursor_post_id)::bigint IS NULL
+OR (t.sort_key, t.updated_at, t.post_id) < (
+    sqlc.narg(cursor_sort_key)::float8,
+    sqlc.narg(cursor_updated_at)::timestamptz,
+    sqlc.narg(cursor_post_id)::bigint
+)
+ORDER BY t.sort_key DESC, t.updated_at DESC, t.post_id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountPostsByTopic :one
+SELECT COUNT(*) FROM Posts
+WHERE deleted_at IS NULL AND topic_id = sqlc.arg(topic_id)
+AND (sqlc.narg(since)::timestamptz IS NULL OR created_at >= sqlc.narg(since)::timestamptz);
+
+-- name: FindFeed :many
+SELECT * FROM (
+    SELECT a.*, sort_key(sqlc.arg(sort)::text, a.likes, a.dislikes, a.created_at, a.last_activity_at) AS sort_key
+    FROM (
+        SELECT p.post_id, p.topic_id, p.user_id, u.name AS username,
+        p.title, p.description, p.created_at, p.updated_at, p.edit_count,
+        COUNT(v.vote) FILTER (WHERE v.vote = 1) AS likes,
+        COUNT(v.vote) FILTER (WHERE v.vote = -1) AS dislikes,
+        MAX(uv.vote) AS user_vote,
+        EXISTS (SELECT 1 FROM Bookmarks s WHERE s.post_id = p.post_id AND s.user_id = sqlc.arg(user_id)) AS saved,
+        (SELECT COALESCE(MAX(c.created_at), p.created_at) FROM Comments c
+        WHERE c.post_id = p.post_id AND c.deleted_at IS NULL)::timestamptz AS last_activity_at
+        FROM Posts p
+        JOIN Users u ON u.user_id = p.user_id
+        LEFT JOIN Post_Votes v ON p.post_id = v.post_id
+        LEFT JOIN Post_Votes uv ON p.post_id = uv.post_id AND uv.user_id = sqlc.arg(user_id)
+        WHERE (p.topic_id IN (SELECT ts.topic_id FROM Topic_Subscriptions ts WHERE ts.user_id = sqlc.arg(user_id))
+        OR NOT EXISTS (SELECT 1 FROM Topic_Subscriptions ts WHERE ts.user_id = sqlc.arg(user_id)))
+        AND (sqlc.narg(since)::timestamptz IS NULL OR p.created_at >= sqlc.narg(since)::timestamptz)
+        AND p.deleted_at IS NULL
+        GROUP BY p.post_id, u.name
+    ) AS a
+) AS t
+WHERE sqlc.narg(cursor_post_id)::bigint IS NULL
+OR (t.sort_key, t.updated_at, t.post_id) < (
+    sqlc.narg(cursor_sort_key)::float8,
+    sqlc.narg(cursor_updated_at)::timestamptz,
+    sqlc.narg(cursor_post_id)::bigint
+)
+ORDER BY t.sort_key DESC, t.updated_at DESC, t.post_id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountFeed :one
+SELECT COUNT(*) FROM Posts p
+WHERE p.deleted_at IS NULL
+AND (p.topic_id IN (SELECT ts.topic_id FROM Topic_Subscriptions ts WHERE ts.user_id = sqlc.arg(user_id))
+OR NOT EXISTS (SELECT 1 FROM Topic_Subscriptions ts WHERE ts.user_id = sqlc.arg(user_id)))
+AND (sqlc.narg(since)::timestamptz IS NULL OR p.created_at >= sqlc.narg(since)::timestamptz);
 
 -- name: FindPostByID :one
 SELECT DISTINCT p.post_id, p.topic_id, p.user_id, u.name AS username,
-p.title, p.description, p.created_at, p.updated_at,
+p.title, p.description, p.created_at, p.updated_at, p.edit_count,
 COUNT(v.vote) FILTER (WHERE v.vote = 1) OVER (PARTITION BY p.post_id) AS likes,
 COUNT(v.vote) FILTER (WHERE v.vote = -1) OVER (PARTITION BY p.post_id) AS dislikes,
-MAX(uv.vote) OVER (PARTITION BY p.post_id) AS user_vote
+MAX(uv.vote) OVER (PARTITION BY p.post_id) AS user_vote,
+EXISTS (SELECT 1 FROM Bookmarks s WHERE s.post_id = p.post_id AND s.user_id = $3) AS saved
 FROM Posts p
 JOIN Users u ON u.user_id = p.user_id
 LEFT JOIN Post_Votes v ON p.post_id = v.post_id
 LEFT JOIN Post_Votes uv ON p.post_id = uv.post_id AND uv.user_id = $3
-WHERE p.post_id = $1 AND p.topic_id = $2;
+WHERE p.post_id = $1 AND p.topic_id = $2 AND p.deleted_at IS NULL;
 
 -- name: CreatePost :one
 INSERT INTO Posts (topic_id, user_id, title, description) VALUES ($1, $2, $3, $4) RETURNING *;
 
+-- name: FindPostAuthor :one
+SELECT post_id, topic_id, user_id FROM Posts WHERE post_id = $1 AND deleted_at IS NULL;
+
 -- name: UpdatePost :one
-UPDATE Posts SET title = $3, description = $4, updated_at = now() WHERE post_id = $1 AND user_id = $2 RETURNING *;
+UPDATE Posts SET title = $2, description = $3, updated_at = now(), edit_count = edit_count + 1 WHERE post_id = $1 AND deleted_at IS NULL RETURNING *;
+
+-- name: CreatePostRevision :exec
+INSERT INTO Post_Revisions (post_id, version, title, description, replaced_by, created_at)
+SELECT post_id, edit_count + 1, title, description, sqlc.arg(replaced_by)::bigint, updated_at
+FROM Posts WHERE post_id = sqlc.arg(post_id) AND deleted_at IS NULL
+FOR UPDATE;
+
+-- name: ListPostRevisions :many
+SELECT r.version, r.title, r.description, r.replaced_by, COALESCE(u.name, '') AS replaced_by_name,
+r.created_at, r.replaced_at
+FROM Post_Revisions r
+LEFT JOIN Users u ON u.user_id = r.replaced_by
+WHERE r.post_id = $1
+ORDER BY r.version DESC;
+
+-- name: FindPostRevision :one
+SELECT r.version, r.title, r.description, r.replaced_by, COALESCE(u.name, '') AS replaced_by_name,
+r.created_at, r.replaced_at
+FROM Post_Revisions r
+LEFT JOIN Users u ON u.user_id = r.replaced_by
+WHERE r.post_id = $1 AND r.version = $2;
 
 -- name: UpdatePostStatus :exec
 UPDATE Posts SET updated_at = now() WHERE post_id = $1 RETURNING *;
 
 -- name: DeletePost :execrows
-DELETE FROM Posts WHERE post_id = $1 AND user_id = $2;
+UPDATE Posts SET deleted_at = now(), deleted_by = $2 WHERE post_id = $1 AND deleted_at IS NULL;
+
+-- name: FindDeletedPost :one
+SELECT post_id, topic_id, user_id, deleted_at, deleted_by FROM Posts
+WHERE post_id = $1 AND deleted_at IS NOT NULL;
+
+-- name: RestorePost :one
+UPDATE Posts SET deleted_at = NULL, deleted_by = NULL WHERE post_id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
 
 -- name: SearchPost :many
-SELECT DISTINCT p.post_id, p.topic_id, p.user_id, u.name AS username,
-p.title, p.description, p.created_at, p.updated_at,
-COUNT(v.vote) FILTER (WHERE v.vote = 1) OVER (PARTITION BY p.post_id)AS likes,
-COUNT(v.vote) FILTER (WHERE v.vote = -1) OVER (PARTITION BY p.post_id)AS dislikes,
-MAX(uv.vote) OVER (PARTITION BY p.post_id)AS user_vote
-FROM Posts p
-JOIN Users u ON u.user_id = p.user_id
-LEFT JOIN Post_Votes v ON p.post_id = v.post_id
-LEFT JOIN Post_Votes uv ON p.post_id = uv.post_id AND uv.user_id = $3
-WHERE p.topic_id = $1 AND (p.title ILIKE '%' || $2 ||'%' OR p.description ILIKE '%' || $2 ||'%')
-ORDER BY likes DESC, p.updated_at DESC;
+SELECT t.post_id, t.topic_id, t.user_id, t.username, t.title, t.description, t.created_at,
+t.updated_at, t.edit_count, t.likes, t.dislikes, t.user_vote, t.saved, t.rank,
+ts_headline('english', t.description, websearch_to_tsquery('english', sqlc.arg(query)::text),
+    'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10') AS headline
+FROM (
+    SELECT p.post_id, p.topic_id, p.user_id, u.name AS username,
+    p.title, p.description, p.created_at, p.updated_at, p.edit_count,
+    COUNT(v.vote) FILTER (WHERE v.vote = 1) AS likes,
+    COUNT(v.vote) FILTER (WHERE v.vote = -1) AS dislikes,
+    MAX(uv.vote) AS user_vote,
+    EXISTS (SELECT 1 FROM Bookmarks s WHERE s.post_id = p.post_id AND s.user_id = sqlc.arg(user_id)) AS saved,
+    ts_rank(p.search_vector, websearch_to_tsquery('english', sqlc.arg(query)::text)) AS rank
+    FROM Posts p
+    JOIN Users u ON u.user_id = p.user_id
+    LEFT JOIN Post_Votes v ON p.post_id = v.post_id
+    LEFT JOIN Post_Votes uv ON p.post_id = uv.post_id AND uv.user_id = sqlc.arg(user_id)
+    WHERE p.topic_id = sqlc.arg(topic_id)
+    AND p.search_vector @@ websearch_to_tsquery('english', sqlc.arg(query)::text)
+    AND p.deleted_at IS NULL
+    GROUP BY p.post_id, u.name
+) AS t
+WHERE sqlc.narg(cursor_post_id)::bigint IS NULL
+OR (t.rank, t.post_id) < (sqlc.narg(cursor_rank)::real, sqlc.narg(cursor_post_id)::bigint)
+ORDER BY t.rank DESC, t.post_id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountSearchPost :one
+SELECT COUNT(*) FROM Posts
+WHERE deleted_at IS NULL AND topic_id = sqlc.arg(topic_id)
+AND search_vector @@ websearch_to_tsquery('english', sqlc.arg(query)::text);
 
 -- Comments Queries
 -- name: FindCommentsByPost :many
-SELECT DISTINCT c.comment_id, c.user_id, u.name as username, c.post_id, c.description, c.created_at,
-c.updated_at, COUNT(v.vote) FILTER (WHERE v.vote = 1) OVER (PARTITION BY c.comment_id) AS likes,
-COUNT(v.vote) FILTER (WHERE v.vote = -1) OVER (PARTITION BY c.comment_id) AS dislikes,
-MAX(uv.vote) OVER (PARTITION BY c.comment_id) AS user_vote
-FROM Comments c
-JOIN Users u ON u.user_id = c.user_id
-LEFT JOIN Comment_Votes v ON c.comment_id = v.comment_id
-LEFT JOIN Comment_Votes uv ON c.comment_id = uv.comment_id AND uv.user_id = $2
-WHERE c.post_id = $1
-ORDER BY likes DESC, c.updated_at DESC;
+SELECT * FROM (
+    SELECT a.*, sort_key(sqlc.arg(sort)::text, a.likes, a.dislikes, a.created_at, a.last_activity_at) AS sort_key
+    FROM (
+        SELECT c.comment_id, c.user_id, u.name as username, c.post_id, c.parent_comment_id,
+        c.description, c.created_at, c.updated_at, c.edit_count, c.deleted_at,
+        COUNT(v.vote) FILTER (WHERE v.vote = 1) AS likes,
+        COUNT(v.vote) FILTER (WHERE v.vote = -1) AS dislikes,
+        MAX(uv.vote) AS user_vote,
+        EXISTS (SELECT 1 FROM Bookmarks s WHERE s.comment_id = c.comment_id AND s.user_id = sqlc.arg(user_id)) AS saved,
+        (SELECT COALESCE(MAX(r.created_at), c.created_at) FROM Comments r
+        WHERE r.parent_comment_id = c.comment_id AND r.deleted_at IS NULL)::timestamptz AS last_activity_at
+        FROM Comments c
+        JOIN Users u ON u.user_id = c.user_id
+        LEFT JOIN Comment_Votes v ON c.comment_id = v.comment_id
+        LEFT JOIN Comment_Votes uv ON c.comment_id = uv.comment_id AND uv.user_id = sqlc.arg(user_id)
+        WHERE c.post_id = sqlc.arg(post_id) AND c.parent_comment_id IS NULL
+        AND (sqlc.narg(since)::timestamptz IS NULL OR c.created_at >= sqlc.narg(since)::timestamptz)
+        AND (c.deleted_at IS NULL OR has_live_replies(c.comment_id))
+        GROUP BY c.comment_id, u.name
+    ) AS a
+) AS t
+WHERE sqlc.narg(cursor_comment_id)::bigint IS NULL
+OR (t.sort_key, t.updated_at, t.comment_id) < (
+    sqlc.narg(cursor_sort_key)::float8,
+    sqlc.narg(cursor_updated_at)::timestamptz,
+    sqlc.narg(cursor_comment_id)::bigint
+)
+ORDER BY t.sort_key DESC, t.updated_at DESC, t.comment_id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountCommentsByPost :one
+SELECT COUNT(*) FROM Comments c
+WHERE post_id = sqlc.arg(post_id) AND parent_comment_id IS NULL
+AND (deleted_at IS NULL OR has_live_replies(c.comment_id))
+AND (sqlc.narg(since)::timestamptz IS NULL OR created_at >= sqlc.narg(since)::timestamptz);
+
+-- name: SearchComment :many
+SELECT t.comment_id, t.user_id, t.username, t.post_id, t.parent_comment_id, t.description,
+t.created_at, t.updated_at, t.edit_count, t.likes, t.dislikes, t.user_vote, t.saved, t.rank,
+ts_headline('english', t.description, websearch_to_tsquery('english', sqlc.arg(query)::text),
+    'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10') AS headline
+FROM (
+    SELECT c.comment_id, c.user_id, u.name AS username, c.post_id, c.parent_comment_id,
+    c.description, c.created_at, c.updated_at, c.edit_count,
+    COUNT(v.vote) FILTER (WHERE v.vote = 1) AS likes,
+    COUNT(v.vote) FILTER (WHERE v.vote = -1) AS dislikes,
+    MAX(uv.vote) AS user_vote,
+    EXISTS (SELECT 1 FROM Bookmarks s WHERE s.comment_id = c.comment_id AND s.user_id = sqlc.arg(user_id)) AS saved,
+    ts_rank(c.search_vector, websearch_to_tsquery('english', sqlc.arg(query)::text)) AS rank
+    FROM Comments c
+    JOIN Posts p ON p.post_id = c.post_id
+    JOIN Users u ON u.user_id = c.user_id
+    LEFT JOIN Comment_Votes v ON c.comment_id = v.comment_id
+    LEFT JOIN Comment_Votes uv ON c.comment_id = uv.comment_id AND uv.user_id = sqlc.arg(user_id)
+    WHERE p.topic_id = sqlc.arg(topic_id) AND c.deleted_at IS NULL AND p.deleted_at IS NULL
+    AND c.search_vector @@ websearch_to_tsquery('english', sqlc.arg(query)::text)
+    GROUP BY c.comment_id, u.name
+) AS t
+WHERE sqlc.narg(cursor_comment_id)::bigint IS NULL
+OR (t.rank, t.comment_id) < (sqlc.narg(cursor_rank)::real, sqlc.narg(cursor_comment_id)::bigint)
+ORDER BY t.rank DESC, t.comment_id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountSearchComment :one
+SELECT COUNT(*) FROM Comments c
+JOIN Posts p ON p.post_id = c.post_id
+WHERE p.topic_id = sqlc.arg(topic_id) AND c.deleted_at IS NULL AND p.deleted_at IS NULL
+AND c.search_vector @@ websearch_to_tsquery('english', sqlc.arg(query)::text);
+
+-- name: FindCommentReplies :many
+WITH RECURSIVE thread AS (
+    SELECT comment_id, 1 AS depth FROM Comments
+    WHERE parent_comment_id = ANY(sqlc.arg(root_ids)::bigint[])
+    UNION ALL
+    SELECT c.comment_id, t.depth + 1 FROM Comments c
+    JOIN thread t ON c.parent_comment_id = t.comment_id
+    WHERE t.depth < sqlc.arg(max_depth)::int
+)
+SELECT a.*, sort_key(sqlc.arg(sort)::text, a.likes, a.dislikes, a.created_at, a.last_activity_at) AS sort_key
+FROM (
+    SELECT c.comment_id, c.user_id, u.name as username, c.post_id, c.parent_comment_id,
+    c.description, c.created_at, c.updated_at, c.edit_count, c.deleted_at,
+    COUNT(v.vote) FILTER (WHERE v.vote = 1) AS likes,
+    COUNT(v.vote) FILTER (WHERE v.vote = -1) AS dislikes,
+    MAX(uv.vote) AS user_vote,
+    EXISTS (SELECT 1 FROM Bookmarks s WHERE s.comment_id = c.comment_id AND s.user_id = sqlc.arg(user_id)) AS saved,
+    (SELECT COALESCE(MAX(r.created_at), c.created_at) FROM Comments r
+    WHERE r.parent_comment_id = c.comment_id AND r.deleted_at IS NULL)::timestamptz AS last_activity_at
+    FROM thread t
+    JOIN Comments c ON c.comment_id = t.comment_id
+    JOIN Users u ON u.user_id = c.user_id
+    LEFT JOIN Comment_Votes v ON c.comment_id = v.comment_id
+    LEFT JOIN Comment_Votes uv ON c.comment_id = uv.comment_id AND uv.user_id = sqlc.arg(user_id)
+    WHERE c.deleted_at IS NULL OR has_live_replies(c.comment_id)
+    GROUP BY c.comment_id, u.name
+) AS a
+ORDER BY sort_key DESC, a.updated_at DESC, a.comment_id DESC;
+
+-- name: FindCommentParent :one
+WITH RECURSIVE ancestors AS (
+    SELECT comment_id, parent_comment_id FROM Comments WHERE comment_id = $1
+    UNION ALL
+    SELECT c.comment_id, c.parent_comment_id FROM Comments c
+    JOIN ancestors a ON c.comment_id = a.parent_comment_id
+)
+SELECT comment_id, post_id, deleted_at, (SELECT COUNT(*) - 1 FROM ancestors)::int AS depth
+FROM Comments WHERE comment_id = $1;
 
 -- name: CreateComment :one
-INSERT INTO Comments (user_id, post_id, description) VALUES ($1, $2, $3) RETURNING *;
+INSERT INTO Comments (user_id, post_id, parent_comment_id, description) VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: FindCommentAuthor :one
+SELECT c.comment_id, c.post_id, c.user_id, p.topic_id
+FROM Comments c
+JOIN Posts p ON p.post_id = c.post_id
+WHERE c.comment_id = $1 AND c.deleted_at IS NULL AND p.deleted_at IS NULL;
 
 -- name: UpdateComment :one
-UPDATE Comments SET description = $4, updated_at = now()
-WHERE comment_id = $1 AND post_id = $2 AND user_id = $3 RETURNING *;
+UPDATE Comments SET description = $3, updated_at = now(), edit_count = edit_count + 1
+WHERE comment_id = $1 AND post_id = $2 AND deleted_at IS NULL RETURNING *;
+
+-- name: CreateCommentRevision :exec
+INSERT INTO Comment_Revisions (comment_id, version, description, replaced_by, created_at)
+SELECT comment_id, edit_count + 1, description, sqlc.arg(replaced_by)::bigint, updated_at
+FROM Comments WHERE comment_id = sqlc.arg(comment_id) AND deleted_at IS NULL
+FOR UPDATE;
+
+-- name: ListCommentRevisions :many
+SELECT r.version, r.description, r.replaced_by, COALESCE(u.name, '') AS replaced_by_name,
+r.created_at, r.replaced_at
+FROM Comment_Revisions r
+LEFT JOIN Users u ON u.user_id = r.replaced_by
+WHERE r.comment_id = $1
+ORDER BY r.version DESC;
+
+-- name: FindCommentRevision :one
+SELECT r.version, r.description, r.replaced_by, COALESCE(u.name, '') AS replaced_by_name,
+r.created_at, r.replaced_at
+FROM Comment_Revisions r
+LEFT JOIN Users u ON u.user_id = r.replaced_by
+WHERE r.comment_id = $1 AND r.version = $2;
 
 -- name: DeleteComment :execrows
-DELETE FROM Comments WHERE comment_id = $1 AND user_id = $2;
+UPDATE Comments SET deleted_at = now(), deleted_by = $2 WHERE comment_id = $1 AND deleted_at IS NULL;
+
+-- name: FindDeletedComment :one
+SELECT c.comment_id, c.post_id, c.user_id, p.topic_id, c.deleted_at, c.deleted_by
+FROM Comments c
+JOIN Posts p ON p.post_id = c.post_id
+WHERE c.comment_id = $1 AND c.deleted_at IS NOT NULL AND p.deleted_at IS NULL;
+
+-- name: RestoreComment :one
+UPDATE Comments SET deleted_at = NULL, deleted_by = NULL WHERE comment_id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
 
 -- Post Votes
 -- name: LikesPost :exec
@@ -124,3 +483,426 @@ ON CONFLICT (comment_id, user_id) DO UPDATE SET vote = -1 WHERE Comment_Votes.vo
 
 -- name: RemoveCommentVote :execrows
 DELETE FROM Comment_Votes WHERE comment_id = $1 AND user_id = $2;
+
+-- Poll Queries
+-- name: CreatePoll :exec
+INSERT INTO Polls (post_id, question, multiple_choice, closes_at) VALUES ($1, $2, $3, $4);
+
+-- name: CreatePollOptions :exec
+INSERT INTO Poll_Options (post_id, position, label)
+SELECT sqlc.arg(post_id), o.position, o.label
+FROM unnest(sqlc.arg(labels)::text[]) WITH ORDINALITY AS o(label, position);
+
+-- name: FindPoll :one
+SELECT * FROM Polls WHERE post_id = $1;
+
+-- name: FindPollOptions :many
+SELECT o.option_id, o.label, COUNT(b.user_id) AS votes,
+COALESCE(bool_or(b.user_id = sqlc.arg(user_id)), false)::boolean AS chosen
+FROM Poll_Options o
+LEFT JOIN Poll_Ballot_Options b ON b.option_id = o.option_id
+WHERE o.post_id = sqlc.arg(post_id)
+GROUP BY o.option_id
+ORDER BY o.position;
+
+-- name: CountPollVoters :one
+SELECT COUNT(*) FROM Poll_Ballots WHERE post_id = $1;
+
+-- name: UpsertPollBallot :exec
+INSERT INTO Poll_Ballots (post_id, user_id) VALUES ($1, $2)
+ON CONFLICT (post_id, user_id) DO UPDATE SET created_at = now();
+
+-- name: DeletePollBallotOptions :exec
+DELETE FROM Poll_Ballot_Options WHERE post_id = $1 AND user_id = $2;
+
+-- name: CreatePollBallotOptions :execrows
+INSERT INTO Poll_Ballot_Options (post_id, user_id, option_id)
+SELECT o.post_id, sqlc.arg(user_id), o.option_id FROM Poll_Options o
+WHERE o.post_id = sqlc.arg(post_id) AND o.option_id = ANY(sqlc.arg(option_ids)::bigint[]);
+
+-- Search Queries
+-- name: Search :many
+SELECT t.result_type, t.result_id, t.topic_id, t.post_id, t.user_id, t.username, t.title,
+t.score, t.rank, t.created_at,
+ts_headline('english', t.content, websearch_to_tsquery('english', sqlc.arg(query)::text),
+    'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10') AS headline
+FROM search_results(
+    sqlc.arg(query)::text,
+    sqlc.narg(result_type)::text,
+    sqlc.narg(author)::text,
+    sqlc.narg(topic_id)::bigint,
+    sqlc.narg(created_after)::timestamptz,
+    sqlc.narg(created_before)::timestamptz,
+    sqlc.narg(min_score)::bigint
+) AS t
+WHERE sqlc.narg(cursor_id)::bigint IS NULL OR (t.rank, t.result_type, t.result_id) < (
+    sqlc.narg(cursor_rank)::real,
+    sqlc.narg(cursor_type)::text,
+    sqlc.narg(cursor_id)::bigint
+)
+ORDER BY t.rank DESC, t.result_type DESC, t.result_id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountSearch :one
+SELECT COUNT(*) FROM search_results(
+    sqlc.arg(query)::text,
+    sqlc.narg(result_type)::text,
+    sqlc.narg(author)::text,
+    sqlc.narg(topic_id)::bigint,
+    sqlc.narg(created_after)::timestamptz,
+    sqlc.narg(created_before)::timestamptz,
+    sqlc.narg(min_score)::bigint
+) AS t;
+
+-- Notifications Queries
+-- name: CreateNotification :exec
+INSERT INTO Notifications (user_id, actor_id, type, topic_id, post_id, comment_id, message)
+SELECT sqlc.arg(user_id)::bigint, sqlc.arg(actor_id)::bigint, sqlc.arg(type)::text,
+sqlc.narg(topic_id)::bigint, sqlc.narg(post_id)::bigint, sqlc.narg(comment_id)::bigint,
+sqlc.arg(message)::text
+WHERE sqlc.arg(user_id)::bigint <> sqlc.arg(actor_id)::bigint
+AND NOT EXISTS (
+    SELECT 1 FROM Notification_Preferences np
+    WHERE np.user_id = sqlc.arg(user_id)::bigint AND np.type = sqlc.arg(type)::text AND NOT np.enabled
+)
+AND NOT EXISTS (
+    SELECT 1 FROM Notifications n
+    WHERE n.user_id = sqlc.arg(user_id)::bigint AND n.actor_id = sqlc.arg(actor_id)::bigint
+    AND n.type = sqlc.arg(type)::text AND n.read_at IS NULL
+    AND n.post_id IS NOT DISTINCT FROM sqlc.narg(post_id)::bigint
+    AND n.comment_id IS NOT DISTINCT FROM sqlc.narg(comment_id)::bigint
+);
+
+-- name: ListNotifications :many
+SELECT n.notification_id, n.type, n.actor_id, u.name AS actor_name, n.topic_id, n.post_id,
+n.comment_id, n.message, n.read_at, n.created_at
+FROM Notifications n
+JOIN Users u ON u.user_id = n.actor_id
+WHERE n.user_id = sqlc.arg(user_id)
+AND (NOT sqlc.arg(unread_only)::boolean OR n.read_at IS NULL)
+AND (sqlc.narg(cursor_notification_id)::bigint IS NULL
+OR n.notification_id < sqlc.narg(cursor_notification_id)::bigint)
+ORDER BY n.notification_id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountNotifications :one
+SELECT COUNT(*) FROM Notifications
+WHERE user_id = sqlc.arg(user_id) AND (NOT sqlc.arg(unread_only)::boolean OR read_at IS NULL);
+
+-- name: MarkNotificationRead :execrows
+UPDATE Notifications SET read_at = COALESCE(read_at, now())
+WHERE notification_id = $1 AND user_id = $2;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE Notifications SET read_at = now() WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: ListNotificationPreferences :many
+SELECT * FROM Notification_Preferences WHERE user_id = $1;
+
+-- name: UpsertNotificationPreference :exec
+INSERT INTO Notification_Preferences (user_id, type, enabled) VALUES ($1, $2, $3)
+ON CONFLICT (user_id, type) DO UPDATE SET enabled = EXCLUDED.enabled;
+
+-- Stream Queries
+-- name: PublishEvent :exec
+SELECT pg_notify('forum_events', sqlc.arg(payload)::text);
+
+-- name: FindPostVoteCounts :one
+SELECT p.post_id, p.topic_id,
+COUNT(v.vote) FILTER (WHERE v.vote = 1) AS likes,
+COUNT(v.vote) FILTER (WHERE v.vote = -1) AS dislikes
+FROM Posts p
+LEFT JOIN Post_Votes v ON v.post_id = p.post_id
+WHERE p.post_id = $1
+GROUP BY p.post_id;
+
+-- name: FindCommentVoteCounts :one
+SELECT c.comment_id, c.post_id, p.topic_id,
+COUNT(v.vote) FILTER (WHERE v.vote = 1) AS likes,
+COUNT(v.vote) FILTER (WHERE v.vote = -1) AS dislikes
+FROM Comments c
+JOIN Posts p ON p.post_id = c.post_id
+LEFT JOIN Comment_Votes v ON v.comment_id = c.comment_id
+WHERE c.comment_id = $1
+GROUP BY c.comment_id, p.topic_id;
+
+-- Reports Queries
+-- name: CreateReport :one
+INSERT INTO Reports (reporter_id, target_type, target_id, topic_id, reason, details)
+VALUES ($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- name: LockReportByID :one
+SELECT * FROM Reports WHERE report_id = $1 FOR UPDATE;
+
+-- name: ListReportQueue :many
+SELECT r.target_type, r.target_id, r.topic_id, COUNT(*) AS report_count,
+MIN(r.created_at)::timestamptz AS first_reported_at, MAX(r.created_at)::timestamptz AS last_reported_at
+FROM Reports r
+WHERE r.status = 'open'
+AND (sqlc.arg(all_topics)::boolean
+OR r.topic_id IN (SELECT m.topic_id FROM Topic_Moderators m WHERE m.user_id = sqlc.arg(moderator_id)))
+GROUP BY r.target_type, r.target_id, r.topic_id
+HAVING sqlc.narg(cursor_reported_at)::timestamptz IS NULL
+OR (MIN(r.created_at), r.target_type, r.target_id) > (
+    sqlc.narg(cursor_reported_at)::timestamptz, sqlc.narg(cursor_target_type)::text,
+    sqlc.narg(cursor_target_id)::bigint
+)
+ORDER BY first_reported_at, r.target_type, r.target_id
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountReportQueue :one
+SELECT COUNT(DISTINCT (r.target_type, r.target_id)) FROM Reports r
+WHERE r.status = 'open'
+AND (sqlc.arg(all_topics)::boolean
+OR r.topic_id IN (SELECT m.topic_id FROM Topic_Moderators m WHERE m.user_id = sqlc.arg(moderator_id)));
+
+-- name: ListOpenReportsByTargets :many
+SELECT r.report_id, r.reporter_id, u.name AS reporter_name, r.target_type, r.target_id, r.reason,
+r.details, r.created_at
+FROM Reports r
+JOIN Users u ON u.user_id = r.reporter_id
+JOIN unnest(sqlc.arg(target_types)::text[], sqlc.arg(target_ids)::bigint[]) AS t(target_type, target_id)
+ON t.target_type = r.target_type AND t.target_id = r.target_id
+WHERE r.status = 'open'
+ORDER BY r.created_at, r.report_id;
+
+-- name: ResolveReports :execrows
+UPDATE Reports SET status = sqlc.arg(status), action = sqlc.arg(action), resolved_by = sqlc.arg(resolved_by),
+resolution_note = sqlc.arg(resolution_note), resolved_at = now()
+WHERE target_type = sqlc.arg(target_type) AND target_id = sqlc.arg(target_id) AND status = 'open';
+
+-- name: CreateSuspension :one
+INSERT INTO Suspensions (user_id, topic_id, reason, expires_at, created_by, report_id)
+VALUES ($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- name: FindSuspensionByID :one
+SELECT * FROM Suspensions WHERE suspension_id = $1;
+
+-- name: FindActiveSuspension :one
+SELECT * FROM Suspensions
+WHERE user_id = sqlc.arg(user_id) AND lifted_at IS NULL AND (expires_at IS NULL OR expires_at > now())
+AND (topic_id IS NULL OR topic_id = sqlc.narg(topic_id))
+ORDER BY topic_id NULLS FIRST, expires_at DESC NULLS FIRST
+LIMIT 1;
+
+-- name: ListActiveSuspensions :many
+SELECT s.suspension_id, s.user_id, u.name AS username, s.topic_id, s.reason, s.expires_at,
+s.created_by, s.created_at
+FROM Suspensions s
+JOIN Users u ON u.user_id = s.user_id
+WHERE s.lifted_at IS NULL AND (s.expires_at IS NULL OR s.expires_at > now())
+AND (sqlc.arg(all_topics)::boolean
+OR s.topic_id IN (SELECT m.topic_id FROM Topic_Moderators m WHERE m.user_id = sqlc.arg(moderator_id)))
+AND (sqlc.narg(cursor_suspension_id)::bigint IS NULL
+OR s.suspension_id < sqlc.narg(cursor_suspension_id)::bigint)
+ORDER BY s.suspension_id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountActiveSuspensions :one
+SELECT COUNT(*) FROM Suspensions s
+WHERE s.lifted_at IS NULL AND (s.expires_at IS NULL OR s.expires_at > now())
+AND (sqlc.arg(all_topics)::boolean
+OR s.topic_id IN (SELECT m.topic_id FROM Topic_Moderators m WHERE m.user_id = sqlc.arg(moderator_id)));
+
+-- name: LiftSuspension :execrows
+UPDATE Suspensions SET lifted_at = now(), lifted_by = $2 WHERE suspension_id = $1 AND lifted_at IS NULL;
+
+-- name: FindPostSnapshot :one
+SELECT * FROM Posts WHERE post_id = $1;
+
+-- name: FindCommentSnapshot :one
+SELECT * FROM Comments WHERE comment_id = $1;
+
+-- name: CreateAuditLog :exec
+INSERT INTO Audit_Log (actor_id, action, target_type, target_id, topic_id, before, after)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: ListAuditLog :many
+SELECT a.audit_id, a.actor_id, COALESCE(u.name, '') AS actor_name, a.action, a.target_type,
+a.target_id, a.topic_id, a.before, a.after, a.created_at
+FROM Audit_Log a
+LEFT JOIN Users u ON u.user_id = a.actor_id
+WHERE (sqlc.narg(actor_id)::bigint IS NULL OR a.actor_id = sqlc.narg(actor_id)::bigint)
+AND (sqlc.narg(action)::text IS NULL OR a.action = sqlc.narg(action)::text)
+AND (sqlc.narg(target_type)::text IS NULL OR a.target_type = sqlc.narg(target_type)::text)
+AND (sqlc.narg(target_id)::bigint IS NULL OR a.target_id = sqlc.narg(target_id)::bigint)
+AND (sqlc.narg(topic_id)::bigint IS NULL OR a.topic_id = sqlc.narg(topic_id)::bigint)
+AND (sqlc.narg(since)::timestamptz IS NULL OR a.created_at >= sqlc.narg(since)::timestamptz)
+AND (sqlc.narg(until)::timestamptz IS NULL OR a.created_at < sqlc.narg(until)::timestamptz)
+AND (sqlc.narg(cursor_audit_id)::bigint IS NULL OR a.audit_id < sqlc.narg(cursor_audit_id)::bigint)
+ORDER BY a.audit_id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountAuditLog :one
+SELECT COUNT(*) FROM Audit_Log a
+WHERE (sqlc.narg(actor_id)::bigint IS NULL OR a.actor_id = sqlc.narg(actor_id)::bigint)
+AND (sqlc.narg(action)::text IS NULL OR a.action = sqlc.narg(action)::text)
+AND (sqlc.narg(target_type)::text IS NULL OR a.target_type = sqlc.narg(target_type)::text)
+AND (sqlc.narg(target_id)::bigint IS NULL OR a.target_id = sqlc.narg(target_id)::bigint)
+AND (sqlc.narg(topic_id)::bigint IS NULL OR a.topic_id = sqlc.narg(topic_id)::bigint)
+AND (sqlc.narg(since)::timestamptz IS NULL OR a.created_at >= sqlc.narg(since)::timestamptz)
+AND (sqlc.narg(until)::timestamptz IS NULL OR a.created_at < sqlc.narg(until)::timestamptz);
+
+-- name: PurgeDeletedPosts :execrows
+DELETE FROM Posts WHERE deleted_at < sqlc.arg(deleted_before);
+
+-- name: PurgeDeletedComments :execrows
+DELETE FROM Comments c
+WHERE c.deleted_at < sqlc.arg(deleted_before)
+AND NOT EXISTS (SELECT 1 FROM Comments r WHERE r.parent_comment_id = c.comment_id);
+
+-- name: PurgeCommentTombstones :execrows
+UPDATE Comments SET description = ''
+WHERE deleted_at < sqlc.arg(deleted_before) AND description <> '';
+
+-- name: PurgeTombstoneRevisions :exec
+DELETE FROM Comment_Revisions r USING Comments c
+WHERE r.comment_id = c.comment_id AND c.deleted_at < sqlc.arg(deleted_before);
+
+-- name: CreateAttachment :one
+INSERT INTO Attachments (user_id, post_id, comment_id, storage_key, thumbnail_key, filename, content_type,
+size_bytes, width, height)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: FindAttachment :one
+SELECT a.* FROM Attachments a
+LEFT JOIN Comments c ON c.comment_id = a.comment_id
+JOIN Posts p ON p.post_id = COALESCE(a.post_id, c.post_id)
+WHERE a.attachment_id = $1 AND c.deleted_at IS NULL AND p.deleted_at IS NULL;
+
+-- name: ListPostAttachments :many
+SELECT * FROM Attachments WHERE post_id = $1 ORDER BY attachment_id;
+
+-- name: ListCommentAttachments :many
+SELECT * FROM Attachments WHERE comment_id = $1 ORDER BY attachment_id;
+
+-- name: DeleteAttachment :one
+DELETE FROM Attachments WHERE attachment_id = $1 RETURNING storage_key, thumbnail_key;
+
+-- name: PurgeDeletedAttachments :many
+DELETE FROM Attachments a
+WHERE a.post_id IN (SELECT post_id FROM Posts WHERE deleted_at < sqlc.arg(deleted_before))
+OR a.comment_id IN (
+    SELECT c.comment_id FROM Comments c
+    JOIN Posts p ON p.post_id = c.post_id
+    WHERE c.deleted_at < sqlc.arg(deleted_before) OR p.deleted_at < sqlc.arg(deleted_before)
+)
+RETURNING a.storage_key, a.thumbnail_key;
+
+-- name: SavePost :exec
+INSERT INTO Bookmarks (user_id, post_id) VALUES ($1, $2) ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: UnsavePost :execrows
+DELETE FROM Bookmarks WHERE user_id = $1 AND post_id = $2;
+
+-- name: SaveComment :exec
+INSERT INTO Bookmarks (user_id, comment_id) VALUES ($1, $2) ON CONFLICT (user_id, comment_id) DO NOTHING;
+
+-- name: UnsaveComment :execrows
+DELETE FROM Bookmarks WHERE user_id = $1 AND comment_id = $2;
+
+-- name: ListBookmarks :many
+SELECT b.bookmark_id, p.topic_id, p.post_id, b.comment_id, p.title,
+COALESCE(c.description, p.description)::text AS description,
+COALESCE(c.user_id, p.user_id)::bigint AS author_id, u.name AS author_name,
+COALESCE(c.created_at, p.created_at)::timestamptz AS created_at, b.created_at AS saved_at
+FROM Bookmarks b
+LEFT JOIN Comments c ON c.comment_id = b.comment_id
+JOIN Posts p ON p.post_id = COALESCE(b.post_id, c.post_id)
+JOIN Users u ON u.user_id = COALESCE(c.user_id, p.user_id)
+WHERE b.user_id = sqlc.arg(user_id) AND c.deleted_at IS NULL AND p.deleted_at IS NULL
+AND (sqlc.narg(topic_id)::bigint IS NULL OR p.topic_id = sqlc.narg(topic_id)::bigint)
+AND (sqlc.narg(cursor_bookmark_id)::bigint IS NULL OR b.bookmark_id < sqlc.narg(cursor_bookmark_id)::bigint)
+ORDER BY b.bookmark_id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountBookmarks :one
+SELECT COUNT(*) FROM Bookmarks b
+LEFT JOIN Comments c ON c.comment_id = b.comment_id
+JOIN Posts p ON p.post_id = COALESCE(b.post_id, c.post_id)
+WHERE b.user_id = sqlc.arg(user_id) AND c.deleted_at IS NULL AND p.deleted_at IS NULL
+AND (sqlc.narg(topic_id)::bigint IS NULL OR p.topic_id = sqlc.narg(topic_id)::bigint);
+
+-- name: SyncPostMentions :many
+WITH mentioned AS (
+    SELECT user_id FROM Users WHERE name = ANY(sqlc.arg(names)::text[])
+), stale AS (
+    DELETE FROM Mentions m
+    WHERE m.post_id = sqlc.arg(post_id) AND m.user_id NOT IN (SELECT user_id FROM mentioned)
+)
+INSERT INTO Mentions (user_id, post_id)
+SELECT user_id, sqlc.arg(post_id) FROM mentioned
+ON CONFLICT (post_id, user_id) DO NOTHING
+RETURNING user_id;
+
+-- name: SyncCommentMentions :many
+WITH mentioned AS (
+    SELECT user_id FROM Users WHERE name = ANY(sqlc.arg(names)::text[])
+), stale AS (
+    DELETE FROM Mentions m
+    WHERE m.comment_id = sqlc.arg(comment_id) AND m.user_id NOT IN (SELECT user_id FROM mentioned)
+)
+INSERT INTO Mentions (user_id, comment_id)
+SELECT user_id, sqlc.arg(comment_id) FROM mentioned
+ON CONFLICT (comment_id, user_id) DO NOTHING
+RETURNING user_id;
+
+-- name: ListPostMentions :many
+SELECT m.post_id, m.user_id, u.name FROM Mentions m
+JOIN Users u ON u.user_id = m.user_id
+WHERE m.post_id = ANY(sqlc.arg(post_ids)::bigint[])
+ORDER BY m.mention_id;
+
+-- name: ListCommentMentions :many
+SELECT m.comment_id, m.user_id, u.name FROM Mentions m
+JOIN Users u ON u.user_id = m.user_id
+WHERE m.comment_id = ANY(sqlc.arg(comment_ids)::bigint[])
+ORDER BY m.mention_id;
+
+-- name: FindUsersByNames :many
+SELECT user_id, name FROM Users WHERE name = ANY(sqlc.arg(names)::text[]);
+
+-- name: ListUserMentions :many
+SELECT m.mention_id, p.topic_id, p.post_id, m.comment_id, p.title,
+COALESCE(c.description, p.description)::text AS description,
+COALESCE(c.user_id, p.user_id)::bigint AS author_id, u.name AS author_name,
+COALESCE(c.created_at, p.created_at)::timestamptz AS created_at
+FROM Mentions m
+LEFT JOIN Comments c ON c.comment_id = m.comment_id
+JOIN Posts p ON p.post_id = COALESCE(m.post_id, c.post_id)
+JOIN Users u ON u.user_id = COALESCE(c.user_id, p.user_id)
+WHERE m.user_id = sqlc.arg(user_id) AND c.deleted_at IS NULL AND p.deleted_at IS NULL
+AND (sqlc.narg(cursor_mention_id)::bigint IS NULL OR m.mention_id < sqlc.narg(cursor_mention_id)::bigint)
+ORDER BY m.mention_id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: CountUserMentions :one
+SELECT COUNT(*) FROM Mentions m
+LEFT JOIN Comments c ON c.comment_id = m.comment_id
+JOIN Posts p ON p.post_id = COALESCE(m.post_id, c.post_id)
+WHERE m.user_id = $1 AND c.deleted_at IS NULL AND p.deleted_at IS NULL;
+
+-- name: AutocompleteUsers :many
+SELECT user_id, name FROM Users
+WHERE starts_with(lower(name), lower(sqlc.arg(prefix)::text))
+ORDER BY length(name), name
+LIMIT sqlc.arg(page_limit);
+
+-- name: TakeRateLimitToken :one
+INSERT INTO Rate_Limits AS rl (bucket_key, tokens, updated_at)
+VALUES (sqlc.arg(bucket_key), sqlc.arg(burst)::float8 - 1, now())
+ON CONFLICT (bucket_key) DO UPDATE
+SET tokens = LEAST(sqlc.arg(burst)::float8, rl.tokens + EXTRACT(EPOCH FROM now() - rl.updated_at)::float8 / sqlc.arg(interval_seconds)::float8) - 1,
+    updated_at = now()
+WHERE LEAST(sqlc.arg(burst)::float8, rl.tokens + EXTRACT(EPOCH FROM now() - rl.updated_at)::float8 / sqlc.arg(interval_seconds)::float8) >= 1
+RETURNING tokens;
+
+-- name: FindRateLimitTokens :one
+SELECT LEAST(sqlc.arg(burst)::float8, tokens + EXTRACT(EPOCH FROM now() - updated_at)::float8 / sqlc.arg(interval_seconds)::float8)::float8 AS tokens
+FROM Rate_Limits
+WHERE bucket_key = sqlc.arg(bucket_key);
+
+-- name: PurgeRateLimits :execrows
+DELETE FROM Rate_Limits
+WHERE updated_at < $1;
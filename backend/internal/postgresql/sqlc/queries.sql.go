@@ -11,18 +11,475 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const addTopicModerator = `-- name: AddTopicModerator :exec
+INSERT INTO Topic_Moderators (topic_id, user_id) VALUES ($1, $2) ON CONFLICT (topic_id, user_id) DO NOTHING
+`
+
+type AddTopicModeratorParams struct {
+	TopicID int64 `json:"topic_id"`
+	UserID  int64 `json:"user_id"`
+}
+
+func (q *Queries) AddTopicModerator(ctx context.Context, arg AddTopicModeratorParams) error {
+	_, err := q.db.Exec(ctx, addTopicModerator, arg.TopicID, arg.UserID)
+	return err
+}
+
+const autocompleteUsers = `-- name: AutocompleteUsers :many
+SELECT user_id, name FROM Users
+WHERE starts_with(lower(name), lower($1::text))
+ORDER BY length(name), name
+LIMIT $2
+`
+
+type AutocompleteUsersParams struct {
+	Prefix    string `json:"prefix"`
+	PageLimit int32  `json:"page_limit"`
+}
+
+type AutocompleteUsersRow struct {
+	UserID int64  `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+func (q *Queries) AutocompleteUsers(ctx context.Context, arg AutocompleteUsersParams) ([]AutocompleteUsersRow, error) {
+	rows, err := q.db.Query(ctx, autocompleteUsers, arg.Prefix, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AutocompleteUsersRow
+	for rows.Next() {
+		var i AutocompleteUsersRow
+		if err := rows.Scan(&i.UserID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const canModerateTopic = `-- name: CanModerateTopic :one
+SELECT (
+    EXISTS (SELECT 1 FROM Users WHERE user_id = $1 AND role IN ('admin', 'moderator'))
+    OR EXISTS (SELECT 1 FROM Topic_Moderators WHERE user_id = $1 AND topic_id = $2)
+)::boolean AS can_moderate
+`
+
+type CanModerateTopicParams struct {
+	UserID  int64 `json:"user_id"`
+	TopicID int64 `json:"topic_id"`
+}
+
+func (q *Queries) CanModerateTopic(ctx context.Context, arg CanModerateTopicParams) (bool, error) {
+	row := q.db.QueryRow(ctx, canModerateTopic, arg.UserID, arg.TopicID)
+	var can_moderate bool
+	err := row.Scan(&can_moderate)
+	return can_moderate, err
+}
+
+const countActiveSuspensions = `-- name: CountActiveSuspensions :one
+SELECT COUNT(*) FROM Suspensions s
+WHERE s.lifted_at IS NULL AND (s.expires_at IS NULL OR s.expires_at > now())
+AND ($1::boolean
+OR s.topic_id IN (SELECT m.topic_id FROM Topic_Moderators m WHERE m.user_id = $2))
+`
+
+type CountActiveSuspensionsParams struct {
+	AllTopics   bool  `json:"all_topics"`
+	ModeratorID int64 `json:"moderator_id"`
+}
+
+func (q *Queries) CountActiveSuspensions(ctx context.Context, arg CountActiveSuspensionsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countActiveSuspensions, arg.AllTopics, arg.ModeratorID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countAuditLog = `-- name: CountAuditLog :one
+SELECT COUNT(*) FROM Audit_Log a
+WHERE ($1::bigint IS NULL OR a.actor_id = $1::bigint)
+AND ($2::text IS NULL OR a.action = $2::text)
+AND ($3::text IS NULL OR a.target_type = $3::text)
+AND ($4::bigint IS NULL OR a.target_id = $4::bigint)
+AND ($5::bigint IS NULL OR a.topic_id = $5::bigint)
+AND ($6::timestamptz IS NULL OR a.created_at >= $6::timestamptz)
+AND ($7::timestamptz IS NULL OR a.created_at < $7::timestamptz)
+`
+
+type CountAuditLogParams struct {
+	ActorID    pgtype.Int8        `json:"actor_id"`
+	Action     pgtype.Text        `json:"action"`
+	TargetType pgtype.Text        `json:"target_type"`
+	TargetID   pgtype.Int8        `json:"target_id"`
+	TopicID    pgtype.Int8        `json:"topic_id"`
+	Since      pgtype.Timestamptz `json:"since"`
+	Until      pgtype.Timestamptz `json:"until"`
+}
+
+func (q *Queries) CountAuditLog(ctx context.Context, arg CountAuditLogParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countAuditLog,
+		arg.ActorID,
+		arg.Action,
+		arg.TargetType,
+		arg.TargetID,
+		arg.TopicID,
+		arg.Since,
+		arg.Until,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countBookmarks = `-- name: CountBookmarks :one
+SELECT COUNT(*) FROM Bookmarks b
+LEFT JOIN Comments c ON c.comment_id = b.comment_id
+JOIN Posts p ON p.post_id = COALESCE(b.post_id, c.post_id)
+WHERE b.user_id = $1 AND c.deleted_at IS NULL AND p.deleted_at IS NULL
+AND ($2::bigint IS NULL OR p.topic_id = $2::bigint)
+`
+
+type CountBookmarksParams struct {
+	UserID  int64       `json:"user_id"`
+	TopicID pgtype.Int8 `json:"topic_id"`
+}
+
+func (q *Queries) CountBookmarks(ctx context.Context, arg CountBookmarksParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countBookmarks, arg.UserID, arg.TopicID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countCommentsByPost = `-- name: CountCommentsByPost :one
+SELECT COUNT(*) FROM Comments c
+WHERE post_id = $1 AND parent_comment_id IS NULL
+AND (deleted_at IS NULL OR has_live_replies(c.comment_id))
+AND ($2::timestamptz IS NULL OR created_at >= $2::timestamptz)
+`
+
+type CountCommentsByPostParams struct {
+	PostID int64              `json:"post_id"`
+	Since  pgtype.Timestamptz `json:"since"`
+}
+
+func (q *Queries) CountCommentsByPost(ctx context.Context, arg CountCommentsByPostParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countCommentsByPost, arg.PostID, arg.Since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countFeed = `-- name: CountFeed :one
+SELECT COUNT(*) FROM Posts p
+WHERE p.deleted_at IS NULL
+AND (p.topic_id IN (SELECT ts.topic_id FROM Topic_Subscriptions ts WHERE ts.user_id = $1)
+OR NOT EXISTS (SELECT 1 FROM Topic_Subscriptions ts WHERE ts.user_id = $1))
+AND ($2::timestamptz IS NULL OR p.created_at >= $2::timestamptz)
+`
+
+type CountFeedParams struct {
+	UserID int64              `json:"user_id"`
+	Since  pgtype.Timestamptz `json:"since"`
+}
+
+func (q *Queries) CountFeed(ctx context.Context, arg CountFeedParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countFeed, arg.UserID, arg.Since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countNotifications = `-- name: CountNotifications :one
+SELECT COUNT(*) FROM Notifications
+WHERE user_id = $1 AND (NOT $2::boolean OR read_at IS NULL)
+`
+
+type CountNotificationsParams struct {
+	UserID     int64 `json:"user_id"`
+	UnreadOnly bool  `json:"unread_only"`
+}
+
+func (q *Queries) CountNotifications(ctx context.Context, arg CountNotificationsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countNotifications, arg.UserID, arg.UnreadOnly)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPollVoters = `-- name: CountPollVoters :one
+SELECT COUNT(*) FROM Poll_Ballots WHERE post_id = $1
+`
+
+func (q *Queries) CountPollVoters(ctx context.Context, postID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, countPollVoters, postID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPostsByTopic = `-- name: CountPostsByTopic :one
+SELECT COUNT(*) FROM Posts
+WHERE deleted_at IS NULL AND topic_id = $1
+AND ($2::timestamptz IS NULL OR created_at >= $2::timestamptz)
+`
+
+type CountPostsByTopicParams struct {
+	TopicID int64              `json:"topic_id"`
+	Since   pgtype.Timestamptz `json:"since"`
+}
+
+func (q *Queries) CountPostsByTopic(ctx context.Context, arg CountPostsByTopicParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countPostsByTopic, arg.TopicID, arg.Since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countReportQueue = `-- name: CountReportQueue :one
+SELECT COUNT(DISTINCT (r.target_type, r.target_id)) FROM Reports r
+WHERE r.status = 'open'
+AND ($1::boolean
+OR r.topic_id IN (SELECT m.topic_id FROM Topic_Moderators m WHERE m.user_id = $2))
+`
+
+type CountReportQueueParams struct {
+	AllTopics   bool  `json:"all_topics"`
+	ModeratorID int64 `json:"moderator_id"`
+}
+
+func (q *Queries) CountReportQueue(ctx context.Context, arg CountReportQueueParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countReportQueue, arg.AllTopics, arg.ModeratorID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearch = `-- name: CountSearch :one
+SELECT COUNT(*) FROM search_results(
+    $1::text,
+    $2::text,
+    $3::text,
+    $4::bigint,
+    $5::timestamptz,
+    $6::timestamptz,
+    $7::bigint
+) AS t
+`
+
+type CountSearchParams struct {
+	Query         string             `json:"query"`
+	ResultType    pgtype.Text        `json:"result_type"`
+	Author        pgtype.Text        `json:"author"`
+	TopicID       pgtype.Int8        `json:"topic_id"`
+	CreatedAfter  pgtype.Timestamptz `json:"created_after"`
+	CreatedBefore pgtype.Timestamptz `json:"created_before"`
+	MinScore      pgtype.Int8        `json:"min_score"`
+}
+
+func (q *Queries) CountSearch(ctx context.Context, arg CountSearchParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countSearch,
+		arg.Query,
+		arg.ResultType,
+		arg.Author,
+		arg.TopicID,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.MinScore,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchComment = `-- name: CountSearchComment :one
+SELECT COUNT(*) FROM Comments c
+JOIN Posts p ON p.post_id = c.post_id
+WHERE p.topic_id = $1 AND c.deleted_at IS NULL AND p.deleted_at IS NULL
+AND c.search_vector @@ websearch_to_tsquery('english', $2::text)
+`
+
+type CountSearchCommentParams struct {
+	TopicID int64  `json:"topic_id"`
+	Query   string `json:"query"`
+}
+
+func (q *Queries) CountSearchComment(ctx context.Context, arg CountSearchCommentParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countSearchComment, arg.TopicID, arg.Query)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchPost = `-- name: CountSearchPost :one
+SELECT COUNT(*) FROM Posts
+WHERE deleted_at IS NULL AND topic_id = $1
+AND search_vector @@ websearch_to_tsquery('english', $2::text)
+`
+
+type CountSearchPostParams struct {
+	TopicID int64  `json:"topic_id"`
+	Query   string `json:"query"`
+}
+
+func (q *Queries) CountSearchPost(ctx context.Context, arg CountSearchPostParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countSearchPost, arg.TopicID, arg.Query)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchTopic = `-- name: CountSearchTopic :one
+SELECT COUNT(*) FROM Topics
+WHERE search_vector @@ websearch_to_tsquery('english', $1::text)
+`
+
+func (q *Queries) CountSearchTopic(ctx context.Context, query string) (int64, error) {
+	row := q.db.QueryRow(ctx, countSearchTopic, query)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSubscribedTopics = `-- name: CountSubscribedTopics :one
+SELECT COUNT(*) FROM Topic_Subscriptions WHERE user_id = $1
+`
+
+func (q *Queries) CountSubscribedTopics(ctx context.Context, userID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, countSubscribedTopics, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countTopics = `-- name: CountTopics :one
+SELECT COUNT(*) FROM Topics
+`
+
+func (q *Queries) CountTopics(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countTopics)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUserMentions = `-- name: CountUserMentions :one
+SELECT COUNT(*) FROM Mentions m
+LEFT JOIN Comments c ON c.comment_id = m.comment_id
+JOIN Posts p ON p.post_id = COALESCE(m.post_id, c.post_id)
+WHERE m.user_id = $1 AND c.deleted_at IS NULL AND p.deleted_at IS NULL
+`
+
+func (q *Queries) CountUserMentions(ctx context.Context, userID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, countUserMentions, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createAttachment = `-- name: CreateAttachment :one
+INSERT INTO Attachments (user_id, post_id, comment_id, storage_key, thumbnail_key, filename, content_type,
+size_bytes, width, height)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING attachment_id, user_id, post_id, comment_id, storage_key, thumbnail_key, filename, content_type, size_bytes, width, height, created_at
+`
+
+type CreateAttachmentParams struct {
+	UserID       int64       `json:"user_id"`
+	PostID       pgtype.Int8 `json:"post_id"`
+	CommentID    pgtype.Int8 `json:"comment_id"`
+	StorageKey   string      `json:"storage_key"`
+	ThumbnailKey pgtype.Text `json:"thumbnail_key"`
+	Filename     string      `json:"filename"`
+	ContentType  string      `json:"content_type"`
+	SizeBytes    int64       `json:"size_bytes"`
+	Width        pgtype.Int4 `json:"width"`
+	Height       pgtype.Int4 `json:"height"`
+}
+
+func (q *Queries) CreateAttachment(ctx context.Context, arg CreateAttachmentParams) (Attachment, error) {
+	row := q.db.QueryRow(ctx, createAttachment,
+		arg.UserID,
+		arg.PostID,
+		arg.CommentID,
+		arg.StorageKey,
+		arg.ThumbnailKey,
+		arg.Filename,
+		arg.ContentType,
+		arg.SizeBytes,
+		arg.Width,
+		arg.Height,
+	)
+	var i Attachment
+	err := row.Scan(
+		&i.AttachmentID,
+		&i.UserID,
+		&i.PostID,
+		&i.CommentID,
+		&i.StorageKey,
+		&i.ThumbnailKey,
+		&i.Filename,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.Width,
+		&i.Height,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createAuditLog = `-- name: CreateAuditLog :exec
+INSERT INTO Audit_Log (actor_id, action, target_type, target_id, topic_id, before, after)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+`
+
+type CreateAuditLogParams struct {
+	ActorID    int64       `json:"actor_id"`
+	Action     string      `json:"action"`
+	TargetType string      `json:"target_type"`
+	TargetID   int64       `json:"target_id"`
+	TopicID    pgtype.Int8 `json:"topic_id"`
+	Before     []byte      `json:"before"`
+	After      []byte      `json:"after"`
+}
+
+func (q *Queries) CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) error {
+	_, err := q.db.Exec(ctx, createAuditLog,
+		arg.ActorID,
+		arg.Action,
+		arg.TargetType,
+		arg.TargetID,
+		arg.TopicID,
+		arg.Before,
+		arg.After,
+	)
+	return err
+}
+
 const createComment = `-- name: CreateComment :one
-INSERT INTO Comments (user_id, post_id, description) VALUES ($1, $2, $3) RETURNING comment_id, post_id, user_id, description, created_at, updated_at
+INSERT INTO Comments (user_id, post_id, parent_comment_id, description) VALUES ($1, $2, $3, $4)
+RETURNING comment_id, post_id, user_id, description, created_at, updated_at, parent_comment_id, deleted_at, search_vector, deleted_by, edit_count
 `
 
 type CreateCommentParams struct {
-	UserID      int64  `json:"user_id"`
-	PostID      int64  `json:"post_id"`
-	Description string `json:"description"`
+	UserID          int64       `json:"user_id"`
+	PostID          int64       `json:"post_id"`
+	ParentCommentID pgtype.Int8 `json:"parent_comment_id"`
+	Description     string      `json:"description"`
 }
 
 func (q *Queries) CreateComment(ctx context.Context, arg CreateCommentParams) (Comment, error) {
-	row := q.db.QueryRow(ctx, createComment, arg.UserID, arg.PostID, arg.Description)
+	row := q.db.QueryRow(ctx, createComment,
+		arg.UserID,
+		arg.PostID,
+		arg.ParentCommentID,
+		arg.Description,
+	)
 	var i Comment
 	err := row.Scan(
 		&i.CommentID,
@@ -31,12 +488,134 @@ func (q *Queries) CreateComment(ctx context.Context, arg CreateCommentParams) (C
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ParentCommentID,
+		&i.DeletedAt,
+		&i.SearchVector,
+		&i.DeletedBy,
+		&i.EditCount,
 	)
 	return i, err
 }
 
+const createCommentRevision = `-- name: CreateCommentRevision :exec
+INSERT INTO Comment_Revisions (comment_id, version, description, replaced_by, created_at)
+SELECT comment_id, edit_count + 1, description, $1::bigint, updated_at
+FROM Comments WHERE comment_id = $2 AND deleted_at IS NULL
+FOR UPDATE
+`
+
+type CreateCommentRevisionParams struct {
+	ReplacedBy int64 `json:"replaced_by"`
+	CommentID  int64 `json:"comment_id"`
+}
+
+func (q *Queries) CreateCommentRevision(ctx context.Context, arg CreateCommentRevisionParams) error {
+	_, err := q.db.Exec(ctx, createCommentRevision, arg.ReplacedBy, arg.CommentID)
+	return err
+}
+
+const createNotification = `-- name: CreateNotification :exec
+INSERT INTO Notifications (user_id, actor_id, type, topic_id, post_id, comment_id, message)
+SELECT $1::bigint, $2::bigint, $3::text,
+$4::bigint, $5::bigint, $6::bigint,
+$7::text
+WHERE $1::bigint <> $2::bigint
+AND NOT EXISTS (
+    SELECT 1 FROM Notification_Preferences np
+    WHERE np.user_id = $1::bigint AND np.type = $3::text AND NOT np.enabled
+)
+AND NOT EXISTS (
+    SELECT 1 FROM Notifications n
+    WHERE n.user_id = $1::bigint AND n.actor_id = $2::bigint
+    AND n.type = $3::text AND n.read_at IS NULL
+    AND n.post_id IS NOT DISTINCT FROM $5::bigint
+    AND n.comment_id IS NOT DISTINCT FROM $6::bigint
+)
+`
+
+type CreateNotificationParams struct {
+	UserID    int64       `json:"user_id"`
+	ActorID   int64       `json:"actor_id"`
+	Type      string      `json:"type"`
+	TopicID   pgtype.Int8 `json:"topic_id"`
+	PostID    pgtype.Int8 `json:"post_id"`
+	CommentID pgtype.Int8 `json:"comment_id"`
+	Message   string      `json:"message"`
+}
+
+// Notifications Queries
+func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotificationParams) error {
+	_, err := q.db.Exec(ctx, createNotification,
+		arg.UserID,
+		arg.ActorID,
+		arg.Type,
+		arg.TopicID,
+		arg.PostID,
+		arg.CommentID,
+		arg.Message,
+	)
+	return err
+}
+
+const createPoll = `-- name: CreatePoll :exec
+INSERT INTO Polls (post_id, question, multiple_choice, closes_at) VALUES ($1, $2, $3, $4)
+`
+
+type CreatePollParams struct {
+	PostID         int64              `json:"post_id"`
+	Question       string             `json:"question"`
+	MultipleChoice bool               `json:"multiple_choice"`
+	ClosesAt       pgtype.Timestamptz `json:"closes_at"`
+}
+
+func (q *Queries) CreatePoll(ctx context.Context, arg CreatePollParams) error {
+	_, err := q.db.Exec(ctx, createPoll,
+		arg.PostID,
+		arg.Question,
+		arg.MultipleChoice,
+		arg.ClosesAt,
+	)
+	return err
+}
+
+const createPollBallotOptions = `-- name: CreatePollBallotOptions :execrows
+INSERT INTO Poll_Ballot_Options (post_id, user_id, option_id)
+SELECT o.post_id, $1, o.option_id FROM Poll_Options o
+WHERE o.post_id = $2 AND o.option_id = ANY($3::bigint[])
+`
+
+type CreatePollBallotOptionsParams struct {
+	UserID    int64   `json:"user_id"`
+	PostID    int64   `json:"post_id"`
+	OptionIds []int64 `json:"option_ids"`
+}
+
+func (q *Queries) CreatePollBallotOptions(ctx context.Context, arg CreatePollBallotOptionsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, createPollBallotOptions, arg.UserID, arg.PostID, arg.OptionIds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createPollOptions = `-- name: CreatePollOptions :exec
+INSERT INTO Poll_Options (post_id, position, label)
+SELECT $1, o.position, o.label
+FROM unnest($2::text[]) WITH ORDINALITY AS o(label, position)
+`
+
+type CreatePollOptionsParams struct {
+	PostID int64    `json:"post_id"`
+	Labels []string `json:"labels"`
+}
+
+func (q *Queries) CreatePollOptions(ctx context.Context, arg CreatePollOptionsParams) error {
+	_, err := q.db.Exec(ctx, createPollOptions, arg.PostID, arg.Labels)
+	return err
+}
+
 const createPost = `-- name: CreatePost :one
-INSERT INTO Posts (topic_id, user_id, title, description) VALUES ($1, $2, $3, $4) RETURNING post_id, topic_id, user_id, title, description, created_at, updated_at
+INSERT INTO Posts (topic_id, user_id, title, description) VALUES ($1, $2, $3, $4) RETURNING post_id, topic_id, user_id, title, description, created_at, updated_at, search_vector, deleted_at, deleted_by, edit_count
 `
 
 type CreatePostParams struct {
@@ -62,394 +641,2786 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.EditCount,
 	)
 	return i, err
 }
 
-const createTopic = `-- name: CreateTopic :one
-INSERT INTO Topics (user_id, title) VALUES ($1, $2) RETURNING topic_id, user_id, title, created_at
+const createPostRevision = `-- name: CreatePostRevision :exec
+INSERT INTO Post_Revisions (post_id, version, title, description, replaced_by, created_at)
+SELECT post_id, edit_count + 1, title, description, $1::bigint, updated_at
+FROM Posts WHERE post_id = $2 AND deleted_at IS NULL
+FOR UPDATE
 `
 
-type CreateTopicParams struct {
-	UserID int64  `json:"user_id"`
-	Title  string `json:"title"`
+type CreatePostRevisionParams struct {
+	ReplacedBy int64 `json:"replaced_by"`
+	PostID     int64 `json:"post_id"`
 }
 
-func (q *Queries) CreateTopic(ctx context.Context, arg CreateTopicParams) (Topic, error) {
-	row := q.db.QueryRow(ctx, createTopic, arg.UserID, arg.Title)
-	var i Topic
+func (q *Queries) CreatePostRevision(ctx context.Context, arg CreatePostRevisionParams) error {
+	_, err := q.db.Exec(ctx, createPostRevision, arg.ReplacedBy, arg.PostID)
+	return err
+}
+
+const createRefreshToken = `-- name: CreateRefreshToken :one
+INSERT INTO Refresh_Tokens (user_id, token_hash, expires_at) VALUES ($1, $2, $3) RETURNING token_id, user_id, token_hash, expires_at, revoked_at, created_at
+`
+
+type CreateRefreshTokenParams struct {
+	UserID    int64              `json:"user_id"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+// Tokens Queries
+func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error) {
+	row := q.db.QueryRow(ctx, createRefreshToken, arg.UserID, arg.TokenHash, arg.ExpiresAt)
+	var i RefreshToken
+	err := row.Scan(
+		&i.TokenID,
+		&i.UserID,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createReport = `-- name: CreateReport :one
+INSERT INTO Reports (reporter_id, target_type, target_id, topic_id, reason, details)
+VALUES ($1, $2, $3, $4, $5, $6) RETURNING report_id, reporter_id, target_type, target_id, topic_id, reason, details, status, action, resolved_by, resolution_note, resolved_at, created_at
+`
+
+type CreateReportParams struct {
+	ReporterID int64       `json:"reporter_id"`
+	TargetType string      `json:"target_type"`
+	TargetID   int64       `json:"target_id"`
+	TopicID    pgtype.Int8 `json:"topic_id"`
+	Reason     string      `json:"reason"`
+	Details    string      `json:"details"`
+}
+
+// Reports Queries
+func (q *Queries) CreateReport(ctx context.Context, arg CreateReportParams) (Report, error) {
+	row := q.db.QueryRow(ctx, createReport,
+		arg.ReporterID,
+		arg.TargetType,
+		arg.TargetID,
+		arg.TopicID,
+		arg.Reason,
+		arg.Details,
+	)
+	var i Report
 	err := row.Scan(
+		&i.ReportID,
+		&i.ReporterID,
+		&i.TargetType,
+		&i.TargetID,
 		&i.TopicID,
+		&i.Reason,
+		&i.Details,
+		&i.Status,
+		&i.Action,
+		&i.ResolvedBy,
+		&i.ResolutionNote,
+		&i.ResolvedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createSuspension = `-- name: CreateSuspension :one
+INSERT INTO Suspensions (user_id, topic_id, reason, expires_at, created_by, report_id)
+VALUES ($1, $2, $3, $4, $5, $6) RETURNING suspension_id, user_id, topic_id, reason, expires_at, created_by, report_id, created_at, lifted_at, lifted_by
+`
+
+type CreateSuspensionParams struct {
+	UserID    int64              `json:"user_id"`
+	TopicID   pgtype.Int8        `json:"topic_id"`
+	Reason    string             `json:"reason"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	CreatedBy pgtype.Int8        `json:"created_by"`
+	ReportID  pgtype.Int8        `json:"report_id"`
+}
+
+func (q *Queries) CreateSuspension(ctx context.Context, arg CreateSuspensionParams) (Suspension, error) {
+	row := q.db.QueryRow(ctx, createSuspension,
+		arg.UserID,
+		arg.TopicID,
+		arg.Reason,
+		arg.ExpiresAt,
+		arg.CreatedBy,
+		arg.ReportID,
+	)
+	var i Suspension
+	err := row.Scan(
+		&i.SuspensionID,
 		&i.UserID,
-		&i.Title,
+		&i.TopicID,
+		&i.Reason,
+		&i.ExpiresAt,
+		&i.CreatedBy,
+		&i.ReportID,
 		&i.CreatedAt,
+		&i.LiftedAt,
+		&i.LiftedBy,
 	)
 	return i, err
 }
 
-const createUser = `-- name: CreateUser :one
-INSERT INTO Users (name) VALUES ($1) RETURNING user_id, name
+const createTopic = `-- name: CreateTopic :one
+INSERT INTO Topics (user_id, title) VALUES ($1, $2) RETURNING topic_id, user_id, title, created_at, search_vector
+`
+
+type CreateTopicParams struct {
+	UserID int64  `json:"user_id"`
+	Title  string `json:"title"`
+}
+
+func (q *Queries) CreateTopic(ctx context.Context, arg CreateTopicParams) (Topic, error) {
+	row := q.db.QueryRow(ctx, createTopic, arg.UserID, arg.Title)
+	var i Topic
+	err := row.Scan(
+		&i.TopicID,
+		&i.UserID,
+		&i.Title,
+		&i.CreatedAt,
+		&i.SearchVector,
+	)
+	return i, err
+}
+
+const createUser = `-- name: CreateUser :one
+INSERT INTO Users (name, password_hash) VALUES ($1, $2) RETURNING user_id, name, password_hash, must_change_password, tokens_revoked_at, role
+`
+
+type CreateUserParams struct {
+	Name         string      `json:"name"`
+	PasswordHash pgtype.Text `json:"password_hash"`
+}
+
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, createUser, arg.Name, arg.PasswordHash)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Name,
+		&i.PasswordHash,
+		&i.MustChangePassword,
+		&i.TokensRevokedAt,
+		&i.Role,
+	)
+	return i, err
+}
+
+const deleteAttachment = `-- name: DeleteAttachment :one
+DELETE FROM Attachments WHERE attachment_id = $1 RETURNING storage_key, thumbnail_key
+`
+
+type DeleteAttachmentRow struct {
+	StorageKey   string      `json:"storage_key"`
+	ThumbnailKey pgtype.Text `json:"thumbnail_key"`
+}
+
+func (q *Queries) DeleteAttachment(ctx context.Context, attachmentID int64) (DeleteAttachmentRow, error) {
+	row := q.db.QueryRow(ctx, deleteAttachment, attachmentID)
+	var i DeleteAttachmentRow
+	err := row.Scan(&i.StorageKey, &i.ThumbnailKey)
+	return i, err
+}
+
+const deleteComment = `-- name: DeleteComment :execrows
+UPDATE Comments SET deleted_at = now(), deleted_by = $2 WHERE comment_id = $1 AND deleted_at IS NULL
+`
+
+type DeleteCommentParams struct {
+	CommentID int64       `json:"comment_id"`
+	DeletedBy pgtype.Int8 `json:"deleted_by"`
+}
+
+func (q *Queries) DeleteComment(ctx context.Context, arg DeleteCommentParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteComment, arg.CommentID, arg.DeletedBy)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deletePollBallotOptions = `-- name: DeletePollBallotOptions :exec
+DELETE FROM Poll_Ballot_Options WHERE post_id = $1 AND user_id = $2
+`
+
+type DeletePollBallotOptionsParams struct {
+	PostID int64 `json:"post_id"`
+	UserID int64 `json:"user_id"`
+}
+
+func (q *Queries) DeletePollBallotOptions(ctx context.Context, arg DeletePollBallotOptionsParams) error {
+	_, err := q.db.Exec(ctx, deletePollBallotOptions, arg.PostID, arg.UserID)
+	return err
+}
+
+const deletePost = `-- name: DeletePost :execrows
+UPDATE Posts SET deleted_at = now(), deleted_by = $2 WHERE post_id = $1 AND deleted_at IS NULL
+`
+
+type DeletePostParams struct {
+	PostID    int64       `json:"post_id"`
+	DeletedBy pgtype.Int8 `json:"deleted_by"`
+}
+
+func (q *Queries) DeletePost(ctx context.Context, arg DeletePostParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePost, arg.PostID, arg.DeletedBy)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteTopic = `-- name: DeleteTopic :execrows
+DELETE FROM Topics WHERE topic_id = $1
+`
+
+func (q *Queries) DeleteTopic(ctx context.Context, topicID int64) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteTopic, topicID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteTopicAttachments = `-- name: DeleteTopicAttachments :many
+DELETE FROM Attachments a
+WHERE a.post_id IN (SELECT post_id FROM Posts WHERE topic_id = $1)
+OR a.comment_id IN (
+    SELECT c.comment_id FROM Comments c
+    JOIN Posts p ON p.post_id = c.post_id
+    WHERE p.topic_id = $1
+)
+RETURNING a.storage_key, a.thumbnail_key
+`
+
+type DeleteTopicAttachmentsRow struct {
+	StorageKey   string      `json:"storage_key"`
+	ThumbnailKey pgtype.Text `json:"thumbnail_key"`
+}
+
+func (q *Queries) DeleteTopicAttachments(ctx context.Context, topicID int64) ([]DeleteTopicAttachmentsRow, error) {
+	rows, err := q.db.Query(ctx, deleteTopicAttachments, topicID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DeleteTopicAttachmentsRow
+	for rows.Next() {
+		var i DeleteTopicAttachmentsRow
+		if err := rows.Scan(&i.StorageKey, &i.ThumbnailKey); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const dislikesComment = `-- name: DislikesComment :exec
+INSERT INTO Comment_Votes (comment_id, user_id, vote) VALUES ($1, $2, -1)
+ON CONFLICT (comment_id, user_id) DO UPDATE SET vote = -1 WHERE Comment_Votes.vote <> -1
+`
+
+type DislikesCommentParams struct {
+	CommentID int64 `json:"comment_id"`
+	UserID    int64 `json:"user_id"`
+}
+
+func (q *Queries) DislikesComment(ctx context.Context, arg DislikesCommentParams) error {
+	_, err := q.db.Exec(ctx, dislikesComment, arg.CommentID, arg.UserID)
+	return err
+}
+
+const dislikesPost = `-- name: DislikesPost :exec
+INSERT INTO Post_Votes (post_id, user_id, vote) VALUES ($1, $2, -1)
+ON CONFLICT (post_id, user_id) DO UPDATE SET vote = -1 WHERE Post_Votes.vote <> -1
+`
+
+type DislikesPostParams struct {
+	PostID int64 `json:"post_id"`
+	UserID int64 `json:"user_id"`
+}
+
+func (q *Queries) DislikesPost(ctx context.Context, arg DislikesPostParams) error {
+	_, err := q.db.Exec(ctx, dislikesPost, arg.PostID, arg.UserID)
+	return err
+}
+
+const findActiveSuspension = `-- name: FindActiveSuspension :one
+SELECT suspension_id, user_id, topic_id, reason, expires_at, created_by, report_id, created_at, lifted_at, lifted_by FROM Suspensions
+WHERE user_id = $1 AND lifted_at IS NULL AND (expires_at IS NULL OR expires_at > now())
+AND (topic_id IS NULL OR topic_id = $2)
+ORDER BY topic_id NULLS FIRST, expires_at DESC NULLS FIRST
+LIMIT 1
+`
+
+type FindActiveSuspensionParams struct {
+	UserID  int64       `json:"user_id"`
+	TopicID pgtype.Int8 `json:"topic_id"`
+}
+
+func (q *Queries) FindActiveSuspension(ctx context.Context, arg FindActiveSuspensionParams) (Suspension, error) {
+	row := q.db.QueryRow(ctx, findActiveSuspension, arg.UserID, arg.TopicID)
+	var i Suspension
+	err := row.Scan(
+		&i.SuspensionID,
+		&i.UserID,
+		&i.TopicID,
+		&i.Reason,
+		&i.ExpiresAt,
+		&i.CreatedBy,
+		&i.ReportID,
+		&i.CreatedAt,
+		&i.LiftedAt,
+		&i.LiftedBy,
+	)
+	return i, err
+}
+
+const findAttachment = `-- name: FindAttachment :one
+SELECT a.attachment_id, a.user_id, a.post_id, a.comment_id, a.storage_key, a.thumbnail_key, a.filename, a.content_type, a.size_bytes, a.width, a.height, a.created_at FROM Attachments a
+LEFT JOIN Comments c ON c.comment_id = a.comment_id
+JOIN Posts p ON p.post_id = COALESCE(a.post_id, c.post_id)
+WHERE a.attachment_id = $1 AND c.deleted_at IS NULL AND p.deleted_at IS NULL
+`
+
+func (q *Queries) FindAttachment(ctx context.Context, attachmentID int64) (Attachment, error) {
+	row := q.db.QueryRow(ctx, findAttachment, attachmentID)
+	var i Attachment
+	err := row.Scan(
+		&i.AttachmentID,
+		&i.UserID,
+		&i.PostID,
+		&i.CommentID,
+		&i.StorageKey,
+		&i.ThumbnailKey,
+		&i.Filename,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.Width,
+		&i.Height,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const findCommentAuthor = `-- name: FindCommentAuthor :one
+SELECT c.comment_id, c.post_id, c.user_id, p.topic_id
+FROM Comments c
+JOIN Posts p ON p.post_id = c.post_id
+WHERE c.comment_id = $1 AND c.deleted_at IS NULL AND p.deleted_at IS NULL
+`
+
+type FindCommentAuthorRow struct {
+	CommentID int64 `json:"comment_id"`
+	PostID    int64 `json:"post_id"`
+	UserID    int64 `json:"user_id"`
+	TopicID   int64 `json:"topic_id"`
+}
+
+func (q *Queries) FindCommentAuthor(ctx context.Context, commentID int64) (FindCommentAuthorRow, error) {
+	row := q.db.QueryRow(ctx, findCommentAuthor, commentID)
+	var i FindCommentAuthorRow
+	err := row.Scan(
+		&i.CommentID,
+		&i.PostID,
+		&i.UserID,
+		&i.TopicID,
+	)
+	return i, err
+}
+
+const findCommentParent = `-- name: FindCommentParent :one
+WITH RECURSIVE ancestors AS (
+    SELECT comment_id, parent_comment_id FROM Comments WHERE comment_id = $1
+    UNION ALL
+    SELECT c.comment_id, c.parent_comment_id FROM Comments c
+    JOIN ancestors a ON c.comment_id = a.parent_comment_id
+)
+SELECT comment_id, post_id, deleted_at, (SELECT COUNT(*) - 1 FROM ancestors)::int AS depth
+FROM Comments WHERE comment_id = $1
+`
+
+type FindCommentParentRow struct {
+	CommentID int64              `json:"comment_id"`
+	PostID    int64              `json:"post_id"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	Depth     int32              `json:"depth"`
+}
+
+func (q *Queries) FindCommentParent(ctx context.Context, commentID int64) (FindCommentParentRow, error) {
+	row := q.db.QueryRow(ctx, findCommentParent, commentID)
+	var i FindCommentParentRow
+	err := row.Scan(
+		&i.CommentID,
+		&i.PostID,
+		&i.DeletedAt,
+		&i.Depth,
+	)
+	return i, err
+}
+
+const findCommentReplies = `-- name: FindCommentReplies :many
+WITH RECURSIVE thread AS (
+    SELECT comment_id, 1 AS depth FROM Comments
+    WHERE parent_comment_id = ANY($1::bigint[])
+    UNION ALL
+    SELECT c.comment_id, t.depth + 1 FROM Comments c
+    JOIN thread t ON c.parent_comment_id = t.comment_id
+    WHERE t.depth < $2::int
+)
+SELECT a.comment_id, a.user_id, a.username, a.post_id, a.parent_comment_id, a.description, a.created_at, a.updated_at, a.edit_count, a.deleted_at, a.likes, a.dislikes, a.user_vote, a.saved, a.last_activity_at, sort_key($3::text, a.likes, a.dislikes, a.created_at, a.last_activity_at) AS sort_key
+FROM (
+    SELECT c.comment_id, c.user_id, u.name as username, c.post_id, c.parent_comment_id,
+    c.description, c.created_at, c.updated_at, c.edit_count, c.deleted_at,
+    COUNT(v.vote) FILTER (WHERE v.vote = 1) AS likes,
+    COUNT(v.vote) FILTER (WHERE v.vote = -1) AS dislikes,
+    MAX(uv.vote) AS user_vote,
+    EXISTS (SELECT 1 FROM Bookmarks s WHERE s.comment_id = c.comment_id AND s.user_id = $4) AS saved,
+    (SELECT COALESCE(MAX(r.created_at), c.created_at) FROM Comments r
+    WHERE r.parent_comment_id = c.comment_id AND r.deleted_at IS NULL)::timestamptz AS last_activity_at
+    FROM thread t
+    JOIN Comments c ON c.comment_id = t.comment_id
+    JOIN Users u ON u.user_id = c.user_id
+    LEFT JOIN Comment_Votes v ON c.comment_id = v.comment_id
+    LEFT JOIN Comment_Votes uv ON c.comment_id = uv.comment_id AND uv.user_id = $4
+    WHERE c.deleted_at IS NULL OR has_live_replies(c.comment_id)
+    GROUP BY c.comment_id, u.name
+) AS a
+ORDER BY sort_key DESC, a.updated_at DESC, a.comment_id DESC
+`
+
+type FindCommentRepliesParams struct {
+	RootIds  []int64 `json:"root_ids"`
+	MaxDepth int32   `json:"max_depth"`
+	Sort     string  `json:"sort"`
+	UserID   int64   `json:"user_id"`
+}
+
+type FindCommentRepliesRow struct {
+	CommentID       int64              `json:"comment_id"`
+	UserID          int64              `json:"user_id"`
+	Username        string             `json:"username"`
+	PostID          int64              `json:"post_id"`
+	ParentCommentID pgtype.Int8        `json:"parent_comment_id"`
+	Description     string             `json:"description"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	EditCount       int32              `json:"edit_count"`
+	DeletedAt       pgtype.Timestamptz `json:"deleted_at"`
+	Likes           int64              `json:"likes"`
+	Dislikes        int64              `json:"dislikes"`
+	UserVote        interface{}        `json:"user_vote"`
+	Saved           bool               `json:"saved"`
+	LastActivityAt  pgtype.Timestamptz `json:"last_activity_at"`
+	SortKey         float64            `json:"sort_key"`
+}
+
+func (q *Queries) FindCommentReplies(ctx context.Context, arg FindCommentRepliesParams) ([]FindCommentRepliesRow, error) {
+	rows, err := q.db.Query(ctx, findCommentReplies,
+		arg.RootIds,
+		arg.MaxDepth,
+		arg.Sort,
+		arg.UserID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindCommentRepliesRow
+	for rows.Next() {
+		var i FindCommentRepliesRow
+		if err := rows.Scan(
+			&i.CommentID,
+			&i.UserID,
+			&i.Username,
+			&i.PostID,
+			&i.ParentCommentID,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.EditCount,
+			&i.DeletedAt,
+			&i.Likes,
+			&i.Dislikes,
+			&i.UserVote,
+			&i.Saved,
+			&i.LastActivityAt,
+			&i.SortKey,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findCommentRevision = `-- name: FindCommentRevision :one
+SELECT r.version, r.description, r.replaced_by, COALESCE(u.name, '') AS replaced_by_name,
+r.created_at, r.replaced_at
+FROM Comment_Revisions r
+LEFT JOIN Users u ON u.user_id = r.replaced_by
+WHERE r.comment_id = $1 AND r.version = $2
+`
+
+type FindCommentRevisionParams struct {
+	CommentID int64 `json:"comment_id"`
+	Version   int32 `json:"version"`
+}
+
+type FindCommentRevisionRow struct {
+	Version        int32              `json:"version"`
+	Description    string             `json:"description"`
+	ReplacedBy     pgtype.Int8        `json:"replaced_by"`
+	ReplacedByName string             `json:"replaced_by_name"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	ReplacedAt     pgtype.Timestamptz `json:"replaced_at"`
+}
+
+func (q *Queries) FindCommentRevision(ctx context.Context, arg FindCommentRevisionParams) (FindCommentRevisionRow, error) {
+	row := q.db.QueryRow(ctx, findCommentRevision, arg.CommentID, arg.Version)
+	var i FindCommentRevisionRow
+	err := row.Scan(
+		&i.Version,
+		&i.Description,
+		&i.ReplacedBy,
+		&i.ReplacedByName,
+		&i.CreatedAt,
+		&i.ReplacedAt,
+	)
+	return i, err
+}
+
+const findCommentSnapshot = `-- name: FindCommentSnapshot :one
+SELECT comment_id, post_id, user_id, description, created_at, updated_at, parent_comment_id, deleted_at, search_vector, deleted_by, edit_count FROM Comments WHERE comment_id = $1
+`
+
+func (q *Queries) FindCommentSnapshot(ctx context.Context, commentID int64) (Comment, error) {
+	row := q.db.QueryRow(ctx, findCommentSnapshot, commentID)
+	var i Comment
+	err := row.Scan(
+		&i.CommentID,
+		&i.PostID,
+		&i.UserID,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ParentCommentID,
+		&i.DeletedAt,
+		&i.SearchVector,
+		&i.DeletedBy,
+		&i.EditCount,
+	)
+	return i, err
+}
+
+const findCommentVoteCounts = `-- name: FindCommentVoteCounts :one
+SELECT c.comment_id, c.post_id, p.topic_id,
+COUNT(v.vote) FILTER (WHERE v.vote = 1) AS likes,
+COUNT(v.vote) FILTER (WHERE v.vote = -1) AS dislikes
+FROM Comments c
+JOIN Posts p ON p.post_id = c.post_id
+LEFT JOIN Comment_Votes v ON v.comment_id = c.comment_id
+WHERE c.comment_id = $1
+GROUP BY c.comment_id, p.topic_id
+`
+
+type FindCommentVoteCountsRow struct {
+	CommentID int64 `json:"comment_id"`
+	PostID    int64 `json:"post_id"`
+	TopicID   int64 `json:"topic_id"`
+	Likes     int64 `json:"likes"`
+	Dislikes  int64 `json:"dislikes"`
+}
+
+func (q *Queries) FindCommentVoteCounts(ctx context.Context, commentID int64) (FindCommentVoteCountsRow, error) {
+	row := q.db.QueryRow(ctx, findCommentVoteCounts, commentID)
+	var i FindCommentVoteCountsRow
+	err := row.Scan(
+		&i.CommentID,
+		&i.PostID,
+		&i.TopicID,
+		&i.Likes,
+		&i.Dislikes,
+	)
+	return i, err
+}
+
+const findCommentsByPost = `-- name: FindCommentsByPost :many
+SELECT comment_id, user_id, username, post_id, parent_comment_id, description, created_at, updated_at, edit_count, deleted_at, likes, dislikes, user_vote, saved, last_activity_at, sort_key FROM (
+    SELECT a.comment_id, a.user_id, a.username, a.post_id, a.parent_comment_id, a.description, a.created_at, a.updated_at, a.edit_count, a.deleted_at, a.likes, a.dislikes, a.user_vote, a.saved, a.last_activity_at, sort_key($1::text, a.likes, a.dislikes, a.created_at, a.last_activity_at) AS sort_key
+    FROM (
+        SELECT c.comment_id, c.user_id, u.name as username, c.post_id, c.parent_comment_id,
+        c.description, c.created_at, c.updated_at, c.edit_count, c.deleted_at,
+        COUNT(v.vote) FILTER (WHERE v.vote = 1) AS likes,
+        COUNT(v.vote) FILTER (WHERE v.vote = -1) AS dislikes,
+        MAX(uv.vote) AS user_vote,
+        EXISTS (SELECT 1 FROM Bookmarks s WHERE s.comment_id = c.comment_id AND s.user_id = $2) AS saved,
+        (SELECT COALESCE(MAX(r.created_at), c.created_at) FROM Comments r
+        WHERE r.parent_comment_id = c.comment_id AND r.deleted_at IS NULL)::timestamptz AS last_activity_at
+        FROM Comments c
+        JOIN Users u ON u.user_id = c.user_id
+        LEFT JOIN Comment_Votes v ON c.comment_id = v.comment_id
+        LEFT JOIN Comment_Votes uv ON c.comment_id = uv.comment_id AND uv.user_id = $2
+        WHERE c.post_id = $3 AND c.parent_comment_id IS NULL
+        AND ($4::timestamptz IS NULL OR c.created_at >= $4::timestamptz)
+        AND (c.deleted_at IS NULL OR has_live_replies(c.comment_id))
+        GROUP BY c.comment_id, u.name
+    ) AS a
+) AS t
+WHERE $5::bigint IS NULL
+OR (t.sort_key, t.updated_at, t.comment_id) < (
+    $6::float8,
+    $7::timestamptz,
+    $5::bigint
+)
+ORDER BY t.sort_key DESC, t.updated_at DESC, t.comment_id DESC
+LIMIT $8
+`
+
+type FindCommentsByPostParams struct {
+	Sort            string             `json:"sort"`
+	UserID          int64              `json:"user_id"`
+	PostID          int64              `json:"post_id"`
+	Since           pgtype.Timestamptz `json:"since"`
+	CursorCommentID pgtype.Int8        `json:"cursor_comment_id"`
+	CursorSortKey   pgtype.Float8      `json:"cursor_sort_key"`
+	CursorUpdatedAt pgtype.Timestamptz `json:"cursor_updated_at"`
+	PageLimit       int32              `json:"page_limit"`
+}
+
+type FindCommentsByPostRow struct {
+	CommentID       int64              `json:"comment_id"`
+	UserID          int64              `json:"user_id"`
+	Username        string             `json:"username"`
+	PostID          int64              `json:"post_id"`
+	ParentCommentID pgtype.Int8        `json:"parent_comment_id"`
+	Description     string             `json:"description"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	EditCount       int32              `json:"edit_count"`
+	DeletedAt       pgtype.Timestamptz `json:"deleted_at"`
+	Likes           int64              `json:"likes"`
+	Dislikes        int64              `json:"dislikes"`
+	UserVote        interface{}        `json:"user_vote"`
+	Saved           bool               `json:"saved"`
+	LastActivityAt  pgtype.Timestamptz `json:"last_activity_at"`
+	SortKey         float64            `json:"sort_key"`
+}
+
+// Comments Queries
+func (q *Queries) FindCommentsByPost(ctx context.Context, arg FindCommentsByPostParams) ([]FindCommentsByPostRow, error) {
+	rows, err := q.db.Query(ctx, findCommentsByPost,
+		arg.Sort,
+		arg.UserID,
+		arg.PostID,
+		arg.Since,
+		arg.CursorCommentID,
+		arg.CursorSortKey,
+		arg.CursorUpdatedAt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindCommentsByPostRow
+	for rows.Next() {
+		var i FindCommentsByPostRow
+		if err := rows.Scan(
+			&i.CommentID,
+			&i.UserID,
+			&i.Username,
+			&i.PostID,
+			&i.ParentCommentID,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.EditCount,
+			&i.DeletedAt,
+			&i.Likes,
+			&i.Dislikes,
+			&i.UserVote,
+			&i.Saved,
+			&i.LastActivityAt,
+			&i.SortKey,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findDeletedComment = `-- name: FindDeletedComment :one
+SELECT c.comment_id, c.post_id, c.user_id, p.topic_id, c.deleted_at, c.deleted_by
+FROM Comments c
+JOIN Posts p ON p.post_id = c.post_id
+WHERE c.comment_id = $1 AND c.deleted_at IS NOT NULL AND p.deleted_at IS NULL
+`
+
+type FindDeletedCommentRow struct {
+	CommentID int64              `json:"comment_id"`
+	PostID    int64              `json:"post_id"`
+	UserID    int64              `json:"user_id"`
+	TopicID   int64              `json:"topic_id"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	DeletedBy pgtype.Int8        `json:"deleted_by"`
+}
+
+func (q *Queries) FindDeletedComment(ctx context.Context, commentID int64) (FindDeletedCommentRow, error) {
+	row := q.db.QueryRow(ctx, findDeletedComment, commentID)
+	var i FindDeletedCommentRow
+	err := row.Scan(
+		&i.CommentID,
+		&i.PostID,
+		&i.UserID,
+		&i.TopicID,
+		&i.DeletedAt,
+		&i.DeletedBy,
+	)
+	return i, err
+}
+
+const findDeletedPost = `-- name: FindDeletedPost :one
+SELECT post_id, topic_id, user_id, deleted_at, deleted_by FROM Posts
+WHERE post_id = $1 AND deleted_at IS NOT NULL
+`
+
+type FindDeletedPostRow struct {
+	PostID    int64              `json:"post_id"`
+	TopicID   int64              `json:"topic_id"`
+	UserID    int64              `json:"user_id"`
+	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	DeletedBy pgtype.Int8        `json:"deleted_by"`
+}
+
+func (q *Queries) FindDeletedPost(ctx context.Context, postID int64) (FindDeletedPostRow, error) {
+	row := q.db.QueryRow(ctx, findDeletedPost, postID)
+	var i FindDeletedPostRow
+	err := row.Scan(
+		&i.PostID,
+		&i.TopicID,
+		&i.UserID,
+		&i.DeletedAt,
+		&i.DeletedBy,
+	)
+	return i, err
+}
+
+const findFeed = `-- name: FindFeed :many
+SELECT post_id, topic_id, user_id, username, title, description, created_at, updated_at, edit_count, likes, dislikes, user_vote, saved, last_activity_at, sort_key FROM (
+    SELECT a.post_id, a.topic_id, a.user_id, a.username, a.title, a.description, a.created_at, a.updated_at, a.edit_count, a.likes, a.dislikes, a.user_vote, a.saved, a.last_activity_at, sort_key($1::text, a.likes, a.dislikes, a.created_at, a.last_activity_at) AS sort_key
+    FROM (
+        SELECT p.post_id, p.topic_id, p.user_id, u.name AS username,
+        p.title, p.description, p.created_at, p.updated_at, p.edit_count,
+        COUNT(v.vote) FILTER (WHERE v.vote = 1) AS likes,
+        COUNT(v.vote) FILTER (WHERE v.vote = -1) AS dislikes,
+        MAX(uv.vote) AS user_vote,
+        EXISTS (SELECT 1 FROM Bookmarks s WHERE s.post_id = p.post_id AND s.user_id = $2) AS saved,
+        (SELECT COALESCE(MAX(c.created_at), p.created_at) FROM Comments c
+        WHERE c.post_id = p.post_id AND c.deleted_at IS NULL)::timestamptz AS last_activity_at
+        FROM Posts p
+        JOIN Users u ON u.user_id = p.user_id
+        LEFT JOIN Post_Votes v ON p.post_id = v.post_id
+        LEFT JOIN Post_Votes uv ON p.post_id = uv.post_id AND uv.user_id = $2
+        WHERE (p.topic_id IN (SELECT ts.topic_id FROM Topic_Subscriptions ts WHERE ts.user_id = $2)
+        OR NOT EXISTS (SELECT 1 FROM Topic_Subscriptions ts WHERE ts.user_id = $2))
+        AND ($3::timestamptz IS NULL OR p.created_at >= $3::timestamptz)
+        AND p.deleted_at IS NULL
+        GROUP BY p.post_id, u.name
+    ) AS a
+) AS t
+WHERE $4::bigint IS NULL
+OR (t.sort_key, t.updated_at, t.post_id) < (
+    $5::float8,
+    $6::timestamptz,
+    $4::bigint
+)
+ORDER BY t.sort_key DESC, t.updated_at DESC, t.post_id DESC
+LIMIT $7
+`
+
+type FindFeedParams struct {
+	Sort            string             `json:"sort"`
+	UserID          int64              `json:"user_id"`
+	Since           pgtype.Timestamptz `json:"since"`
+	CursorPostID    pgtype.Int8        `json:"cursor_post_id"`
+	CursorSortKey   pgtype.Float8      `json:"cursor_sort_key"`
+	CursorUpdatedAt pgtype.Timestamptz `json:"cursor_updated_at"`
+	PageLimit       int32              `json:"page_limit"`
+}
+
+type FindFeedRow struct {
+	PostID         int64              `json:"post_id"`
+	TopicID        int64              `json:"topic_id"`
+	UserID         int64              `json:"user_id"`
+	Username       string             `json:"username"`
+	Title          string             `json:"title"`
+	Description    string             `json:"description"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	EditCount      int32              `json:"edit_count"`
+	Likes          int64              `json:"likes"`
+	Dislikes       int64              `json:"dislikes"`
+	UserVote       interface{}        `json:"user_vote"`
+	Saved          bool               `json:"saved"`
+	LastActivityAt pgtype.Timestamptz `json:"last_activity_at"`
+	SortKey        float64            `json:"sort_key"`
+}
+
+func (q *Queries) FindFeed(ctx context.Context, arg FindFeedParams) ([]FindFeedRow, error) {
+	rows, err := q.db.Query(ctx, findFeed,
+		arg.Sort,
+		arg.UserID,
+		arg.Since,
+		arg.CursorPostID,
+		arg.CursorSortKey,
+		arg.CursorUpdatedAt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindFeedRow
+	for rows.Next() {
+		var i FindFeedRow
+		if err := rows.Scan(
+			&i.PostID,
+			&i.TopicID,
+			&i.UserID,
+			&i.Username,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.EditCount,
+			&i.Likes,
+			&i.Dislikes,
+			&i.UserVote,
+			&i.Saved,
+			&i.LastActivityAt,
+			&i.SortKey,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findPoll = `-- name: FindPoll :one
+SELECT post_id, question, multiple_choice, closes_at, created_at FROM Polls WHERE post_id = $1
+`
+
+func (q *Queries) FindPoll(ctx context.Context, postID int64) (Poll, error) {
+	row := q.db.QueryRow(ctx, findPoll, postID)
+	var i Poll
+	err := row.Scan(
+		&i.PostID,
+		&i.Question,
+		&i.MultipleChoice,
+		&i.ClosesAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const findPollOptions = `-- name: FindPollOptions :many
+SELECT o.option_id, o.label, COUNT(b.user_id) AS votes,
+COALESCE(bool_or(b.user_id = $1), false)::boolean AS chosen
+FROM Poll_Options o
+LEFT JOIN Poll_Ballot_Options b ON b.option_id = o.option_id
+WHERE o.post_id = $2
+GROUP BY o.option_id
+ORDER BY o.position
+`
+
+type FindPollOptionsParams struct {
+	UserID int64 `json:"user_id"`
+	PostID int64 `json:"post_id"`
+}
+
+type FindPollOptionsRow struct {
+	OptionID int64  `json:"option_id"`
+	Label    string `json:"label"`
+	Votes    int64  `json:"votes"`
+	Chosen   bool   `json:"chosen"`
+}
+
+func (q *Queries) FindPollOptions(ctx context.Context, arg FindPollOptionsParams) ([]FindPollOptionsRow, error) {
+	rows, err := q.db.Query(ctx, findPollOptions, arg.UserID, arg.PostID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindPollOptionsRow
+	for rows.Next() {
+		var i FindPollOptionsRow
+		if err := rows.Scan(
+			&i.OptionID,
+			&i.Label,
+			&i.Votes,
+			&i.Chosen,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findPostAuthor = `-- name: FindPostAuthor :one
+SELECT post_id, topic_id, user_id FROM Posts WHERE post_id = $1 AND deleted_at IS NULL
+`
+
+type FindPostAuthorRow struct {
+	PostID  int64 `json:"post_id"`
+	TopicID int64 `json:"topic_id"`
+	UserID  int64 `json:"user_id"`
+}
+
+func (q *Queries) FindPostAuthor(ctx context.Context, postID int64) (FindPostAuthorRow, error) {
+	row := q.db.QueryRow(ctx, findPostAuthor, postID)
+	var i FindPostAuthorRow
+	err := row.Scan(&i.PostID, &i.TopicID, &i.UserID)
+	return i, err
+}
+
+const findPostByID = `-- name: FindPostByID :one
+SELECT DISTINCT p.post_id, p.topic_id, p.user_id, u.name AS username,
+p.title, p.description, p.created_at, p.updated_at, p.edit_count,
+COUNT(v.vote) FILTER (WHERE v.vote = 1) OVER (PARTITION BY p.post_id) AS likes,
+COUNT(v.vote) FILTER (WHERE v.vote = -1) OVER (PARTITION BY p.post_id) AS dislikes,
+MAX(uv.vote) OVER (PARTITION BY p.post_id) AS user_vote,
+EXISTS (SELECT 1 FROM Bookmarks s WHERE s.post_id = p.post_id AND s.user_id = $3) AS saved
+FROM Posts p
+JOIN Users u ON u.user_id = p.user_id
+LEFT JOIN Post_Votes v ON p.post_id = v.post_id
+LEFT JOIN Post_Votes uv ON p.post_id = uv.post_id AND uv.user_id = $3
+WHERE p.post_id = $1 AND p.topic_id = $2 AND p.deleted_at IS NULL
+`
+
+type FindPostByIDParams struct {
+	PostID  int64 `json:"post_id"`
+	TopicID int64 `json:"topic_id"`
+	UserID  int64 `json:"user_id"`
+}
+
+type FindPostByIDRow struct {
+	PostID      int64              `json:"post_id"`
+	TopicID     int64              `json:"topic_id"`
+	UserID      int64              `json:"user_id"`
+	Username    string             `json:"username"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	EditCount   int32              `json:"edit_count"`
+	Likes       int64              `json:"likes"`
+	Dislikes    int64              `json:"dislikes"`
+	UserVote    interface{}        `json:"user_vote"`
+	Saved       bool               `json:"saved"`
+}
+
+func (q *Queries) FindPostByID(ctx context.Context, arg FindPostByIDParams) (FindPostByIDRow, error) {
+	row := q.db.QueryRow(ctx, findPostByID, arg.PostID, arg.TopicID, arg.UserID)
+	var i FindPostByIDRow
+	err := row.Scan(
+		&i.PostID,
+		&i.TopicID,
+		&i.UserID,
+		&i.Username,
+		&i.Title,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EditCount,
+		&i.Likes,
+		&i.Dislikes,
+		&i.UserVote,
+		&i.Saved,
+	)
+	return i, err
+}
+
+const findPostRevision = `-- name: FindPostRevision :one
+SELECT r.version, r.title, r.description, r.replaced_by, COALESCE(u.name, '') AS replaced_by_name,
+r.created_at, r.replaced_at
+FROM Post_Revisions r
+LEFT JOIN Users u ON u.user_id = r.replaced_by
+WHERE r.post_id = $1 AND r.version = $2
+`
+
+type FindPostRevisionParams struct {
+	PostID  int64 `json:"post_id"`
+	Version int32 `json:"version"`
+}
+
+type FindPostRevisionRow struct {
+	Version        int32              `json:"version"`
+	Title          string             `json:"title"`
+	Description    string             `json:"description"`
+	ReplacedBy     pgtype.Int8        `json:"replaced_by"`
+	ReplacedByName string             `json:"replaced_by_name"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	ReplacedAt     pgtype.Timestamptz `json:"replaced_at"`
+}
+
+func (q *Queries) FindPostRevision(ctx context.Context, arg FindPostRevisionParams) (FindPostRevisionRow, error) {
+	row := q.db.QueryRow(ctx, findPostRevision, arg.PostID, arg.Version)
+	var i FindPostRevisionRow
+	err := row.Scan(
+		&i.Version,
+		&i.Title,
+		&i.Description,
+		&i.ReplacedBy,
+		&i.ReplacedByName,
+		&i.CreatedAt,
+		&i.ReplacedAt,
+	)
+	return i, err
+}
+
+const findPostSnapshot = `-- name: FindPostSnapshot :one
+SELECT post_id, topic_id, user_id, title, description, created_at, updated_at, search_vector, deleted_at, deleted_by, edit_count FROM Posts WHERE post_id = $1
+`
+
+func (q *Queries) FindPostSnapshot(ctx context.Context, postID int64) (Post, error) {
+	row := q.db.QueryRow(ctx, findPostSnapshot, postID)
+	var i Post
+	err := row.Scan(
+		&i.PostID,
+		&i.TopicID,
+		&i.UserID,
+		&i.Title,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.EditCount,
+	)
+	return i, err
+}
+
+const findPostVoteCounts = `-- name: FindPostVoteCounts :one
+SELECT p.post_id, p.topic_id,
+COUNT(v.vote) FILTER (WHERE v.vote = 1) AS likes,
+COUNT(v.vote) FILTER (WHERE v.vote = -1) AS dislikes
+FROM Posts p
+LEFT JOIN Post_Votes v ON v.post_id = p.post_id
+WHERE p.post_id = $1
+GROUP BY p.post_id
+`
+
+type FindPostVoteCountsRow struct {
+	PostID   int64 `json:"post_id"`
+	TopicID  int64 `json:"topic_id"`
+	Likes    int64 `json:"likes"`
+	Dislikes int64 `json:"dislikes"`
+}
+
+func (q *Queries) FindPostVoteCounts(ctx context.Context, postID int64) (FindPostVoteCountsRow, error) {
+	row := q.db.QueryRow(ctx, findPostVoteCounts, postID)
+	var i FindPostVoteCountsRow
+	err := row.Scan(
+		&i.PostID,
+		&i.TopicID,
+		&i.Likes,
+		&i.Dislikes,
+	)
+	return i, err
+}
+
+const findPostsByTopic = `-- name: FindPostsByTopic :many
+SELECT post_id, topic_id, user_id, username, title, description, created_at, updated_at, edit_count, likes, dislikes, user_vote, saved, last_activity_at, sort_key FROM (
+    SELECT a.post_id, a.topic_id, a.user_id, a.username, a.title, a.description, a.created_at, a.updated_at, a.edit_count, a.likes, a.dislikes, a.user_vote, a.saved, a.last_activity_at, sort_key($1::text, a.likes, a.dislikes, a.created_at, a.last_activity_at) AS sort_key
+    FROM (
+        SELECT p.post_id, p.topic_id, p.user_id, u.name AS username,
+        p.title, p.description, p.created_at, p.updated_at, p.edit_count,
+        COUNT(v.vote) FILTER (WHERE v.vote = 1) AS likes,
+        COUNT(v.vote) FILTER (WHERE v.vote = -1) AS dislikes,
+        MAX(uv.vote) AS user_vote,
+        EXISTS (SELECT 1 FROM Bookmarks s WHERE s.post_id = p.post_id AND s.user_id = $2) AS saved,
+        (SELECT COALESCE(MAX(c.created_at), p.created_at) FROM Comments c
+        WHERE c.post_id = p.post_id AND c.deleted_at IS NULL)::timestamptz AS last_activity_at
+        FROM Posts p
+        JOIN Users u ON u.user_id = p.user_id
+        LEFT JOIN Post_Votes v ON p.post_id = v.post_id
+        LEFT JOIN Post_Votes uv ON p.post_id = uv.post_id AND uv.user_id = $2
+        WHERE p.topic_id = $3
+        AND ($4::timestamptz IS NULL OR p.created_at >= $4::timestamptz)
+        AND p.deleted_at IS NULL
+        GROUP BY p.post_id, u.name
+    ) AS a
+) AS t
+WHERE $5::bigint IS NULL
+OR (t.sort_key, t.updated_at, t.post_id) < (
+    $6::float8,
+    $7::timestamptz,
+    $5::bigint
+)
+ORDER BY t.sort_key DESC, t.updated_at DESC, t.post_id DESC
+LIMIT $8
+`
+
+type FindPostsByTopicParams struct {
+	Sort            string             `json:"sort"`
+	UserID          int64              `json:"user_id"`
+	TopicID         int64              `json:"topic_id"`
+	Since           pgtype.Timestamptz `json:"since"`
+	CursorPostID    pgtype.Int8        `json:"cursor_post_id"`
+	CursorSortKey   pgtype.Float8      `json:"cursor_sort_key"`
+	CursorUpdatedAt pgtype.Timestamptz `json:"cursor_updated_at"`
+	PageLimit       int32              `json:"page_limit"`
+}
+
+type FindPostsByTopicRow struct {
+	PostID         int64              `json:"post_id"`
+	TopicID        int64              `json:"topic_id"`
+	UserID         int64              `json:"user_id"`
+	Username       string             `json:"username"`
+	Title          string             `json:"title"`
+	Description    string             `json:"description"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+	EditCount      int32              `json:"edit_count"`
+	Likes          int64              `json:"likes"`
+	Dislikes       int64              `json:"dislikes"`
+	UserVote       interface{}        `json:"user_vote"`
+	Saved          bool               `json:"saved"`
+	LastActivityAt pgtype.Timestamptz `json:"last_activity_at"`
+	SortKey        float64            `json:"sort_key"`
+}
+
+// Posts Queries
+func (q *Queries) FindPostsByTopic(ctx context.Context, arg FindPostsByTopicParams) ([]FindPostsByTopicRow, error) {
+	rows, err := q.db.Query(ctx, findPostsByTopic,
+		arg.Sort,
+		arg.UserID,
+		arg.TopicID,
+		arg.Since,
+		arg.CursorPostID,
+		arg.CursorSortKey,
+		arg.CursorUpdatedAt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindPostsByTopicRow
+	for rows.Next() {
+		var i FindPostsByTopicRow
+		if err := rows.Scan(
+			&i.PostID,
+			&i.TopicID,
+			&i.UserID,
+			&i.Username,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.EditCount,
+			&i.Likes,
+			&i.Dislikes,
+			&i.UserVote,
+			&i.Saved,
+			&i.LastActivityAt,
+			&i.SortKey,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findRateLimitTokens = `-- name: FindRateLimitTokens :one
+SELECT LEAST($1::float8, tokens + EXTRACT(EPOCH FROM now() - updated_at)::float8 / $2::float8)::float8 AS tokens
+FROM Rate_Limits
+WHERE bucket_key = $3
+`
+
+type FindRateLimitTokensParams struct {
+	Burst           float64 `json:"burst"`
+	IntervalSeconds float64 `json:"interval_seconds"`
+	BucketKey       string  `json:"bucket_key"`
+}
+
+func (q *Queries) FindRateLimitTokens(ctx context.Context, arg FindRateLimitTokensParams) (float64, error) {
+	row := q.db.QueryRow(ctx, findRateLimitTokens, arg.Burst, arg.IntervalSeconds, arg.BucketKey)
+	var tokens float64
+	err := row.Scan(&tokens)
+	return tokens, err
+}
+
+const findRefreshToken = `-- name: FindRefreshToken :one
+SELECT token_id, user_id, token_hash, expires_at, revoked_at, created_at FROM Refresh_Tokens WHERE token_hash = $1
+`
+
+func (q *Queries) FindRefreshToken(ctx context.Context, tokenHash string) (RefreshToken, error) {
+	row := q.db.QueryRow(ctx, findRefreshToken, tokenHash)
+	var i RefreshToken
+	err := row.Scan(
+		&i.TokenID,
+		&i.UserID,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const findSuspensionByID = `-- name: FindSuspensionByID :one
+SELECT suspension_id, user_id, topic_id, reason, expires_at, created_by, report_id, created_at, lifted_at, lifted_by FROM Suspensions WHERE suspension_id = $1
+`
+
+func (q *Queries) FindSuspensionByID(ctx context.Context, suspensionID int64) (Suspension, error) {
+	row := q.db.QueryRow(ctx, findSuspensionByID, suspensionID)
+	var i Suspension
+	err := row.Scan(
+		&i.SuspensionID,
+		&i.UserID,
+		&i.TopicID,
+		&i.Reason,
+		&i.ExpiresAt,
+		&i.CreatedBy,
+		&i.ReportID,
+		&i.CreatedAt,
+		&i.LiftedAt,
+		&i.LiftedBy,
+	)
+	return i, err
+}
+
+const findTopicByID = `-- name: FindTopicByID :one
+SELECT topic_id, user_id, title, created_at, search_vector FROM Topics WHERE topic_id = $1
+`
+
+func (q *Queries) FindTopicByID(ctx context.Context, topicID int64) (Topic, error) {
+	row := q.db.QueryRow(ctx, findTopicByID, topicID)
+	var i Topic
+	err := row.Scan(
+		&i.TopicID,
+		&i.UserID,
+		&i.Title,
+		&i.CreatedAt,
+		&i.SearchVector,
+	)
+	return i, err
+}
+
+const findUserByID = `-- name: FindUserByID :one
+SELECT user_id, name, password_hash, must_change_password, tokens_revoked_at, role FROM Users WHERE user_id = $1
+`
+
+func (q *Queries) FindUserByID(ctx context.Context, userID int64) (User, error) {
+	row := q.db.QueryRow(ctx, findUserByID, userID)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Name,
+		&i.PasswordHash,
+		&i.MustChangePassword,
+		&i.TokensRevokedAt,
+		&i.Role,
+	)
+	return i, err
+}
+
+const findUserByName = `-- name: FindUserByName :one
+SELECT user_id, name, password_hash, must_change_password, tokens_revoked_at, role FROM Users WHERE name = $1
+`
+
+// Users Queries
+func (q *Queries) FindUserByName(ctx context.Context, name string) (User, error) {
+	row := q.db.QueryRow(ctx, findUserByName, name)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Name,
+		&i.PasswordHash,
+		&i.MustChangePassword,
+		&i.TokensRevokedAt,
+		&i.Role,
+	)
+	return i, err
+}
+
+const findUsersByNames = `-- name: FindUsersByNames :many
+SELECT user_id, name FROM Users WHERE name = ANY($1::text[])
+`
+
+type FindUsersByNamesRow struct {
+	UserID int64  `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+func (q *Queries) FindUsersByNames(ctx context.Context, names []string) ([]FindUsersByNamesRow, error) {
+	rows, err := q.db.Query(ctx, findUsersByNames, names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindUsersByNamesRow
+	for rows.Next() {
+		var i FindUsersByNamesRow
+		if err := rows.Scan(&i.UserID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const isTokenRevoked = `-- name: IsTokenRevoked :one
+SELECT (
+    EXISTS (SELECT 1 FROM Revoked_Tokens WHERE jti = $1)
+    OR EXISTS (
+        SELECT 1 FROM Users
+        WHERE user_id = $2 AND date_trunc('second', tokens_revoked_at) > $3::timestamptz
+    )
+)::boolean AS revoked
+`
+
+type IsTokenRevokedParams struct {
+	Jti      string             `json:"jti"`
+	UserID   int64              `json:"user_id"`
+	IssuedAt pgtype.Timestamptz `json:"issued_at"`
+}
+
+func (q *Queries) IsTokenRevoked(ctx context.Context, arg IsTokenRevokedParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isTokenRevoked, arg.Jti, arg.UserID, arg.IssuedAt)
+	var revoked bool
+	err := row.Scan(&revoked)
+	return revoked, err
+}
+
+const liftSuspension = `-- name: LiftSuspension :execrows
+UPDATE Suspensions SET lifted_at = now(), lifted_by = $2 WHERE suspension_id = $1 AND lifted_at IS NULL
+`
+
+type LiftSuspensionParams struct {
+	SuspensionID int64       `json:"suspension_id"`
+	LiftedBy     pgtype.Int8 `json:"lifted_by"`
+}
+
+func (q *Queries) LiftSuspension(ctx context.Context, arg LiftSuspensionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, liftSuspension, arg.SuspensionID, arg.LiftedBy)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const likesComment = `-- name: LikesComment :exec
+INSERT INTO Comment_Votes (comment_id, user_id, vote) VALUES ($1, $2, 1)
+ON CONFLICT (comment_id, user_id) DO UPDATE SET vote = 1 WHERE Comment_Votes.vote <> 1
+`
+
+type LikesCommentParams struct {
+	CommentID int64 `json:"comment_id"`
+	UserID    int64 `json:"user_id"`
+}
+
+// Comment Votes
+func (q *Queries) LikesComment(ctx context.Context, arg LikesCommentParams) error {
+	_, err := q.db.Exec(ctx, likesComment, arg.CommentID, arg.UserID)
+	return err
+}
+
+const likesPost = `-- name: LikesPost :exec
+INSERT INTO Post_Votes (post_id, user_id, vote) VALUES ($1, $2, 1)
+ON CONFLICT (post_id, user_id) DO UPDATE SET vote = 1 WHERE Post_Votes.vote <> 1
+`
+
+type LikesPostParams struct {
+	PostID int64 `json:"post_id"`
+	UserID int64 `json:"user_id"`
+}
+
+// Post Votes
+func (q *Queries) LikesPost(ctx context.Context, arg LikesPostParams) error {
+	_, err := q.db.Exec(ctx, likesPost, arg.PostID, arg.UserID)
+	return err
+}
+
+const listActiveSuspensions = `-- name: ListActiveSuspensions :many
+SELECT s.suspension_id, s.user_id, u.name AS username, s.topic_id, s.reason, s.expires_at,
+s.created_by, s.created_at
+FROM Suspensions s
+JOIN Users u ON u.user_id = s.user_id
+WHERE s.lifted_at IS NULL AND (s.expires_at IS NULL OR s.expires_at > now())
+AND ($1::boolean
+OR s.topic_id IN (SELECT m.topic_id FROM Topic_Moderators m WHERE m.user_id = $2))
+AND ($3::bigint IS NULL
+OR s.suspension_id < $3::bigint)
+ORDER BY s.suspension_id DESC
+LIMIT $4
+`
+
+type ListActiveSuspensionsParams struct {
+	AllTopics          bool        `json:"all_topics"`
+	ModeratorID        int64       `json:"moderator_id"`
+	CursorSuspensionID pgtype.Int8 `json:"cursor_suspension_id"`
+	PageLimit          int32       `json:"page_limit"`
+}
+
+type ListActiveSuspensionsRow struct {
+	SuspensionID int64              `json:"suspension_id"`
+	UserID       int64              `json:"user_id"`
+	Username     string             `json:"username"`
+	TopicID      pgtype.Int8        `json:"topic_id"`
+	Reason       string             `json:"reason"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
+	CreatedBy    pgtype.Int8        `json:"created_by"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) ListActiveSuspensions(ctx context.Context, arg ListActiveSuspensionsParams) ([]ListActiveSuspensionsRow, error) {
+	rows, err := q.db.Query(ctx, listActiveSuspensions,
+		arg.AllTopics,
+		arg.ModeratorID,
+		arg.CursorSuspensionID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListActiveSuspensionsRow
+	for rows.Next() {
+		var i ListActiveSuspensionsRow
+		if err := rows.Scan(
+			&i.SuspensionID,
+			&i.UserID,
+			&i.Username,
+			&i.TopicID,
+			&i.Reason,
+			&i.ExpiresAt,
+			&i.CreatedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuditLog = `-- name: ListAuditLog :many
+SELECT a.audit_id, a.actor_id, COALESCE(u.name, '') AS actor_name, a.action, a.target_type,
+a.target_id, a.topic_id, a.before, a.after, a.created_at
+FROM Audit_Log a
+LEFT JOIN Users u ON u.user_id = a.actor_id
+WHERE ($1::bigint IS NULL OR a.actor_id = $1::bigint)
+AND ($2::text IS NULL OR a.action = $2::text)
+AND ($3::text IS NULL OR a.target_type = $3::text)
+AND ($4::bigint IS NULL OR a.target_id = $4::bigint)
+AND ($5::bigint IS NULL OR a.topic_id = $5::bigint)
+AND ($6::timestamptz IS NULL OR a.created_at >= $6::timestamptz)
+AND ($7::timestamptz IS NULL OR a.created_at < $7::timestamptz)
+AND ($8::bigint IS NULL OR a.audit_id < $8::bigint)
+ORDER BY a.audit_id DESC
+LIMIT $9
+`
+
+type ListAuditLogParams struct {
+	ActorID       pgtype.Int8        `json:"actor_id"`
+	Action        pgtype.Text        `json:"action"`
+	TargetType    pgtype.Text        `json:"target_type"`
+	TargetID      pgtype.Int8        `json:"target_id"`
+	TopicID       pgtype.Int8        `json:"topic_id"`
+	Since         pgtype.Timestamptz `json:"since"`
+	Until         pgtype.Timestamptz `json:"until"`
+	CursorAuditID pgtype.Int8        `json:"cursor_audit_id"`
+	PageLimit     int32              `json:"page_limit"`
+}
+
+type ListAuditLogRow struct {
+	AuditID    int64              `json:"audit_id"`
+	ActorID    int64              `json:"actor_id"`
+	ActorName  string             `json:"actor_name"`
+	Action     string             `json:"action"`
+	TargetType string             `json:"target_type"`
+	TargetID   int64              `json:"target_id"`
+	TopicID    pgtype.Int8        `json:"topic_id"`
+	Before     []byte             `json:"before"`
+	After      []byte             `json:"after"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) ListAuditLog(ctx context.Context, arg ListAuditLogParams) ([]ListAuditLogRow, error) {
+	rows, err := q.db.Query(ctx, listAuditLog,
+		arg.ActorID,
+		arg.Action,
+		arg.TargetType,
+		arg.TargetID,
+		arg.TopicID,
+		arg.Since,
+		arg.Until,
+		arg.CursorAuditID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAuditLogRow
+	for rows.Next() {
+		var i ListAuditLogRow
+		if err := rows.Scan(
+			&i.AuditID,
+			&i.ActorID,
+			&i.ActorName,
+			&i.Action,
+			&i.TargetType,
+			&i.TargetID,
+			&i.TopicID,
+			&i.Before,
+			&i.After,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBookmarks = `-- name: ListBookmarks :many
+SELECT b.bookmark_id, p.topic_id, p.post_id, b.comment_id, p.title,
+COALESCE(c.description, p.description)::text AS description,
+COALESCE(c.user_id, p.user_id)::bigint AS author_id, u.name AS author_name,
+COALESCE(c.created_at, p.created_at)::timestamptz AS created_at, b.created_at AS saved_at
+FROM Bookmarks b
+LEFT JOIN Comments c ON c.comment_id = b.comment_id
+JOIN Posts p ON p.post_id = COALESCE(b.post_id, c.post_id)
+JOIN Users u ON u.user_id = COALESCE(c.user_id, p.user_id)
+WHERE b.user_id = $1 AND c.deleted_at IS NULL AND p.deleted_at IS NULL
+AND ($2::bigint IS NULL OR p.topic_id = $2::bigint)
+AND ($3::bigint IS NULL OR b.bookmark_id < $3::bigint)
+ORDER BY b.bookmark_id DESC
+LIMIT $4
+`
+
+type ListBookmarksParams struct {
+	UserID           int64       `json:"user_id"`
+	TopicID          pgtype.Int8 `json:"topic_id"`
+	CursorBookmarkID pgtype.Int8 `json:"cursor_bookmark_id"`
+	PageLimit        int32       `json:"page_limit"`
+}
+
+type ListBookmarksRow struct {
+	BookmarkID  int64              `json:"bookmark_id"`
+	TopicID     int64              `json:"topic_id"`
+	PostID      int64              `json:"post_id"`
+	CommentID   pgtype.Int8        `json:"comment_id"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	AuthorID    int64              `json:"author_id"`
+	AuthorName  string             `json:"author_name"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	SavedAt     pgtype.Timestamptz `json:"saved_at"`
+}
+
+func (q *Queries) ListBookmarks(ctx context.Context, arg ListBookmarksParams) ([]ListBookmarksRow, error) {
+	rows, err := q.db.Query(ctx, listBookmarks,
+		arg.UserID,
+		arg.TopicID,
+		arg.CursorBookmarkID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBookmarksRow
+	for rows.Next() {
+		var i ListBookmarksRow
+		if err := rows.Scan(
+			&i.BookmarkID,
+			&i.TopicID,
+			&i.PostID,
+			&i.CommentID,
+			&i.Title,
+			&i.Description,
+			&i.AuthorID,
+			&i.AuthorName,
+			&i.CreatedAt,
+			&i.SavedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCommentAttachments = `-- name: ListCommentAttachments :many
+SELECT attachment_id, user_id, post_id, comment_id, storage_key, thumbnail_key, filename, content_type, size_bytes, width, height, created_at FROM Attachments WHERE comment_id = $1 ORDER BY attachment_id
+`
+
+func (q *Queries) ListCommentAttachments(ctx context.Context, commentID pgtype.Int8) ([]Attachment, error) {
+	rows, err := q.db.Query(ctx, listCommentAttachments, commentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Attachment
+	for rows.Next() {
+		var i Attachment
+		if err := rows.Scan(
+			&i.AttachmentID,
+			&i.UserID,
+			&i.PostID,
+			&i.CommentID,
+			&i.StorageKey,
+			&i.ThumbnailKey,
+			&i.Filename,
+			&i.ContentType,
+			&i.SizeBytes,
+			&i.Width,
+			&i.Height,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCommentMentions = `-- name: ListCommentMentions :many
+SELECT m.comment_id, m.user_id, u.name FROM Mentions m
+JOIN Users u ON u.user_id = m.user_id
+WHERE m.comment_id = ANY($1::bigint[])
+ORDER BY m.mention_id
+`
+
+type ListCommentMentionsRow struct {
+	CommentID pgtype.Int8 `json:"comment_id"`
+	UserID    int64       `json:"user_id"`
+	Name      string      `json:"name"`
+}
+
+func (q *Queries) ListCommentMentions(ctx context.Context, commentIds []int64) ([]ListCommentMentionsRow, error) {
+	rows, err := q.db.Query(ctx, listCommentMentions, commentIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCommentMentionsRow
+	for rows.Next() {
+		var i ListCommentMentionsRow
+		if err := rows.Scan(&i.CommentID, &i.UserID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCommentRevisions = `-- name: ListCommentRevisions :many
+SELECT r.version, r.description, r.replaced_by, COALESCE(u.name, '') AS replaced_by_name,
+r.created_at, r.replaced_at
+FROM Comment_Revisions r
+LEFT JOIN Users u ON u.user_id = r.replaced_by
+WHERE r.comment_id = $1
+ORDER BY r.version DESC
+`
+
+type ListCommentRevisionsRow struct {
+	Version        int32              `json:"version"`
+	Description    string             `json:"description"`
+	ReplacedBy     pgtype.Int8        `json:"replaced_by"`
+	ReplacedByName string             `json:"replaced_by_name"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	ReplacedAt     pgtype.Timestamptz `json:"replaced_at"`
+}
+
+func (q *Queries) ListCommentRevisions(ctx context.Context, commentID int64) ([]ListCommentRevisionsRow, error) {
+	rows, err := q.db.Query(ctx, listCommentRevisions, commentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCommentRevisionsRow
+	for rows.Next() {
+		var i ListCommentRevisionsRow
+		if err := rows.Scan(
+			&i.Version,
+			&i.Description,
+			&i.ReplacedBy,
+			&i.ReplacedByName,
+			&i.CreatedAt,
+			&i.ReplacedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotificationPreferences = `-- name: ListNotificationPreferences :many
+SELECT user_id, type, enabled FROM Notification_Preferences WHERE user_id = $1
+`
+
+func (q *Queries) ListNotificationPreferences(ctx context.Context, userID int64) ([]NotificationPreference, error) {
+	rows, err := q.db.Query(ctx, listNotificationPreferences, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationPreference
+	for rows.Next() {
+		var i NotificationPreference
+		if err := rows.Scan(&i.UserID, &i.Type, &i.Enabled); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotifications = `-- name: ListNotifications :many
+SELECT n.notification_id, n.type, n.actor_id, u.name AS actor_name, n.topic_id, n.post_id,
+n.comment_id, n.message, n.read_at, n.created_at
+FROM Notifications n
+JOIN Users u ON u.user_id = n.actor_id
+WHERE n.user_id = $1
+AND (NOT $2::boolean OR n.read_at IS NULL)
+AND ($3::bigint IS NULL
+OR n.notification_id < $3::bigint)
+ORDER BY n.notification_id DESC
+LIMIT $4
+`
+
+type ListNotificationsParams struct {
+	UserID               int64       `json:"user_id"`
+	UnreadOnly           bool        `json:"unread_only"`
+	CursorNotificationID pgtype.Int8 `json:"cursor_notification_id"`
+	PageLimit            int32       `json:"page_limit"`
+}
+
+type ListNotificationsRow struct {
+	NotificationID int64              `json:"notification_id"`
+	Type           string             `json:"type"`
+	ActorID        int64              `json:"actor_id"`
+	ActorName      string             `json:"actor_name"`
+	TopicID        pgtype.Int8        `json:"topic_id"`
+	PostID         pgtype.Int8        `json:"post_id"`
+	CommentID      pgtype.Int8        `json:"comment_id"`
+	Message        string             `json:"message"`
+	ReadAt         pgtype.Timestamptz `json:"read_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]ListNotificationsRow, error) {
+	rows, err := q.db.Query(ctx, listNotifications,
+		arg.UserID,
+		arg.UnreadOnly,
+		arg.CursorNotificationID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListNotificationsRow
+	for rows.Next() {
+		var i ListNotificationsRow
+		if err := rows.Scan(
+			&i.NotificationID,
+			&i.Type,
+			&i.ActorID,
+			&i.ActorName,
+			&i.TopicID,
+			&i.PostID,
+			&i.CommentID,
+			&i.Message,
+			&i.ReadAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOpenReportsByTargets = `-- name: ListOpenReportsByTargets :many
+SELECT r.report_id, r.reporter_id, u.name AS reporter_name, r.target_type, r.target_id, r.reason,
+r.details, r.created_at
+FROM Reports r
+JOIN Users u ON u.user_id = r.reporter_id
+JOIN unnest($1::text[], $2::bigint[]) AS t(target_type, target_id)
+ON t.target_type = r.target_type AND t.target_id = r.target_id
+WHERE r.status = 'open'
+ORDER BY r.created_at, r.report_id
+`
+
+type ListOpenReportsByTargetsParams struct {
+	TargetTypes []string `json:"target_types"`
+	TargetIds   []int64  `json:"target_ids"`
+}
+
+type ListOpenReportsByTargetsRow struct {
+	ReportID     int64              `json:"report_id"`
+	ReporterID   int64              `json:"reporter_id"`
+	ReporterName string             `json:"reporter_name"`
+	TargetType   string             `json:"target_type"`
+	TargetID     int64              `json:"target_id"`
+	Reason       string             `json:"reason"`
+	Details      string             `json:"details"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) ListOpenReportsByTargets(ctx context.Context, arg ListOpenReportsByTargetsParams) ([]ListOpenReportsByTargetsRow, error) {
+	rows, err := q.db.Query(ctx, listOpenReportsByTargets, arg.TargetTypes, arg.TargetIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOpenReportsByTargetsRow
+	for rows.Next() {
+		var i ListOpenReportsByTargetsRow
+		if err := rows.Scan(
+			&i.ReportID,
+			&i.ReporterID,
+			&i.ReporterName,
+			&i.TargetType,
+			&i.TargetID,
+			&i.Reason,
+			&i.Details,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostAttachments = `-- name: ListPostAttachments :many
+SELECT attachment_id, user_id, post_id, comment_id, storage_key, thumbnail_key, filename, content_type, size_bytes, width, height, created_at FROM Attachments WHERE post_id = $1 ORDER BY attachment_id
+`
+
+func (q *Queries) ListPostAttachments(ctx context.Context, postID pgtype.Int8) ([]Attachment, error) {
+	rows, err := q.db.Query(ctx, listPostAttachments, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Attachment
+	for rows.Next() {
+		var i Attachment
+		if err := rows.Scan(
+			&i.AttachmentID,
+			&i.UserID,
+			&i.PostID,
+			&i.CommentID,
+			&i.StorageKey,
+			&i.ThumbnailKey,
+			&i.Filename,
+			&i.ContentType,
+			&i.SizeBytes,
+			&i.Width,
+			&i.Height,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostMentions = `-- name: ListPostMentions :many
+SELECT m.post_id, m.user_id, u.name FROM Mentions m
+JOIN Users u ON u.user_id = m.user_id
+WHERE m.post_id = ANY($1::bigint[])
+ORDER BY m.mention_id
+`
+
+type ListPostMentionsRow struct {
+	PostID pgtype.Int8 `json:"post_id"`
+	UserID int64       `json:"user_id"`
+	Name   string      `json:"name"`
+}
+
+func (q *Queries) ListPostMentions(ctx context.Context, postIds []int64) ([]ListPostMentionsRow, error) {
+	rows, err := q.db.Query(ctx, listPostMentions, postIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPostMentionsRow
+	for rows.Next() {
+		var i ListPostMentionsRow
+		if err := rows.Scan(&i.PostID, &i.UserID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostRevisions = `-- name: ListPostRevisions :many
+SELECT r.version, r.title, r.description, r.replaced_by, COALESCE(u.name, '') AS replaced_by_name,
+r.created_at, r.replaced_at
+FROM Post_Revisions r
+LEFT JOIN Users u ON u.user_id = r.replaced_by
+WHERE r.post_id = $1
+ORDER BY r.version DESC
+`
+
+type ListPostRevisionsRow struct {
+	Version        int32              `json:"version"`
+	Title          string             `json:"title"`
+	Description    string             `json:"description"`
+	ReplacedBy     pgtype.Int8        `json:"replaced_by"`
+	ReplacedByName string             `json:"replaced_by_name"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	ReplacedAt     pgtype.Timestamptz `json:"replaced_at"`
+}
+
+func (q *Queries) ListPostRevisions(ctx context.Context, postID int64) ([]ListPostRevisionsRow, error) {
+	rows, err := q.db.Query(ctx, listPostRevisions, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPostRevisionsRow
+	for rows.Next() {
+		var i ListPostRevisionsRow
+		if err := rows.Scan(
+			&i.Version,
+			&i.Title,
+			&i.Description,
+			&i.ReplacedBy,
+			&i.ReplacedByName,
+			&i.CreatedAt,
+			&i.ReplacedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listReportQueue = `-- name: ListReportQueue :many
+SELECT r.target_type, r.target_id, r.topic_id, COUNT(*) AS report_count,
+MIN(r.created_at)::timestamptz AS first_reported_at, MAX(r.created_at)::timestamptz AS last_reported_at
+FROM Reports r
+WHERE r.status = 'open'
+AND ($1::boolean
+OR r.topic_id IN (SELECT m.topic_id FROM Topic_Moderators m WHERE m.user_id = $2))
+GROUP BY r.target_type, r.target_id, r.topic_id
+HAVING $3::timestamptz IS NULL
+OR (MIN(r.created_at), r.target_type, r.target_id) > (
+    $3::timestamptz, $4::text,
+    $5::bigint
+)
+ORDER BY first_reported_at, r.target_type, r.target_id
+LIMIT $6
+`
+
+type ListReportQueueParams struct {
+	AllTopics        bool               `json:"all_topics"`
+	ModeratorID      int64              `json:"moderator_id"`
+	CursorReportedAt pgtype.Timestamptz `json:"cursor_reported_at"`
+	CursorTargetType pgtype.Text        `json:"cursor_target_type"`
+	CursorTargetID   pgtype.Int8        `json:"cursor_target_id"`
+	PageLimit        int32              `json:"page_limit"`
+}
+
+type ListReportQueueRow struct {
+	TargetType      string             `json:"target_type"`
+	TargetID        int64              `json:"target_id"`
+	TopicID         pgtype.Int8        `json:"topic_id"`
+	ReportCount     int64              `json:"report_count"`
+	FirstReportedAt pgtype.Timestamptz `json:"first_reported_at"`
+	LastReportedAt  pgtype.Timestamptz `json:"last_reported_at"`
+}
+
+func (q *Queries) ListReportQueue(ctx context.Context, arg ListReportQueueParams) ([]ListReportQueueRow, error) {
+	rows, err := q.db.Query(ctx, listReportQueue,
+		arg.AllTopics,
+		arg.ModeratorID,
+		arg.CursorReportedAt,
+		arg.CursorTargetType,
+		arg.CursorTargetID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListReportQueueRow
+	for rows.Next() {
+		var i ListReportQueueRow
+		if err := rows.Scan(
+			&i.TargetType,
+			&i.TargetID,
+			&i.TopicID,
+			&i.ReportCount,
+			&i.FirstReportedAt,
+			&i.LastReportedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubscribedTopics = `-- name: ListSubscribedTopics :many
+SELECT t.topic_id, t.user_id, t.title, t.created_at, t.search_vector FROM Topics t
+JOIN Topic_Subscriptions s ON s.topic_id = t.topic_id
+WHERE s.user_id = $1
+AND ($2::text IS NULL OR t.title > $2::text)
+ORDER BY t.title
+LIMIT $3
+`
+
+type ListSubscribedTopicsParams struct {
+	UserID      int64       `json:"user_id"`
+	CursorTitle pgtype.Text `json:"cursor_title"`
+	PageLimit   int32       `json:"page_limit"`
+}
+
+func (q *Queries) ListSubscribedTopics(ctx context.Context, arg ListSubscribedTopicsParams) ([]Topic, error) {
+	rows, err := q.db.Query(ctx, listSubscribedTopics, arg.UserID, arg.CursorTitle, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Topic
+	for rows.Next() {
+		var i Topic
+		if err := rows.Scan(
+			&i.TopicID,
+			&i.UserID,
+			&i.Title,
+			&i.CreatedAt,
+			&i.SearchVector,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTopicModerators = `-- name: ListTopicModerators :many
+SELECT m.topic_id, m.user_id, u.name AS username, m.created_at
+FROM Topic_Moderators m
+JOIN Users u ON u.user_id = m.user_id
+WHERE m.topic_id = $1
+ORDER BY u.name
+`
+
+type ListTopicModeratorsRow struct {
+	TopicID   int64              `json:"topic_id"`
+	UserID    int64              `json:"user_id"`
+	Username  string             `json:"username"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) ListTopicModerators(ctx context.Context, topicID int64) ([]ListTopicModeratorsRow, error) {
+	rows, err := q.db.Query(ctx, listTopicModerators, topicID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTopicModeratorsRow
+	for rows.Next() {
+		var i ListTopicModeratorsRow
+		if err := rows.Scan(
+			&i.TopicID,
+			&i.UserID,
+			&i.Username,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTopics = `-- name: ListTopics :many
+SELECT topic_id, user_id, title, created_at, search_vector FROM Topics
+WHERE $1::text IS NULL OR title > $1::text
+ORDER BY title
+LIMIT $2
+`
+
+type ListTopicsParams struct {
+	CursorTitle pgtype.Text `json:"cursor_title"`
+	PageLimit   int32       `json:"page_limit"`
+}
+
+// Topics Queries
+func (q *Queries) ListTopics(ctx context.Context, arg ListTopicsParams) ([]Topic, error) {
+	rows, err := q.db.Query(ctx, listTopics, arg.CursorTitle, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Topic
+	for rows.Next() {
+		var i Topic
+		if err := rows.Scan(
+			&i.TopicID,
+			&i.UserID,
+			&i.Title,
+			&i.CreatedAt,
+			&i.SearchVector,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserMentions = `-- name: ListUserMentions :many
+SELECT m.mention_id, p.topic_id, p.post_id, m.comment_id, p.title,
+COALESCE(c.description, p.description)::text AS description,
+COALESCE(c.user_id, p.user_id)::bigint AS author_id, u.name AS author_name,
+COALESCE(c.created_at, p.created_at)::timestamptz AS created_at
+FROM Mentions m
+LEFT JOIN Comments c ON c.comment_id = m.comment_id
+JOIN Posts p ON p.post_id = COALESCE(m.post_id, c.post_id)
+JOIN Users u ON u.user_id = COALESCE(c.user_id, p.user_id)
+WHERE m.user_id = $1 AND c.deleted_at IS NULL AND p.deleted_at IS NULL
+AND ($2::bigint IS NULL OR m.mention_id < $2::bigint)
+ORDER BY m.mention_id DESC
+LIMIT $3
+`
+
+type ListUserMentionsParams struct {
+	UserID          int64       `json:"user_id"`
+	CursorMentionID pgtype.Int8 `json:"cursor_mention_id"`
+	PageLimit       int32       `json:"page_limit"`
+}
+
+type ListUserMentionsRow struct {
+	MentionID   int64              `json:"mention_id"`
+	TopicID     int64              `json:"topic_id"`
+	PostID      int64              `json:"post_id"`
+	CommentID   pgtype.Int8        `json:"comment_id"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	AuthorID    int64              `json:"author_id"`
+	AuthorName  string             `json:"author_name"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) ListUserMentions(ctx context.Context, arg ListUserMentionsParams) ([]ListUserMentionsRow, error) {
+	rows, err := q.db.Query(ctx, listUserMentions, arg.UserID, arg.CursorMentionID, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUserMentionsRow
+	for rows.Next() {
+		var i ListUserMentionsRow
+		if err := rows.Scan(
+			&i.MentionID,
+			&i.TopicID,
+			&i.PostID,
+			&i.CommentID,
+			&i.Title,
+			&i.Description,
+			&i.AuthorID,
+			&i.AuthorName,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockReportByID = `-- name: LockReportByID :one
+SELECT report_id, reporter_id, target_type, target_id, topic_id, reason, details, status, action, resolved_by, resolution_note, resolved_at, created_at FROM Reports WHERE report_id = $1 FOR UPDATE
+`
+
+func (q *Queries) LockReportByID(ctx context.Context, reportID int64) (Report, error) {
+	row := q.db.QueryRow(ctx, lockReportByID, reportID)
+	var i Report
+	err := row.Scan(
+		&i.ReportID,
+		&i.ReporterID,
+		&i.TargetType,
+		&i.TargetID,
+		&i.TopicID,
+		&i.Reason,
+		&i.Details,
+		&i.Status,
+		&i.Action,
+		&i.ResolvedBy,
+		&i.ResolutionNote,
+		&i.ResolvedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const markAllNotificationsRead = `-- name: MarkAllNotificationsRead :execrows
+UPDATE Notifications SET read_at = now() WHERE user_id = $1 AND read_at IS NULL
+`
+
+func (q *Queries) MarkAllNotificationsRead(ctx context.Context, userID int64) (int64, error) {
+	result, err := q.db.Exec(ctx, markAllNotificationsRead, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const markNotificationRead = `-- name: MarkNotificationRead :execrows
+UPDATE Notifications SET read_at = COALESCE(read_at, now())
+WHERE notification_id = $1 AND user_id = $2
+`
+
+type MarkNotificationReadParams struct {
+	NotificationID int64 `json:"notification_id"`
+	UserID         int64 `json:"user_id"`
+}
+
+func (q *Queries) MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markNotificationRead, arg.NotificationID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const publishEvent = `-- name: PublishEvent :exec
+SELECT pg_notify('forum_events', $1::text)
 `
 
-func (q *Queries) CreateUser(ctx context.Context, name string) (User, error) {
-	row := q.db.QueryRow(ctx, createUser, name)
-	var i User
-	err := row.Scan(&i.UserID, &i.Name)
-	return i, err
+// Stream Queries
+func (q *Queries) PublishEvent(ctx context.Context, payload string) error {
+	_, err := q.db.Exec(ctx, publishEvent, payload)
+	return err
 }
 
-const deleteComment = `-- name: DeleteComment :execrows
-DELETE FROM Comments WHERE comment_id = $1 AND user_id = $2
+const purgeCommentTombstones = `-- name: PurgeCommentTombstones :execrows
+UPDATE Comments SET description = ''
+WHERE deleted_at < $1 AND description <> ''
 `
 
-type DeleteCommentParams struct {
-	CommentID int64 `json:"comment_id"`
-	UserID    int64 `json:"user_id"`
-}
-
-func (q *Queries) DeleteComment(ctx context.Context, arg DeleteCommentParams) (int64, error) {
-	result, err := q.db.Exec(ctx, deleteComment, arg.CommentID, arg.UserID)
+func (q *Queries) PurgeCommentTombstones(ctx context.Context, deletedBefore pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeCommentTombstones, deletedBefore)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected(), nil
 }
 
-const deletePost = `-- name: DeletePost :execrows
-DELETE FROM Posts WHERE post_id = $1 AND user_id = $2
+const purgeDeletedAttachments = `-- name: PurgeDeletedAttachments :many
+DELETE FROM Attachments a
+WHERE a.post_id IN (SELECT post_id FROM Posts WHERE deleted_at < $1)
+OR a.comment_id IN (
+    SELECT c.comment_id FROM Comments c
+    JOIN Posts p ON p.post_id = c.post_id
+    WHERE c.deleted_at < $1 OR p.deleted_at < $1
+)
+RETURNING a.storage_key, a.thumbnail_key
 `
 
-type DeletePostParams struct {
-	PostID int64 `json:"post_id"`
-	UserID int64 `json:"user_id"`
+type PurgeDeletedAttachmentsRow struct {
+	StorageKey   string      `json:"storage_key"`
+	ThumbnailKey pgtype.Text `json:"thumbnail_key"`
 }
 
-func (q *Queries) DeletePost(ctx context.Context, arg DeletePostParams) (int64, error) {
-	result, err := q.db.Exec(ctx, deletePost, arg.PostID, arg.UserID)
+func (q *Queries) PurgeDeletedAttachments(ctx context.Context, deletedBefore pgtype.Timestamptz) ([]PurgeDeletedAttachmentsRow, error) {
+	rows, err := q.db.Query(ctx, purgeDeletedAttachments, deletedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PurgeDeletedAttachmentsRow
+	for rows.Next() {
+		var i PurgeDeletedAttachmentsRow
+		if err := rows.Scan(&i.StorageKey, &i.ThumbnailKey); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeDeletedComments = `-- name: PurgeDeletedComments :execrows
+DELETE FROM Comments c
+WHERE c.deleted_at < $1
+AND NOT EXISTS (SELECT 1 FROM Comments r WHERE r.parent_comment_id = c.comment_id)
+`
+
+func (q *Queries) PurgeDeletedComments(ctx context.Context, deletedBefore pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedComments, deletedBefore)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected(), nil
 }
 
-const deleteTopic = `-- name: DeleteTopic :execrows
-DELETE FROM Topics WHERE topic_id = $1 AND user_id = $2
+const purgeDeletedPosts = `-- name: PurgeDeletedPosts :execrows
+DELETE FROM Posts WHERE deleted_at < $1
 `
 
-type DeleteTopicParams struct {
-	TopicID int64 `json:"topic_id"`
-	UserID  int64 `json:"user_id"`
+func (q *Queries) PurgeDeletedPosts(ctx context.Context, deletedBefore pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeDeletedPosts, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
-func (q *Queries) DeleteTopic(ctx context.Context, arg DeleteTopicParams) (int64, error) {
-	result, err := q.db.Exec(ctx, deleteTopic, arg.TopicID, arg.UserID)
+const purgeRateLimits = `-- name: PurgeRateLimits :execrows
+DELETE FROM Rate_Limits
+WHERE updated_at < $1
+`
+
+func (q *Queries) PurgeRateLimits(ctx context.Context, updatedAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeRateLimits, updatedAt)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected(), nil
 }
 
-const dislikesComment = `-- name: DislikesComment :exec
-INSERT INTO Comment_Votes (comment_id, user_id, vote) VALUES ($1, $2, -1)
-ON CONFLICT (comment_id, user_id) DO UPDATE SET vote = -1 WHERE Comment_Votes.vote <> -1
+const purgeRevokedTokens = `-- name: PurgeRevokedTokens :execrows
+DELETE FROM Revoked_Tokens WHERE expires_at < now()
 `
 
-type DislikesCommentParams struct {
-	CommentID int64 `json:"comment_id"`
-	UserID    int64 `json:"user_id"`
+func (q *Queries) PurgeRevokedTokens(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeRevokedTokens)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
-func (q *Queries) DislikesComment(ctx context.Context, arg DislikesCommentParams) error {
-	_, err := q.db.Exec(ctx, dislikesComment, arg.CommentID, arg.UserID)
+const purgeTombstoneRevisions = `-- name: PurgeTombstoneRevisions :exec
+DELETE FROM Comment_Revisions r USING Comments c
+WHERE r.comment_id = c.comment_id AND c.deleted_at < $1
+`
+
+func (q *Queries) PurgeTombstoneRevisions(ctx context.Context, deletedBefore pgtype.Timestamptz) error {
+	_, err := q.db.Exec(ctx, purgeTombstoneRevisions, deletedBefore)
 	return err
 }
 
-const dislikesPost = `-- name: DislikesPost :exec
-INSERT INTO Post_Votes (post_id, user_id, vote) VALUES ($1, $2, -1)
-ON CONFLICT (post_id, user_id) DO UPDATE SET vote = -1 WHERE Post_Votes.vote <> -1
+const removeCommentVote = `-- name: RemoveCommentVote :execrows
+DELETE FROM Comment_Votes WHERE comment_id = $1 AND user_id = $2
 `
 
-type DislikesPostParams struct {
-	PostID int64 `json:"post_id"`
-	UserID int64 `json:"user_id"`
+type RemoveCommentVoteParams struct {
+	CommentID int64 `json:"comment_id"`
+	UserID    int64 `json:"user_id"`
 }
 
-func (q *Queries) DislikesPost(ctx context.Context, arg DislikesPostParams) error {
-	_, err := q.db.Exec(ctx, dislikesPost, arg.PostID, arg.UserID)
-	return err
+func (q *Queries) RemoveCommentVote(ctx context.Context, arg RemoveCommentVoteParams) (int64, error) {
+	result, err := q.db.Exec(ctx, removeCommentVote, arg.CommentID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
-const findCommentsByPost = `-- name: FindCommentsByPost :many
-SELECT DISTINCT c.comment_id, c.user_id, u.name as username, c.post_id, c.description, c.created_at,
-c.updated_at, COUNT(v.vote) FILTER (WHERE v.vote = 1) OVER (PARTITION BY c.comment_id) AS likes,
-COUNT(v.vote) FILTER (WHERE v.vote = -1) OVER (PARTITION BY c.comment_id) AS dislikes,
-MAX(uv.vote) OVER (PARTITION BY c.comment_id) AS user_vote
-FROM Comments c
-JOIN Users u ON u.user_id = c.user_id
-LEFT JOIN Comment_Votes v ON c.comment_id = v.comment_id
-LEFT JOIN Comment_Votes uv ON c.comment_id = uv.comment_id AND uv.user_id = $2
-WHERE c.post_id = $1
-ORDER BY likes DESC, c.updated_at DESC
+const removePostVote = `-- name: RemovePostVote :execrows
+DELETE FROM Post_Votes WHERE post_id = $1 AND user_id = $2
 `
 
-type FindCommentsByPostParams struct {
+type RemovePostVoteParams struct {
 	PostID int64 `json:"post_id"`
 	UserID int64 `json:"user_id"`
 }
 
-type FindCommentsByPostRow struct {
-	CommentID   int64              `json:"comment_id"`
-	UserID      int64              `json:"user_id"`
-	Username    string             `json:"username"`
-	PostID      int64              `json:"post_id"`
-	Description string             `json:"description"`
-	CreatedAt   pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
-	Likes       int64              `json:"likes"`
-	Dislikes    int64              `json:"dislikes"`
-	UserVote    interface{}        `json:"user_vote"`
-}
-
-// Comments Queries
-func (q *Queries) FindCommentsByPost(ctx context.Context, arg FindCommentsByPostParams) ([]FindCommentsByPostRow, error) {
-	rows, err := q.db.Query(ctx, findCommentsByPost, arg.PostID, arg.UserID)
+func (q *Queries) RemovePostVote(ctx context.Context, arg RemovePostVoteParams) (int64, error) {
+	result, err := q.db.Exec(ctx, removePostVote, arg.PostID, arg.UserID)
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []FindCommentsByPostRow
-	for rows.Next() {
-		var i FindCommentsByPostRow
-		if err := rows.Scan(
-			&i.CommentID,
-			&i.UserID,
-			&i.Username,
-			&i.PostID,
-			&i.Description,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Likes,
-			&i.Dislikes,
-			&i.UserVote,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+		return 0, err
 	}
-	return items, nil
+	return result.RowsAffected(), nil
 }
 
-const findPostByID = `-- name: FindPostByID :one
-SELECT DISTINCT p.post_id, p.topic_id, p.user_id, u.name AS username,
-p.title, p.description, p.created_at, p.updated_at,
-COUNT(v.vote) FILTER (WHERE v.vote = 1) OVER (PARTITION BY p.post_id) AS likes,
-COUNT(v.vote) FILTER (WHERE v.vote = -1) OVER (PARTITION BY p.post_id) AS dislikes,
-MAX(uv.vote) OVER (PARTITION BY p.post_id) AS user_vote
-FROM Posts p
-JOIN Users u ON u.user_id = p.user_id
-LEFT JOIN Post_Votes v ON p.post_id = v.post_id
-LEFT JOIN Post_Votes uv ON p.post_id = uv.post_id AND uv.user_id = $3
-WHERE p.post_id = $1 AND p.topic_id = $2
+const removeTopicModerator = `-- name: RemoveTopicModerator :execrows
+DELETE FROM Topic_Moderators WHERE topic_id = $1 AND user_id = $2
 `
 
-type FindPostByIDParams struct {
-	PostID  int64 `json:"post_id"`
+type RemoveTopicModeratorParams struct {
 	TopicID int64 `json:"topic_id"`
 	UserID  int64 `json:"user_id"`
 }
 
-type FindPostByIDRow struct {
-	PostID      int64              `json:"post_id"`
-	TopicID     int64              `json:"topic_id"`
-	UserID      int64              `json:"user_id"`
-	Username    string             `json:"username"`
-	Title       string             `json:"title"`
-	Description string             `json:"description"`
-	CreatedAt   pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
-	Likes       int64              `json:"likes"`
-	Dislikes    int64              `json:"dislikes"`
-	UserVote    interface{}        `json:"user_vote"`
+func (q *Queries) RemoveTopicModerator(ctx context.Context, arg RemoveTopicModeratorParams) (int64, error) {
+	result, err := q.db.Exec(ctx, removeTopicModerator, arg.TopicID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
-func (q *Queries) FindPostByID(ctx context.Context, arg FindPostByIDParams) (FindPostByIDRow, error) {
-	row := q.db.QueryRow(ctx, findPostByID, arg.PostID, arg.TopicID, arg.UserID)
-	var i FindPostByIDRow
+const resetUserPassword = `-- name: ResetUserPassword :one
+UPDATE Users SET password_hash = $2, must_change_password = true WHERE name = $1 RETURNING user_id, name, password_hash, must_change_password, tokens_revoked_at, role
+`
+
+type ResetUserPasswordParams struct {
+	Name         string      `json:"name"`
+	PasswordHash pgtype.Text `json:"password_hash"`
+}
+
+func (q *Queries) ResetUserPassword(ctx context.Context, arg ResetUserPasswordParams) (User, error) {
+	row := q.db.QueryRow(ctx, resetUserPassword, arg.Name, arg.PasswordHash)
+	var i User
 	err := row.Scan(
-		&i.PostID,
-		&i.TopicID,
 		&i.UserID,
-		&i.Username,
-		&i.Title,
-		&i.Description,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-		&i.Likes,
-		&i.Dislikes,
-		&i.UserVote,
+		&i.Name,
+		&i.PasswordHash,
+		&i.MustChangePassword,
+		&i.TokensRevokedAt,
+		&i.Role,
 	)
 	return i, err
 }
 
-const findPostsByTopic = `-- name: FindPostsByTopic :many
-SELECT DISTINCT p.post_id, p.topic_id, p.user_id, u.name AS username,
-p.title, p.description, p.created_at, p.updated_at,
-COUNT(v.vote) FILTER (WHERE v.vote = 1) OVER (PARTITION BY p.post_id) AS likes,
-COUNT(v.vote) FILTER (WHERE v.vote = -1) OVER (PARTITION BY p.post_id) AS dislikes,
-MAX(uv.vote) OVER (PARTITION BY p.post_id) AS user_vote
-FROM Posts p
-JOIN Users u ON u.user_id = p.user_id
-LEFT JOIN Post_Votes v ON p.post_id = v.post_id
-LEFT JOIN Post_Votes uv ON p.post_id = uv.post_id AND uv.user_id = $2
-WHERE p.topic_id = $1
-ORDER BY likes DESC, p.updated_at DESC
+const resolveReports = `-- name: ResolveReports :execrows
+UPDATE Reports SET status = $1, action = $2, resolved_by = $3,
+resolution_note = $4, resolved_at = now()
+WHERE target_type = $5 AND target_id = $6 AND status = 'open'
 `
 
-type FindPostsByTopicParams struct {
-	TopicID int64 `json:"topic_id"`
-	UserID  int64 `json:"user_id"`
-}
-
-type FindPostsByTopicRow struct {
-	PostID      int64              `json:"post_id"`
-	TopicID     int64              `json:"topic_id"`
-	UserID      int64              `json:"user_id"`
-	Username    string             `json:"username"`
-	Title       string             `json:"title"`
-	Description string             `json:"description"`
-	CreatedAt   pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
-	Likes       int64              `json:"likes"`
-	Dislikes    int64              `json:"dislikes"`
-	UserVote    interface{}        `json:"user_vote"`
+type ResolveReportsParams struct {
+	Status         string      `json:"status"`
+	Action         pgtype.Text `json:"action"`
+	ResolvedBy     pgtype.Int8 `json:"resolved_by"`
+	ResolutionNote string      `json:"resolution_note"`
+	TargetType     string      `json:"target_type"`
+	TargetID       int64       `json:"target_id"`
 }
 
-// Posts Queries
-func (q *Queries) FindPostsByTopic(ctx context.Context, arg FindPostsByTopicParams) ([]FindPostsByTopicRow, error) {
-	rows, err := q.db.Query(ctx, findPostsByTopic, arg.TopicID, arg.UserID)
+func (q *Queries) ResolveReports(ctx context.Context, arg ResolveReportsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, resolveReports,
+		arg.Status,
+		arg.Action,
+		arg.ResolvedBy,
+		arg.ResolutionNote,
+		arg.TargetType,
+		arg.TargetID,
+	)
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []FindPostsByTopicRow
-	for rows.Next() {
-		var i FindPostsByTopicRow
-		if err := rows.Scan(
-			&i.PostID,
-			&i.TopicID,
-			&i.UserID,
-			&i.Username,
-			&i.Title,
-			&i.Description,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Likes,
-			&i.Dislikes,
-			&i.UserVote,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+		return 0, err
 	}
-	return items, nil
+	return result.RowsAffected(), nil
 }
 
-const findTopicByID = `-- name: FindTopicByID :one
-SELECT topic_id, user_id, title, created_at FROM Topics WHERE topic_id = $1
+const restoreComment = `-- name: RestoreComment :one
+UPDATE Comments SET deleted_at = NULL, deleted_by = NULL WHERE comment_id = $1 AND deleted_at IS NOT NULL
+RETURNING comment_id, post_id, user_id, description, created_at, updated_at, parent_comment_id, deleted_at, search_vector, deleted_by, edit_count
 `
 
-func (q *Queries) FindTopicByID(ctx context.Context, topicID int64) (Topic, error) {
-	row := q.db.QueryRow(ctx, findTopicByID, topicID)
-	var i Topic
+func (q *Queries) RestoreComment(ctx context.Context, commentID int64) (Comment, error) {
+	row := q.db.QueryRow(ctx, restoreComment, commentID)
+	var i Comment
+	err := row.Scan(
+		&i.CommentID,
+		&i.PostID,
+		&i.UserID,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ParentCommentID,
+		&i.DeletedAt,
+		&i.SearchVector,
+		&i.DeletedBy,
+		&i.EditCount,
+	)
+	return i, err
+}
+
+const restorePost = `-- name: RestorePost :one
+UPDATE Posts SET deleted_at = NULL, deleted_by = NULL WHERE post_id = $1 AND deleted_at IS NOT NULL
+RETURNING post_id, topic_id, user_id, title, description, created_at, updated_at, search_vector, deleted_at, deleted_by, edit_count
+`
+
+func (q *Queries) RestorePost(ctx context.Context, postID int64) (Post, error) {
+	row := q.db.QueryRow(ctx, restorePost, postID)
+	var i Post
 	err := row.Scan(
+		&i.PostID,
 		&i.TopicID,
 		&i.UserID,
 		&i.Title,
+		&i.Description,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.EditCount,
 	)
 	return i, err
 }
 
-const findUserByID = `-- name: FindUserByID :one
-SELECT user_id, name FROM Users WHERE user_id = $1
+const revokeAccessToken = `-- name: RevokeAccessToken :exec
+INSERT INTO Revoked_Tokens (jti, user_id, expires_at) VALUES ($1, $2, $3) ON CONFLICT (jti) DO NOTHING
+`
+
+type RevokeAccessTokenParams struct {
+	Jti       string             `json:"jti"`
+	UserID    int64              `json:"user_id"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) RevokeAccessToken(ctx context.Context, arg RevokeAccessTokenParams) error {
+	_, err := q.db.Exec(ctx, revokeAccessToken, arg.Jti, arg.UserID, arg.ExpiresAt)
+	return err
+}
+
+const revokeRefreshToken = `-- name: RevokeRefreshToken :execrows
+UPDATE Refresh_Tokens SET revoked_at = now() WHERE token_id = $1 AND revoked_at IS NULL
 `
 
-func (q *Queries) FindUserByID(ctx context.Context, userID int64) (User, error) {
-	row := q.db.QueryRow(ctx, findUserByID, userID)
-	var i User
-	err := row.Scan(&i.UserID, &i.Name)
-	return i, err
+func (q *Queries) RevokeRefreshToken(ctx context.Context, tokenID int64) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeRefreshToken, tokenID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
-const findUserByName = `-- name: FindUserByName :one
-SELECT user_id, name FROM Users WHERE name = $1
+const revokeUserRefreshTokens = `-- name: RevokeUserRefreshTokens :exec
+UPDATE Refresh_Tokens SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL
 `
 
-// Users Queries
-func (q *Queries) FindUserByName(ctx context.Context, name string) (User, error) {
-	row := q.db.QueryRow(ctx, findUserByName, name)
-	var i User
-	err := row.Scan(&i.UserID, &i.Name)
-	return i, err
+func (q *Queries) RevokeUserRefreshTokens(ctx context.Context, userID int64) error {
+	_, err := q.db.Exec(ctx, revokeUserRefreshTokens, userID)
+	return err
 }
 
-const likesComment = `-- name: LikesComment :exec
-INSERT INTO Comment_Votes (comment_id, user_id, vote) VALUES ($1, $2, 1)
-ON CONFLICT (comment_id, user_id) DO UPDATE SET vote = 1 WHERE Comment_Votes.vote <> 1
+const revokeUserTokens = `-- name: RevokeUserTokens :exec
+UPDATE Users SET tokens_revoked_at = now() WHERE user_id = $1
 `
 
-type LikesCommentParams struct {
-	CommentID int64 `json:"comment_id"`
-	UserID    int64 `json:"user_id"`
+func (q *Queries) RevokeUserTokens(ctx context.Context, userID int64) error {
+	_, err := q.db.Exec(ctx, revokeUserTokens, userID)
+	return err
 }
 
-// Comment Votes
-func (q *Queries) LikesComment(ctx context.Context, arg LikesCommentParams) error {
-	_, err := q.db.Exec(ctx, likesComment, arg.CommentID, arg.UserID)
+const saveComment = `-- name: SaveComment :exec
+INSERT INTO Bookmarks (user_id, comment_id) VALUES ($1, $2) ON CONFLICT (user_id, comment_id) DO NOTHING
+`
+
+type SaveCommentParams struct {
+	UserID    int64       `json:"user_id"`
+	CommentID pgtype.Int8 `json:"comment_id"`
+}
+
+func (q *Queries) SaveComment(ctx context.Context, arg SaveCommentParams) error {
+	_, err := q.db.Exec(ctx, saveComment, arg.UserID, arg.CommentID)
 	return err
 }
 
-const likesPost = `-- name: LikesPost :exec
-INSERT INTO Post_Votes (post_id, user_id, vote) VALUES ($1, $2, 1)
-ON CONFLICT (post_id, user_id) DO UPDATE SET vote = 1 WHERE Post_Votes.vote <> 1
+const savePost = `-- name: SavePost :exec
+INSERT INTO Bookmarks (user_id, post_id) VALUES ($1, $2) ON CONFLICT (user_id, post_id) DO NOTHING
 `
 
-type LikesPostParams struct {
-	PostID int64 `json:"post_id"`
-	UserID int64 `json:"user_id"`
+type SavePostParams struct {
+	UserID int64       `json:"user_id"`
+	PostID pgtype.Int8 `json:"post_id"`
 }
 
-// Post Votes
-func (q *Queries) LikesPost(ctx context.Context, arg LikesPostParams) error {
-	_, err := q.db.Exec(ctx, likesPost, arg.PostID, arg.UserID)
+func (q *Queries) SavePost(ctx context.Context, arg SavePostParams) error {
+	_, err := q.db.Exec(ctx, savePost, arg.UserID, arg.PostID)
 	return err
 }
 
-const listTopics = `-- name: ListTopics :many
-SELECT topic_id, user_id, title, created_at FROM Topics ORDER BY title
+const search = `-- name: Search :many
+SELECT t.result_type, t.result_id, t.topic_id, t.post_id, t.user_id, t.username, t.title,
+t.score, t.rank, t.created_at,
+ts_headline('english', t.content, websearch_to_tsquery('english', $1::text),
+    'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10') AS headline
+FROM search_results(
+    $1::text,
+    $2::text,
+    $3::text,
+    $4::bigint,
+    $5::timestamptz,
+    $6::timestamptz,
+    $7::bigint
+) AS t
+WHERE $8::bigint IS NULL OR (t.rank, t.result_type, t.result_id) < (
+    $9::real,
+    $10::text,
+    $8::bigint
+)
+ORDER BY t.rank DESC, t.result_type DESC, t.result_id DESC
+LIMIT $11
 `
 
-// Topics Queries
-func (q *Queries) ListTopics(ctx context.Context) ([]Topic, error) {
-	rows, err := q.db.Query(ctx, listTopics)
+type SearchParams struct {
+	Query         string             `json:"query"`
+	ResultType    pgtype.Text        `json:"result_type"`
+	Author        pgtype.Text        `json:"author"`
+	TopicID       pgtype.Int8        `json:"topic_id"`
+	CreatedAfter  pgtype.Timestamptz `json:"created_after"`
+	CreatedBefore pgtype.Timestamptz `json:"created_before"`
+	MinScore      pgtype.Int8        `json:"min_score"`
+	CursorID      pgtype.Int8        `json:"cursor_id"`
+	CursorRank    pgtype.Float4      `json:"cursor_rank"`
+	CursorType    pgtype.Text        `json:"cursor_type"`
+	PageLimit     int32              `json:"page_limit"`
+}
+
+type SearchRow struct {
+	ResultType string             `json:"result_type"`
+	ResultID   int64              `json:"result_id"`
+	TopicID    pgtype.Int8        `json:"topic_id"`
+	PostID     pgtype.Int8        `json:"post_id"`
+	UserID     int64              `json:"user_id"`
+	Username   string             `json:"username"`
+	Title      pgtype.Text        `json:"title"`
+	Score      int64              `json:"score"`
+	Rank       float32            `json:"rank"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	Headline   string             `json:"headline"`
+}
+
+// Search Queries
+func (q *Queries) Search(ctx context.Context, arg SearchParams) ([]SearchRow, error) {
+	rows, err := q.db.Query(ctx, search,
+		arg.Query,
+		arg.ResultType,
+		arg.Author,
+		arg.TopicID,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.MinScore,
+		arg.CursorID,
+		arg.CursorRank,
+		arg.CursorType,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Topic
+	var items []SearchRow
 	for rows.Next() {
-		var i Topic
+		var i SearchRow
 		if err := rows.Scan(
+			&i.ResultType,
+			&i.ResultID,
 			&i.TopicID,
+			&i.PostID,
 			&i.UserID,
+			&i.Username,
 			&i.Title,
+			&i.Score,
+			&i.Rank,
 			&i.CreatedAt,
+			&i.Headline,
 		); err != nil {
 			return nil, err
 		}
@@ -461,58 +3432,139 @@ func (q *Queries) ListTopics(ctx context.Context) ([]Topic, error) {
 	return items, nil
 }
 
-const removeCommentVote = `-- name: RemoveCommentVote :execrows
-DELETE FROM Comment_Votes WHERE comment_id = $1 AND user_id = $2
+const searchComment = `-- name: SearchComment :many
+SELECT t.comment_id, t.user_id, t.username, t.post_id, t.parent_comment_id, t.description,
+t.created_at, t.updated_at, t.edit_count, t.likes, t.dislikes, t.user_vote, t.saved, t.rank,
+ts_headline('english', t.description, websearch_to_tsquery('english', $1::text),
+    'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10') AS headline
+FROM (
+    SELECT c.comment_id, c.user_id, u.name AS username, c.post_id, c.parent_comment_id,
+    c.description, c.created_at, c.updated_at, c.edit_count,
+    COUNT(v.vote) FILTER (WHERE v.vote = 1) AS likes,
+    COUNT(v.vote) FILTER (WHERE v.vote = -1) AS dislikes,
+    MAX(uv.vote) AS user_vote,
+    EXISTS (SELECT 1 FROM Bookmarks s WHERE s.comment_id = c.comment_id AND s.user_id = $2) AS saved,
+    ts_rank(c.search_vector, websearch_to_tsquery('english', $1::text)) AS rank
+    FROM Comments c
+    JOIN Posts p ON p.post_id = c.post_id
+    JOIN Users u ON u.user_id = c.user_id
+    LEFT JOIN Comment_Votes v ON c.comment_id = v.comment_id
+    LEFT JOIN Comment_Votes uv ON c.comment_id = uv.comment_id AND uv.user_id = $2
+    WHERE p.topic_id = $3 AND c.deleted_at IS NULL AND p.deleted_at IS NULL
+    AND c.search_vector @@ websearch_to_tsquery('english', $1::text)
+    GROUP BY c.comment_id, u.name
+) AS t
+WHERE $4::bigint IS NULL
+OR (t.rank, t.comment_id) < ($5::real, $4::bigint)
+ORDER BY t.rank DESC, t.comment_id DESC
+LIMIT $6
 `
 
-type RemoveCommentVoteParams struct {
-	CommentID int64 `json:"comment_id"`
-	UserID    int64 `json:"user_id"`
-}
-
-func (q *Queries) RemoveCommentVote(ctx context.Context, arg RemoveCommentVoteParams) (int64, error) {
-	result, err := q.db.Exec(ctx, removeCommentVote, arg.CommentID, arg.UserID)
-	if err != nil {
-		return 0, err
-	}
-	return result.RowsAffected(), nil
+type SearchCommentParams struct {
+	Query           string        `json:"query"`
+	UserID          int64         `json:"user_id"`
+	TopicID         int64         `json:"topic_id"`
+	CursorCommentID pgtype.Int8   `json:"cursor_comment_id"`
+	CursorRank      pgtype.Float4 `json:"cursor_rank"`
+	PageLimit       int32         `json:"page_limit"`
 }
 
-const removePostVote = `-- name: RemovePostVote :execrows
-DELETE FROM Post_Votes WHERE post_id = $1 AND user_id = $2
-`
-
-type RemovePostVoteParams struct {
-	PostID int64 `json:"post_id"`
-	UserID int64 `json:"user_id"`
+type SearchCommentRow struct {
+	CommentID       int64              `json:"comment_id"`
+	UserID          int64              `json:"user_id"`
+	Username        string             `json:"username"`
+	PostID          int64              `json:"post_id"`
+	ParentCommentID pgtype.Int8        `json:"parent_comment_id"`
+	Description     string             `json:"description"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+	EditCount       int32              `json:"edit_count"`
+	Likes           int64              `json:"likes"`
+	Dislikes        int64              `json:"dislikes"`
+	UserVote        interface{}        `json:"user_vote"`
+	Saved           bool               `json:"saved"`
+	Rank            float32            `json:"rank"`
+	Headline        string             `json:"headline"`
 }
 
-func (q *Queries) RemovePostVote(ctx context.Context, arg RemovePostVoteParams) (int64, error) {
-	result, err := q.db.Exec(ctx, removePostVote, arg.PostID, arg.UserID)
+func (q *Queries) SearchComment(ctx context.Context, arg SearchCommentParams) ([]SearchCommentRow, error) {
+	rows, err := q.db.Query(ctx, searchComment,
+		arg.Query,
+		arg.UserID,
+		arg.TopicID,
+		arg.CursorCommentID,
+		arg.CursorRank,
+		arg.PageLimit,
+	)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	return result.RowsAffected(), nil
+	defer rows.Close()
+	var items []SearchCommentRow
+	for rows.Next() {
+		var i SearchCommentRow
+		if err := rows.Scan(
+			&i.CommentID,
+			&i.UserID,
+			&i.Username,
+			&i.PostID,
+			&i.ParentCommentID,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.EditCount,
+			&i.Likes,
+			&i.Dislikes,
+			&i.UserVote,
+			&i.Saved,
+			&i.Rank,
+			&i.Headline,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const searchPost = `-- name: SearchPost :many
-SELECT DISTINCT p.post_id, p.topic_id, p.user_id, u.name AS username,
-p.title, p.description, p.created_at, p.updated_at,
-COUNT(v.vote) FILTER (WHERE v.vote = 1) OVER (PARTITION BY p.post_id)AS likes,
-COUNT(v.vote) FILTER (WHERE v.vote = -1) OVER (PARTITION BY p.post_id)AS dislikes,
-MAX(uv.vote) OVER (PARTITION BY p.post_id)AS user_vote
-FROM Posts p
-JOIN Users u ON u.user_id = p.user_id
-LEFT JOIN Post_Votes v ON p.post_id = v.post_id
-LEFT JOIN Post_Votes uv ON p.post_id = uv.post_id AND uv.user_id = $3
-WHERE p.topic_id = $1 AND (p.title ILIKE '%' || $2 ||'%' OR p.description ILIKE '%' || $2 ||'%')
-ORDER BY likes DESC, p.updated_at DESC
+SELECT t.post_id, t.topic_id, t.user_id, t.username, t.title, t.description, t.created_at,
+t.updated_at, t.edit_count, t.likes, t.dislikes, t.user_vote, t.saved, t.rank,
+ts_headline('english', t.description, websearch_to_tsquery('english', $1::text),
+    'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10') AS headline
+FROM (
+    SELECT p.post_id, p.topic_id, p.user_id, u.name AS username,
+    p.title, p.description, p.created_at, p.updated_at, p.edit_count,
+    COUNT(v.vote) FILTER (WHERE v.vote = 1) AS likes,
+    COUNT(v.vote) FILTER (WHERE v.vote = -1) AS dislikes,
+    MAX(uv.vote) AS user_vote,
+    EXISTS (SELECT 1 FROM Bookmarks s WHERE s.post_id = p.post_id AND s.user_id = $2) AS saved,
+    ts_rank(p.search_vector, websearch_to_tsquery('english', $1::text)) AS rank
+    FROM Posts p
+    JOIN Users u ON u.user_id = p.user_id
+    LEFT JOIN Post_Votes v ON p.post_id = v.post_id
+    LEFT JOIN Post_Votes uv ON p.post_id = uv.post_id AND uv.user_id = $2
+    WHERE p.topic_id = $3
+    AND p.search_vector @@ websearch_to_tsquery('english', $1::text)
+    AND p.deleted_at IS NULL
+    GROUP BY p.post_id, u.name
+) AS t
+WHERE $4::bigint IS NULL
+OR (t.rank, t.post_id) < ($5::real, $4::bigint)
+ORDER BY t.rank DESC, t.post_id DESC
+LIMIT $6
 `
 
 type SearchPostParams struct {
-	TopicID int64       `json:"topic_id"`
-	Column2 pgtype.Text `json:"column_2"`
-	UserID  int64       `json:"user_id"`
+	Query        string        `json:"query"`
+	UserID       int64         `json:"user_id"`
+	TopicID      int64         `json:"topic_id"`
+	CursorPostID pgtype.Int8   `json:"cursor_post_id"`
+	CursorRank   pgtype.Float4 `json:"cursor_rank"`
+	PageLimit    int32         `json:"page_limit"`
 }
 
 type SearchPostRow struct {
@@ -524,13 +3576,24 @@ type SearchPostRow struct {
 	Description string             `json:"description"`
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+	EditCount   int32              `json:"edit_count"`
 	Likes       int64              `json:"likes"`
 	Dislikes    int64              `json:"dislikes"`
 	UserVote    interface{}        `json:"user_vote"`
+	Saved       bool               `json:"saved"`
+	Rank        float32            `json:"rank"`
+	Headline    string             `json:"headline"`
 }
 
 func (q *Queries) SearchPost(ctx context.Context, arg SearchPostParams) ([]SearchPostRow, error) {
-	rows, err := q.db.Query(ctx, searchPost, arg.TopicID, arg.Column2, arg.UserID)
+	rows, err := q.db.Query(ctx, searchPost,
+		arg.Query,
+		arg.UserID,
+		arg.TopicID,
+		arg.CursorPostID,
+		arg.CursorRank,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -547,9 +3610,13 @@ func (q *Queries) SearchPost(ctx context.Context, arg SearchPostParams) ([]Searc
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.EditCount,
 			&i.Likes,
 			&i.Dislikes,
 			&i.UserVote,
+			&i.Saved,
+			&i.Rank,
+			&i.Headline,
 		); err != nil {
 			return nil, err
 		}
@@ -562,23 +3629,58 @@ func (q *Queries) SearchPost(ctx context.Context, arg SearchPostParams) ([]Searc
 }
 
 const searchTopic = `-- name: SearchTopic :many
-SELECT topic_id, user_id, title, created_at FROM Topics WHERE title ILIKE '%' || $1 ||'%' ORDER BY title
+SELECT t.topic_id, t.user_id, t.title, t.created_at, t.rank,
+ts_headline('english', t.title, websearch_to_tsquery('english', $1::text),
+    'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS headline
+FROM (
+    SELECT topic_id, user_id, title, created_at,
+    ts_rank(search_vector, websearch_to_tsquery('english', $1::text)) AS rank
+    FROM Topics
+    WHERE search_vector @@ websearch_to_tsquery('english', $1::text)
+) AS t
+WHERE $2::bigint IS NULL
+OR (t.rank, t.topic_id) < ($3::real, $2::bigint)
+ORDER BY t.rank DESC, t.topic_id DESC
+LIMIT $4
 `
 
-func (q *Queries) SearchTopic(ctx context.Context, dollar_1 pgtype.Text) ([]Topic, error) {
-	rows, err := q.db.Query(ctx, searchTopic, dollar_1)
+type SearchTopicParams struct {
+	Query         string        `json:"query"`
+	CursorTopicID pgtype.Int8   `json:"cursor_topic_id"`
+	CursorRank    pgtype.Float4 `json:"cursor_rank"`
+	PageLimit     int32         `json:"page_limit"`
+}
+
+type SearchTopicRow struct {
+	TopicID   int64              `json:"topic_id"`
+	UserID    int64              `json:"user_id"`
+	Title     string             `json:"title"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	Rank      float32            `json:"rank"`
+	Headline  string             `json:"headline"`
+}
+
+func (q *Queries) SearchTopic(ctx context.Context, arg SearchTopicParams) ([]SearchTopicRow, error) {
+	rows, err := q.db.Query(ctx, searchTopic,
+		arg.Query,
+		arg.CursorTopicID,
+		arg.CursorRank,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Topic
+	var items []SearchTopicRow
 	for rows.Next() {
-		var i Topic
+		var i SearchTopicRow
 		if err := rows.Scan(
 			&i.TopicID,
 			&i.UserID,
 			&i.Title,
 			&i.CreatedAt,
+			&i.Rank,
+			&i.Headline,
 		); err != nil {
 			return nil, err
 		}
@@ -590,25 +3692,184 @@ func (q *Queries) SearchTopic(ctx context.Context, dollar_1 pgtype.Text) ([]Topi
 	return items, nil
 }
 
+const subscribeTopic = `-- name: SubscribeTopic :exec
+INSERT INTO Topic_Subscriptions (user_id, topic_id) VALUES ($1, $2)
+ON CONFLICT (user_id, topic_id) DO NOTHING
+`
+
+type SubscribeTopicParams struct {
+	UserID  int64 `json:"user_id"`
+	TopicID int64 `json:"topic_id"`
+}
+
+func (q *Queries) SubscribeTopic(ctx context.Context, arg SubscribeTopicParams) error {
+	_, err := q.db.Exec(ctx, subscribeTopic, arg.UserID, arg.TopicID)
+	return err
+}
+
+const syncCommentMentions = `-- name: SyncCommentMentions :many
+WITH mentioned AS (
+    SELECT user_id FROM Users WHERE name = ANY($1::text[])
+), stale AS (
+    DELETE FROM Mentions m
+    WHERE m.comment_id = $2 AND m.user_id NOT IN (SELECT user_id FROM mentioned)
+)
+INSERT INTO Mentions (user_id, comment_id)
+SELECT user_id, $2 FROM mentioned
+ON CONFLICT (comment_id, user_id) DO NOTHING
+RETURNING user_id
+`
+
+type SyncCommentMentionsParams struct {
+	Names     []string    `json:"names"`
+	CommentID pgtype.Int8 `json:"comment_id"`
+}
+
+func (q *Queries) SyncCommentMentions(ctx context.Context, arg SyncCommentMentionsParams) ([]int64, error) {
+	rows, err := q.db.Query(ctx, syncCommentMentions, arg.Names, arg.CommentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var user_id int64
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const syncPostMentions = `-- name: SyncPostMentions :many
+WITH mentioned AS (
+    SELECT user_id FROM Users WHERE name = ANY($1::text[])
+), stale AS (
+    DELETE FROM Mentions m
+    WHERE m.post_id = $2 AND m.user_id NOT IN (SELECT user_id FROM mentioned)
+)
+INSERT INTO Mentions (user_id, post_id)
+SELECT user_id, $2 FROM mentioned
+ON CONFLICT (post_id, user_id) DO NOTHING
+RETURNING user_id
+`
+
+type SyncPostMentionsParams struct {
+	Names  []string    `json:"names"`
+	PostID pgtype.Int8 `json:"post_id"`
+}
+
+func (q *Queries) SyncPostMentions(ctx context.Context, arg SyncPostMentionsParams) ([]int64, error) {
+	rows, err := q.db.Query(ctx, syncPostMentions, arg.Names, arg.PostID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var user_id int64
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const takeRateLimitToken = `-- name: TakeRateLimitToken :one
+INSERT INTO Rate_Limits AS rl (bucket_key, tokens, updated_at)
+VALUES ($1, $2::float8 - 1, now())
+ON CONFLICT (bucket_key) DO UPDATE
+SET tokens = LEAST($2::float8, rl.tokens + EXTRACT(EPOCH FROM now() - rl.updated_at)::float8 / $3::float8) - 1,
+    updated_at = now()
+WHERE LEAST($2::float8, rl.tokens + EXTRACT(EPOCH FROM now() - rl.updated_at)::float8 / $3::float8) >= 1
+RETURNING tokens
+`
+
+type TakeRateLimitTokenParams struct {
+	BucketKey       string  `json:"bucket_key"`
+	Burst           float64 `json:"burst"`
+	IntervalSeconds float64 `json:"interval_seconds"`
+}
+
+func (q *Queries) TakeRateLimitToken(ctx context.Context, arg TakeRateLimitTokenParams) (float64, error) {
+	row := q.db.QueryRow(ctx, takeRateLimitToken, arg.BucketKey, arg.Burst, arg.IntervalSeconds)
+	var tokens float64
+	err := row.Scan(&tokens)
+	return tokens, err
+}
+
+const unsaveComment = `-- name: UnsaveComment :execrows
+DELETE FROM Bookmarks WHERE user_id = $1 AND comment_id = $2
+`
+
+type UnsaveCommentParams struct {
+	UserID    int64       `json:"user_id"`
+	CommentID pgtype.Int8 `json:"comment_id"`
+}
+
+func (q *Queries) UnsaveComment(ctx context.Context, arg UnsaveCommentParams) (int64, error) {
+	result, err := q.db.Exec(ctx, unsaveComment, arg.UserID, arg.CommentID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const unsavePost = `-- name: UnsavePost :execrows
+DELETE FROM Bookmarks WHERE user_id = $1 AND post_id = $2
+`
+
+type UnsavePostParams struct {
+	UserID int64       `json:"user_id"`
+	PostID pgtype.Int8 `json:"post_id"`
+}
+
+func (q *Queries) UnsavePost(ctx context.Context, arg UnsavePostParams) (int64, error) {
+	result, err := q.db.Exec(ctx, unsavePost, arg.UserID, arg.PostID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const unsubscribeTopic = `-- name: UnsubscribeTopic :execrows
+DELETE FROM Topic_Subscriptions WHERE user_id = $1 AND topic_id = $2
+`
+
+type UnsubscribeTopicParams struct {
+	UserID  int64 `json:"user_id"`
+	TopicID int64 `json:"topic_id"`
+}
+
+func (q *Queries) UnsubscribeTopic(ctx context.Context, arg UnsubscribeTopicParams) (int64, error) {
+	result, err := q.db.Exec(ctx, unsubscribeTopic, arg.UserID, arg.TopicID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateComment = `-- name: UpdateComment :one
-UPDATE Comments SET description = $4, updated_at = now()
-WHERE comment_id = $1 AND post_id = $2 AND user_id = $3 RETURNING comment_id, post_id, user_id, description, created_at, updated_at
+UPDATE Comments SET description = $3, updated_at = now(), edit_count = edit_count + 1
+WHERE comment_id = $1 AND post_id = $2 AND deleted_at IS NULL RETURNING comment_id, post_id, user_id, description, created_at, updated_at, parent_comment_id, deleted_at, search_vector, deleted_by, edit_count
 `
 
 type UpdateCommentParams struct {
 	CommentID   int64  `json:"comment_id"`
 	PostID      int64  `json:"post_id"`
-	UserID      int64  `json:"user_id"`
 	Description string `json:"description"`
 }
 
 func (q *Queries) UpdateComment(ctx context.Context, arg UpdateCommentParams) (Comment, error) {
-	row := q.db.QueryRow(ctx, updateComment,
-		arg.CommentID,
-		arg.PostID,
-		arg.UserID,
-		arg.Description,
-	)
+	row := q.db.QueryRow(ctx, updateComment, arg.CommentID, arg.PostID, arg.Description)
 	var i Comment
 	err := row.Scan(
 		&i.CommentID,
@@ -617,28 +3878,27 @@ func (q *Queries) UpdateComment(ctx context.Context, arg UpdateCommentParams) (C
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ParentCommentID,
+		&i.DeletedAt,
+		&i.SearchVector,
+		&i.DeletedBy,
+		&i.EditCount,
 	)
 	return i, err
 }
 
 const updatePost = `-- name: UpdatePost :one
-UPDATE Posts SET title = $3, description = $4, updated_at = now() WHERE post_id = $1 AND user_id = $2 RETURNING post_id, topic_id, user_id, title, description, created_at, updated_at
+UPDATE Posts SET title = $2, description = $3, updated_at = now(), edit_count = edit_count + 1 WHERE post_id = $1 AND deleted_at IS NULL RETURNING post_id, topic_id, user_id, title, description, created_at, updated_at, search_vector, deleted_at, deleted_by, edit_count
 `
 
 type UpdatePostParams struct {
 	PostID      int64  `json:"post_id"`
-	UserID      int64  `json:"user_id"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
 }
 
 func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) (Post, error) {
-	row := q.db.QueryRow(ctx, updatePost,
-		arg.PostID,
-		arg.UserID,
-		arg.Title,
-		arg.Description,
-	)
+	row := q.db.QueryRow(ctx, updatePost, arg.PostID, arg.Title, arg.Description)
 	var i Post
 	err := row.Scan(
 		&i.PostID,
@@ -648,12 +3908,16 @@ func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) (Post, e
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.DeletedBy,
+		&i.EditCount,
 	)
 	return i, err
 }
 
 const updatePostStatus = `-- name: UpdatePostStatus :exec
-UPDATE Posts SET updated_at = now() WHERE post_id = $1 RETURNING post_id, topic_id, user_id, title, description, created_at, updated_at
+UPDATE Posts SET updated_at = now() WHERE post_id = $1 RETURNING post_id, topic_id, user_id, title, description, created_at, updated_at, search_vector, deleted_at, deleted_by, edit_count
 `
 
 func (q *Queries) UpdatePostStatus(ctx context.Context, postID int64) error {
@@ -662,23 +3926,101 @@ func (q *Queries) UpdatePostStatus(ctx context.Context, postID int64) error {
 }
 
 const updateTopic = `-- name: UpdateTopic :one
-UPDATE Topics SET title = $3 WHERE topic_id = $1 AND user_id = $2 RETURNING topic_id, user_id, title, created_at
+UPDATE Topics SET title = $2 WHERE topic_id = $1 RETURNING topic_id, user_id, title, created_at, search_vector
 `
 
 type UpdateTopicParams struct {
 	TopicID int64  `json:"topic_id"`
-	UserID  int64  `json:"user_id"`
 	Title   string `json:"title"`
 }
 
 func (q *Queries) UpdateTopic(ctx context.Context, arg UpdateTopicParams) (Topic, error) {
-	row := q.db.QueryRow(ctx, updateTopic, arg.TopicID, arg.UserID, arg.Title)
+	row := q.db.QueryRow(ctx, updateTopic, arg.TopicID, arg.Title)
 	var i Topic
 	err := row.Scan(
 		&i.TopicID,
 		&i.UserID,
 		&i.Title,
 		&i.CreatedAt,
+		&i.SearchVector,
+	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :one
+UPDATE Users SET password_hash = $2, must_change_password = false WHERE user_id = $1 RETURNING user_id, name, password_hash, must_change_password, tokens_revoked_at, role
+`
+
+type UpdateUserPasswordParams struct {
+	UserID       int64       `json:"user_id"`
+	PasswordHash pgtype.Text `json:"password_hash"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserPassword, arg.UserID, arg.PasswordHash)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Name,
+		&i.PasswordHash,
+		&i.MustChangePassword,
+		&i.TokensRevokedAt,
+		&i.Role,
+	)
+	return i, err
+}
+
+const updateUserRole = `-- name: UpdateUserRole :one
+UPDATE Users SET role = $2 WHERE user_id = $1 RETURNING user_id, name, password_hash, must_change_password, tokens_revoked_at, role
+`
+
+type UpdateUserRoleParams struct {
+	UserID int64  `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+// Roles Queries
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserRole, arg.UserID, arg.Role)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Name,
+		&i.PasswordHash,
+		&i.MustChangePassword,
+		&i.TokensRevokedAt,
+		&i.Role,
 	)
 	return i, err
 }
+
+const upsertNotificationPreference = `-- name: UpsertNotificationPreference :exec
+INSERT INTO Notification_Preferences (user_id, type, enabled) VALUES ($1, $2, $3)
+ON CONFLICT (user_id, type) DO UPDATE SET enabled = EXCLUDED.enabled
+`
+
+type UpsertNotificationPreferenceParams struct {
+	UserID  int64  `json:"user_id"`
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+}
+
+func (q *Queries) UpsertNotificationPreference(ctx context.Context, arg UpsertNotificationPreferenceParams) error {
+	_, err := q.db.Exec(ctx, upsertNotificationPreference, arg.UserID, arg.Type, arg.Enabled)
+	return err
+}
+
+const upsertPollBallot = `-- name: UpsertPollBallot :exec
+INSERT INTO Poll_Ballots (post_id, user_id) VALUES ($1, $2)
+ON CONFLICT (post_id, user_id) DO UPDATE SET created_at = now()
+`
+
+type UpsertPollBallotParams struct {
+	PostID int64 `json:"post_id"`
+	UserID int64 `json:"user_id"`
+}
+
+func (q *Queries) UpsertPollBallot(ctx context.Context, arg UpsertPollBallotParams) error {
+	_, err := q.db.Exec(ctx, upsertPollBallot, arg.PostID, arg.UserID)
+	return err
+}
@@ -6,4 +6,11 @@ var (
 	ErrPostAlreadyExists = errors.New("post already exists")
 	ErrPostNotFound      = errors.New("post not found")
 	ErrVoteNotFound      = errors.New("vote not found")
+	ErrPermissionDenied  = errors.New("permission denied")
+	ErrRestoreExpired    = errors.New("post can no longer be restored")
+	ErrRevisionNotFound  = errors.New("revision not found")
+	ErrInvalidPoll       = errors.New("poll must close in the future")
+	ErrPollNotFound      = errors.New("poll not found")
+	ErrPollClosed        = errors.New("poll is closed")
+	ErrInvalidPollOption = errors.New("invalid poll option")
 )
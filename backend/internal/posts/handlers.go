@@ -6,28 +6,34 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-playground/validator/v10"
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
 	"github.com/haobuhaoo/gossip-with-go/internal/helper"
 	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
 	"github.com/haobuhaoo/gossip-with-go/internal/topics"
-	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const (
 	InvalidTopicIdMessage              = "Invalid topic id"
 	InvalidPostIdMessage               = "Invalid post id"
-	InvalidRequestBodyMessage          = "Required fields missing"
 	InvalidQueryMessage                = "Query string missing"
+	InvalidPageMessage                 = "Invalid limit or cursor"
+	InvalidSortMessage                 = "Invalid sort or time window"
+	InvalidVersionMessage              = "Invalid from or to version"
 	MissingUserIDMessage               = "Missing userID"
 	SuccessfulFindPostByTopicMessage   = "Successfully listed all posts"
+	SuccessfulFindFeedMessage          = "Successfully listed feed"
 	SuccessfulFindPostByIdMessage      = "Successfully find post"
 	SuccessfulCreatePostMessage        = "Successfully created post"
 	SuccessfulUpdatePostMessage        = "Successfully updated post"
 	SuccessfulDeletePostMessage        = "Successfully deleted post"
+	SuccessfulRestorePostMessage       = "Successfully restored post"
 	SuccessfulSearchPostByTopicMessage = "Successfully searched post"
 	SuccessfulLikePostMessage          = "Successfully liked post"
 	SuccessfulDislikePostMessage       = "Successfully disliked post"
 	SuccessfulRemovePostVoteMessage    = "Successfully removed vote"
+	SuccessfulListRevisionsMessage     = "Successfully listed revisions"
+	SuccessfulDiffRevisionsMessage     = "Successfully compared revisions"
+	SuccessfulVotePollMessage          = "Successfully voted on poll"
 )
 
 // handler handles the post related HTTP requests.
@@ -45,44 +51,125 @@ func NewHandler(service Service) *handler {
 }
 
 // FindPostsByTopic handles GET /api/posts/all/{topicId} requests.
-// It parses the topicId string, and passes it to the post service to return all posts for that topic
-// and serializes the result into a JSON HTTP response.
+// It parses the topicId string together with the sort, t, limit and cursor query strings, and
+// passes them to the post service to return a page of sorted posts for that topic. It then
+// serializes the result and its pagination metadata into a JSON HTTP response.
 func (h *handler) FindPostsByTopic(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "topicId")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		helper.WriteError(w, topics.InvalidTopicIdMessage, http.StatusBadRequest)
+		helper.WriteError(w, topics.InvalidTopicIdMessage, http.StatusBadRequest, api.CodeInvalidTopicID)
 		return
 	}
 
 	userId, ok := r.Context().Value("userID").(int64)
 	if !ok {
-		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest)
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	page, err := helper.ReadPage(r)
+	if err != nil {
+		helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+		return
+	}
+
+	sort, err := helper.ReadSort(r)
+	if err != nil {
+		helper.WriteError(w, InvalidSortMessage, http.StatusBadRequest, api.CodeInvalidSort)
 		return
 	}
 
 	arg := repo.FindPostsByTopicParams{
 		TopicID: id,
 		UserID:  userId,
+		Sort:    sort.Order,
+		Since:   sort.Since,
 	}
-	posts, err := h.service.FindPostsByTopic(r.Context(), arg)
+	posts, meta, err := h.service.FindPostsByTopic(r.Context(), arg, page)
 	if err != nil {
 		if err == topics.ErrTopicNotFound {
-			helper.WriteError(w, topics.InvalidTopicIdMessage, http.StatusBadRequest)
+			helper.WriteError(w, topics.InvalidTopicIdMessage, http.StatusBadRequest, api.CodeInvalidTopicID)
+			return
+		}
+
+		if err == helper.ErrInvalidCursor {
+			helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
 			return
 		}
 
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
 	jsonPost, err := json.Marshal(posts)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonMeta, err := json.Marshal(meta)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
-	response := helper.ParseResponseDataAndMessage(jsonPost, SuccessfulFindPostByTopicMessage)
+	response := helper.ParseResponseDataMetaAndMessage(jsonPost, jsonMeta, SuccessfulFindPostByTopicMessage)
+	helper.Write(w, response)
+}
+
+// FindFeed handles GET /api/feed requests.
+// It parses the sort, t, limit and cursor query strings, and passes them to the post service to
+// return a page of sorted posts across the topics that the user subscribed to. It then serializes
+// the result and its pagination metadata into a JSON HTTP response.
+func (h *handler) FindFeed(w http.ResponseWriter, r *http.Request) {
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	page, err := helper.ReadPage(r)
+	if err != nil {
+		helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+		return
+	}
+
+	sort, err := helper.ReadSort(r)
+	if err != nil {
+		helper.WriteError(w, InvalidSortMessage, http.StatusBadRequest, api.CodeInvalidSort)
+		return
+	}
+
+	arg := repo.FindFeedParams{
+		UserID: userId,
+		Sort:   sort.Order,
+		Since:  sort.Since,
+	}
+	posts, meta, err := h.service.FindFeed(r.Context(), arg, page)
+	if err != nil {
+		if err == helper.ErrInvalidCursor {
+			helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonPost, err := json.Marshal(posts)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonMeta, err := json.Marshal(meta)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataMetaAndMessage(jsonPost, jsonMeta, SuccessfulFindFeedMessage)
 	helper.Write(w, response)
 }
 
@@ -93,20 +180,20 @@ func (h *handler) FindPostByID(w http.ResponseWriter, r *http.Request) {
 	topicIdStr := chi.URLParam(r, "topicId")
 	topicId, err := strconv.ParseInt(topicIdStr, 10, 64)
 	if err != nil {
-		helper.WriteError(w, InvalidTopicIdMessage, http.StatusBadRequest)
+		helper.WriteError(w, InvalidTopicIdMessage, http.StatusBadRequest, api.CodeInvalidTopicID)
 		return
 	}
 
 	postIdStr := chi.URLParam(r, "postId")
 	postId, err := strconv.ParseInt(postIdStr, 10, 64)
 	if err != nil {
-		helper.WriteError(w, InvalidPostIdMessage, http.StatusBadRequest)
+		helper.WriteError(w, InvalidPostIdMessage, http.StatusBadRequest, api.CodeInvalidPostID)
 		return
 	}
 
 	userId, ok := r.Context().Value("userID").(int64)
 	if !ok {
-		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest)
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
 		return
 	}
 
@@ -118,17 +205,17 @@ func (h *handler) FindPostByID(w http.ResponseWriter, r *http.Request) {
 	post, err := h.service.FindPostByID(r.Context(), req)
 	if err != nil {
 		if err == ErrPostNotFound {
-			helper.WriteError(w, ErrPostNotFound.Error(), http.StatusNotFound)
+			helper.WriteError(w, ErrPostNotFound.Error(), http.StatusNotFound, api.CodePostNotFound)
 			return
 		}
 
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
 	jsonPost, err := json.Marshal(post)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
@@ -143,19 +230,19 @@ func (h *handler) CreatePost(w http.ResponseWriter, r *http.Request) {
 	var req CreatePostRequest
 	err := helper.Read(r, &req)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusBadRequest)
+		helper.WriteError(w, err.Error(), http.StatusBadRequest, api.CodeMalformedBody)
 		return
 	}
 
-	err = validator.New().Struct(req)
+	err = helper.Validate(req)
 	if err != nil {
-		helper.WriteError(w, InvalidRequestBodyMessage, http.StatusBadRequest)
+		helper.WriteValidationError(w, err)
 		return
 	}
 
 	userId, ok := r.Context().Value("userID").(int64)
 	if !ok {
-		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest)
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
 		return
 	}
 
@@ -165,20 +252,28 @@ func (h *handler) CreatePost(w http.ResponseWriter, r *http.Request) {
 		Title:       req.Title,
 		Description: req.Description,
 	}
-	post, err := h.service.CreatePost(r.Context(), newPost)
+	post, err := h.service.CreatePost(r.Context(), newPost, req.Poll)
 	if err != nil {
+		if suspended, ok := helper.AsSuspendedError(err); ok {
+			helper.WriteSuspendedError(w, suspended)
+			return
+		}
 		if err == ErrPostAlreadyExists {
-			helper.WriteError(w, ErrPostAlreadyExists.Error(), http.StatusConflict)
+			helper.WriteError(w, ErrPostAlreadyExists.Error(), http.StatusConflict, api.CodePostTitleTaken)
+			return
+		}
+		if err == ErrInvalidPoll {
+			helper.WriteError(w, ErrInvalidPoll.Error(), http.StatusBadRequest, api.CodeInvalidPoll)
 			return
 		}
 
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
 	jsonPost, err := json.Marshal(post)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
@@ -193,53 +288,60 @@ func (h *handler) UpdatePost(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		helper.WriteError(w, InvalidPostIdMessage, http.StatusBadRequest)
+		helper.WriteError(w, InvalidPostIdMessage, http.StatusBadRequest, api.CodeInvalidPostID)
 		return
 	}
 
 	var req UpdatePostRequest
 	err = helper.Read(r, &req)
 	if err != nil {
-		helper.WriteError(w, InvalidRequestBodyMessage, http.StatusBadRequest)
+		helper.WriteError(w, err.Error(), http.StatusBadRequest, api.CodeMalformedBody)
 		return
 	}
 
-	err = validator.New().Struct(req)
+	err = helper.Validate(req)
 	if err != nil {
-		helper.WriteError(w, InvalidRequestBodyMessage, http.StatusBadRequest)
+		helper.WriteValidationError(w, err)
 		return
 	}
 
 	userId, ok := r.Context().Value("userID").(int64)
 	if !ok {
-		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest)
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
 		return
 	}
 
 	newPost := repo.UpdatePostParams{
 		PostID:      id,
-		UserID:      userId,
 		Title:       req.Title,
 		Description: req.Description,
 	}
-	post, err := h.service.UpdatePost(r.Context(), newPost)
+	post, err := h.service.UpdatePost(r.Context(), userId, newPost)
 	if err != nil {
+		if suspended, ok := helper.AsSuspendedError(err); ok {
+			helper.WriteSuspendedError(w, suspended)
+			return
+		}
 		if err == ErrPostNotFound {
-			helper.WriteError(w, ErrPostNotFound.Error(), http.StatusNotFound)
+			helper.WriteError(w, ErrPostNotFound.Error(), http.StatusNotFound, api.CodePostNotFound)
+			return
+		}
+		if err == ErrPermissionDenied {
+			helper.WriteError(w, ErrPermissionDenied.Error(), http.StatusForbidden, api.CodePermissionDenied)
 			return
 		}
 		if err == ErrPostAlreadyExists {
-			helper.WriteError(w, ErrPostAlreadyExists.Error(), http.StatusConflict)
+			helper.WriteError(w, ErrPostAlreadyExists.Error(), http.StatusConflict, api.CodePostTitleTaken)
 			return
 		}
 
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
 	jsonPost, err := json.Marshal(post)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
@@ -254,28 +356,28 @@ func (h *handler) DeletePost(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		helper.WriteError(w, InvalidPostIdMessage, http.StatusBadRequest)
+		helper.WriteError(w, InvalidPostIdMessage, http.StatusBadRequest, api.CodeInvalidPostID)
 		return
 	}
 
 	userId, ok := r.Context().Value("userID").(int64)
 	if !ok {
-		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest)
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
 		return
 	}
 
-	arg := repo.DeletePostParams{
-		PostID: id,
-		UserID: userId,
-	}
-	err = h.service.DeletePost(r.Context(), arg)
+	err = h.service.DeletePost(r.Context(), userId, id)
 	if err != nil {
 		if err == ErrPostNotFound {
-			helper.WriteError(w, ErrPostNotFound.Error(), http.StatusNotFound)
+			helper.WriteError(w, ErrPostNotFound.Error(), http.StatusNotFound, api.CodePostNotFound)
+			return
+		}
+		if err == ErrPermissionDenied {
+			helper.WriteError(w, ErrPermissionDenied.Error(), http.StatusForbidden, api.CodePermissionDenied)
 			return
 		}
 
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
@@ -283,51 +385,199 @@ func (h *handler) DeletePost(w http.ResponseWriter, r *http.Request) {
 	helper.Write(w, response)
 }
 
+// RestorePost handles POST /api/posts/{id}/restore requests.
+// It parses the id string, and passes it to the post service to restore the deleted post. It then
+// serializes the result into a JSON HTTP response.
+func (h *handler) RestorePost(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidPostIdMessage, http.StatusBadRequest, api.CodeInvalidPostID)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	post, err := h.service.RestorePost(r.Context(), userId, id)
+	if err != nil {
+		if suspended, ok := helper.AsSuspendedError(err); ok {
+			helper.WriteSuspendedError(w, suspended)
+			return
+		}
+		if err == ErrPostNotFound {
+			helper.WriteError(w, ErrPostNotFound.Error(), http.StatusNotFound, api.CodePostNotFound)
+			return
+		}
+		if err == ErrPermissionDenied {
+			helper.WriteError(w, ErrPermissionDenied.Error(), http.StatusForbidden, api.CodePermissionDenied)
+			return
+		}
+		if err == ErrRestoreExpired {
+			helper.WriteError(w, ErrRestoreExpired.Error(), http.StatusGone, api.CodeRestoreExpired)
+			return
+		}
+		if err == ErrPostAlreadyExists {
+			helper.WriteError(w, ErrPostAlreadyExists.Error(), http.StatusConflict, api.CodePostTitleTaken)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonPost, err := json.Marshal(post)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonPost, SuccessfulRestorePostMessage)
+	helper.Write(w, response)
+}
+
+// ListRevisions handles GET /api/posts/{id}/revisions requests.
+// It parses the id string, and passes it to the post service to return all versions of the post,
+// which then serializes the result into a JSON HTTP response.
+func (h *handler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidPostIdMessage, http.StatusBadRequest, api.CodeInvalidPostID)
+		return
+	}
+
+	revisions, err := h.service.ListRevisions(r.Context(), id)
+	if err != nil {
+		if err == ErrPostNotFound {
+			helper.WriteError(w, ErrPostNotFound.Error(), http.StatusNotFound, api.CodePostNotFound)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonRevisions, err := json.Marshal(revisions)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonRevisions, SuccessfulListRevisionsMessage)
+	helper.Write(w, response)
+}
+
+// DiffRevisions handles GET /api/posts/{id}/revisions/diff requests.
+// It parses the id string together with the from and to query strings, and passes them to the post
+// service to compare the two versions of the post, which then serializes the result into a JSON
+// HTTP response.
+func (h *handler) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidPostIdMessage, http.StatusBadRequest, api.CodeInvalidPostID)
+		return
+	}
+
+	from, to, err := helper.ReadVersions(r)
+	if err != nil {
+		helper.WriteError(w, InvalidVersionMessage, http.StatusBadRequest, api.CodeInvalidVersion)
+		return
+	}
+
+	diff, err := h.service.DiffRevisions(r.Context(), id, from, to)
+	if err != nil {
+		if err == ErrPostNotFound {
+			helper.WriteError(w, ErrPostNotFound.Error(), http.StatusNotFound, api.CodePostNotFound)
+			return
+		}
+		if err == ErrRevisionNotFound {
+			helper.WriteError(w, ErrRevisionNotFound.Error(), http.StatusNotFound, api.CodeRevisionNotFound)
+			return
+		}
+		if err == helper.ErrDiffTooLarge {
+			helper.WriteError(w, helper.ErrDiffTooLarge.Error(), http.StatusUnprocessableEntity, api.CodeDiffTooLarge)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonDiff, err := json.Marshal(diff)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonDiff, SuccessfulDiffRevisionsMessage)
+	helper.Write(w, response)
+}
+
 // SearchPost handles GET /api/posts/{topicId}/search requests.
-// It parses the topicId and query string, and passes it to the post service to search for all
-// post titles and descriptions under the specified topic that contains the query string
-// (case-insensitive), which then serializes the result into a JSON HTTP response.
+// It parses the topicId, query, limit and cursor strings, and passes them to the post service to
+// run a full-text search over the post titles and descriptions under the specified topic, which
+// then serializes the page of ranked results and its pagination metadata into a JSON HTTP
+// response.
 func (h *handler) SearchPost(w http.ResponseWriter, r *http.Request) {
 	topicIdStr := chi.URLParam(r, "topicId")
 	topicId, err := strconv.ParseInt(topicIdStr, 10, 64)
 	if err != nil {
-		helper.WriteError(w, InvalidTopicIdMessage, http.StatusBadRequest)
+		helper.WriteError(w, InvalidTopicIdMessage, http.StatusBadRequest, api.CodeInvalidTopicID)
 		return
 	}
 
 	rawQuery := r.URL.Query().Get("q")
 	if rawQuery == "" {
-		helper.WriteError(w, InvalidQueryMessage, http.StatusBadRequest)
+		helper.WriteError(w, InvalidQueryMessage, http.StatusBadRequest, api.CodeInvalidQuery)
 		return
 	}
 
 	userId, ok := r.Context().Value("userID").(int64)
 	if !ok {
-		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest)
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	page, err := helper.ReadPage(r)
+	if err != nil {
+		helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
 		return
 	}
 
 	arg := repo.SearchPostParams{
 		TopicID: topicId,
-		Column2: pgtype.Text{
-			String: rawQuery,
-			Valid:  true,
-		},
-		UserID: userId,
+		Query:   rawQuery,
+		UserID:  userId,
 	}
-	posts, err := h.service.SearchPost(r.Context(), arg)
+	posts, meta, err := h.service.SearchPost(r.Context(), arg, page)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		if err == helper.ErrInvalidCursor {
+			helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
 	jsonPost, err := json.Marshal(posts)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
-	response := helper.ParseResponseDataAndMessage(jsonPost, SuccessfulSearchPostByTopicMessage)
+	jsonMeta, err := json.Marshal(meta)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataMetaAndMessage(jsonPost, jsonMeta, SuccessfulSearchPostByTopicMessage)
 	helper.Write(w, response)
 }
 
@@ -338,13 +588,13 @@ func (h *handler) LikesPost(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		helper.WriteError(w, InvalidPostIdMessage, http.StatusBadRequest)
+		helper.WriteError(w, InvalidPostIdMessage, http.StatusBadRequest, api.CodeInvalidPostID)
 		return
 	}
 
 	userId, ok := r.Context().Value("userID").(int64)
 	if !ok {
-		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest)
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
 		return
 	}
 
@@ -354,7 +604,16 @@ func (h *handler) LikesPost(w http.ResponseWriter, r *http.Request) {
 	}
 	err = h.service.LikesPost(r.Context(), arg)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		if suspended, ok := helper.AsSuspendedError(err); ok {
+			helper.WriteSuspendedError(w, suspended)
+			return
+		}
+		if err == ErrPostNotFound {
+			helper.WriteError(w, ErrPostNotFound.Error(), http.StatusNotFound, api.CodePostNotFound)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
@@ -362,6 +621,74 @@ func (h *handler) LikesPost(w http.ResponseWriter, r *http.Request) {
 	helper.Write(w, response)
 }
 
+// VotePoll handles POST /api/posts/{id}/poll/vote requests.
+// It parses the id string, reads and validates the request body, and passes it to the post service
+// to cast the ballot of the user on the poll of that post, which then serializes the new tallies
+// into a JSON HTTP response.
+func (h *handler) VotePoll(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidPostIdMessage, http.StatusBadRequest, api.CodeInvalidPostID)
+		return
+	}
+
+	var req VotePollRequest
+	err = helper.Read(r, &req)
+	if err != nil {
+		helper.WriteError(w, err.Error(), http.StatusBadRequest, api.CodeMalformedBody)
+		return
+	}
+
+	err = helper.Validate(req)
+	if err != nil {
+		helper.WriteValidationError(w, err)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	poll, err := h.service.VotePoll(r.Context(), userId, id, req.OptionIDs)
+	if err != nil {
+		if suspended, ok := helper.AsSuspendedError(err); ok {
+			helper.WriteSuspendedError(w, suspended)
+			return
+		}
+		if err == ErrPostNotFound {
+			helper.WriteError(w, ErrPostNotFound.Error(), http.StatusNotFound, api.CodePostNotFound)
+			return
+		}
+		if err == ErrPollNotFound {
+			helper.WriteError(w, ErrPollNotFound.Error(), http.StatusNotFound, api.CodePollNotFound)
+			return
+		}
+		if err == ErrPollClosed {
+			helper.WriteError(w, ErrPollClosed.Error(), http.StatusConflict, api.CodePollClosed)
+			return
+		}
+		if err == ErrInvalidPollOption {
+			helper.WriteError(w, ErrInvalidPollOption.Error(), http.StatusBadRequest, api.CodeInvalidPollOption)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonPoll, err := json.Marshal(poll)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonPoll, SuccessfulVotePollMessage)
+	helper.Write(w, response)
+}
+
 // DislikesPost handles POST /api/posts/{id}/dislikes requests.
 // It parses the id string and passes it to the post service to increment a dislike count for that
 // specified post, which then serializes the result into a JSON HTTP response.
@@ -369,13 +696,13 @@ func (h *handler) DislikesPost(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		helper.WriteError(w, InvalidPostIdMessage, http.StatusBadRequest)
+		helper.WriteError(w, InvalidPostIdMessage, http.StatusBadRequest, api.CodeInvalidPostID)
 		return
 	}
 
 	userId, ok := r.Context().Value("userID").(int64)
 	if !ok {
-		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest)
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
 		return
 	}
 
@@ -385,7 +712,16 @@ func (h *handler) DislikesPost(w http.ResponseWriter, r *http.Request) {
 	}
 	err = h.service.DislikesPost(r.Context(), arg)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		if suspended, ok := helper.AsSuspendedError(err); ok {
+			helper.WriteSuspendedError(w, suspended)
+			return
+		}
+		if err == ErrPostNotFound {
+			helper.WriteError(w, ErrPostNotFound.Error(), http.StatusNotFound, api.CodePostNotFound)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
@@ -400,13 +736,13 @@ func (h *handler) RemovePostVote(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		helper.WriteError(w, InvalidPostIdMessage, http.StatusBadRequest)
+		helper.WriteError(w, InvalidPostIdMessage, http.StatusBadRequest, api.CodeInvalidPostID)
 		return
 	}
 
 	userId, ok := r.Context().Value("userID").(int64)
 	if !ok {
-		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest)
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
 		return
 	}
 
@@ -416,12 +752,16 @@ func (h *handler) RemovePostVote(w http.ResponseWriter, r *http.Request) {
 	}
 	err = h.service.RemovePostVote(r.Context(), arg)
 	if err != nil {
+		if suspended, ok := helper.AsSuspendedError(err); ok {
+			helper.WriteSuspendedError(w, suspended)
+			return
+		}
 		if err == ErrVoteNotFound {
-			helper.WriteError(w, ErrVoteNotFound.Error(), http.StatusNotFound)
+			helper.WriteError(w, ErrVoteNotFound.Error(), http.StatusNotFound, api.CodeVoteNotFound)
 			return
 		}
 
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
@@ -2,15 +2,21 @@ package posts
 
 import "github.com/go-chi/chi/v5"
 
-// Routes group all post related HTTP endpoints together, with the base prefix path /posts.
+// Routes group all post related HTTP endpoints together, with the base prefix path /posts. The
+// feed of posts across the subscribed topics of the user is served from /feed.
 // It connects the URLS to their respective handler methods.
 func Routes(router chi.Router, h *handler) {
+	router.Get("/feed", h.FindFeed)
 	router.Route("/posts", func(r chi.Router) {
 		r.Get("/all/{topicId}", h.FindPostsByTopic)
 		r.Get("/{topicId}/search", h.SearchPost)
+		r.Get("/{id}/revisions", h.ListRevisions)
+		r.Get("/{id}/revisions/diff", h.DiffRevisions)
 		r.Get("/{topicId}/{postId}", h.FindPostByID)
 		r.Post("/{id}/likes", h.LikesPost)
 		r.Post("/{id}/dislikes", h.DislikesPost)
+		r.Post("/{id}/restore", h.RestorePost)
+		r.Post("/{id}/poll/vote", h.VotePoll)
 		r.Post("/", h.CreatePost)
 		r.Put("/{id}", h.UpdatePost)
 		r.Delete("/{id}/remove", h.RemovePostVote)
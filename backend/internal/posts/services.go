@@ -2,58 +2,132 @@ package posts
 
 import (
 	"context"
+	"log"
+	"slices"
+	"time"
 
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/audit"
 	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	"github.com/haobuhaoo/gossip-with-go/internal/mentions"
+	"github.com/haobuhaoo/gossip-with-go/internal/notifications"
 	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+	"github.com/haobuhaoo/gossip-with-go/internal/roles"
+	"github.com/haobuhaoo/gossip-with-go/internal/stream"
+	"github.com/haobuhaoo/gossip-with-go/internal/suspensions"
 	"github.com/haobuhaoo/gossip-with-go/internal/topics"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // svc implements the Service interface.
-// It depends on the sql generated Queries type to interact with the PostgreSQL database.
+// It depends on the sql generated Queries type and the pgx connection pool to interact with the
+// PostgreSQL database, on the role service to check permissions, on the suspension service to
+// refuse suspended users, on the notification service to notify authors, on the mention service to
+// store mentioned users, on the stream service to push changes to clients, and on the audit log
+// service to record moderator actions.
 type svc struct {
-	repo *repo.Queries
+	repo          *repo.Queries
+	db            *pgxpool.Pool
+	roles         roles.Service
+	suspensions   suspensions.Service
+	notifications notifications.Service
+	mentions      mentions.Service
+	stream        stream.Service
+	audit         audit.Service
 }
 
 // NewService creates a new post service.
-func NewService(repo *repo.Queries) Service {
+func NewService(repo *repo.Queries, db *pgxpool.Pool, roles roles.Service, suspensions suspensions.Service, notifications notifications.Service, mentions mentions.Service, stream stream.Service, audit audit.Service) Service {
 	return &svc{
-		repo: repo,
+		repo:          repo,
+		db:            db,
+		roles:         roles,
+		suspensions:   suspensions,
+		notifications: notifications,
+		mentions:      mentions,
+		stream:        stream,
+		audit:         audit,
 	}
 }
 
-// FindPostsByTopic returns all posts of the given topic id from the database.
-func (s *svc) FindPostsByTopic(ctx context.Context, arg repo.FindPostsByTopicParams) ([]Post, error) {
+// FindPostsByTopic returns a page of posts of the given topic id in the sort order of arg from the
+// database, together with the pagination metadata.
+func (s *svc) FindPostsByTopic(ctx context.Context, arg repo.FindPostsByTopicParams, page helper.Page) ([]Post, api.PageMeta, error) {
 	_, err := s.repo.FindTopicByID(ctx, arg.TopicID)
 	if err != nil {
-		return []Post{}, topics.ErrTopicNotFound
+		return []Post{}, api.PageMeta{}, topics.ErrTopicNotFound
 	}
 
+	arg.CursorSortKey, arg.CursorUpdatedAt, arg.CursorPostID, err = decodePostCursor(page.Cursor, arg.Sort)
+	if err != nil {
+		return []Post{}, api.PageMeta{}, err
+	}
+	arg.PageLimit = page.Limit + 1
+
 	rows, err := s.repo.FindPostsByTopic(ctx, arg)
 	if err != nil {
-		return []Post{}, err
+		return []Post{}, api.PageMeta{}, err
 	}
 
-	posts := make([]Post, 0, len(rows))
+	countArg := repo.CountPostsByTopicParams{
+		TopicID: arg.TopicID,
+		Since:   arg.Since,
+	}
+	total, err := s.repo.CountPostsByTopic(ctx, countArg)
+	if err != nil {
+		return []Post{}, api.PageMeta{}, err
+	}
+
+	posts, meta := paginatePosts(rows, arg.Sort, page, total)
+	err = s.attachMentions(ctx, posts)
+	if err != nil {
+		return []Post{}, api.PageMeta{}, err
+	}
+	return posts, meta, nil
+}
+
+// FindFeed returns a page of posts across the topics that the user subscribed to in the sort order
+// of arg from the database, together with the pagination metadata. Users without subscriptions get
+// the posts of all topics instead.
+func (s *svc) FindFeed(ctx context.Context, arg repo.FindFeedParams, page helper.Page) ([]Post, api.PageMeta, error) {
+	var err error
+	arg.CursorSortKey, arg.CursorUpdatedAt, arg.CursorPostID, err = decodePostCursor(page.Cursor, arg.Sort)
+	if err != nil {
+		return []Post{}, api.PageMeta{}, err
+	}
+	arg.PageLimit = page.Limit + 1
+
+	rows, err := s.repo.FindFeed(ctx, arg)
+	if err != nil {
+		return []Post{}, api.PageMeta{}, err
+	}
+
+	countArg := repo.CountFeedParams{
+		UserID: arg.UserID,
+		Since:  arg.Since,
+	}
+	total, err := s.repo.CountFeed(ctx, countArg)
+	if err != nil {
+		return []Post{}, api.PageMeta{}, err
+	}
+
+	topicRows := make([]repo.FindPostsByTopicRow, 0, len(rows))
 	for _, row := range rows {
-		posts = append(posts, Post{
-			PostID:      row.PostID,
-			TopicID:     row.TopicID,
-			UserID:      row.UserID,
-			Username:    row.Username,
-			Title:       row.Title,
-			Description: row.Description,
-			Likes:       row.Likes,
-			Dislikes:    row.Dislikes,
-			UserVote:    row.UserVote,
-			CreatedAt:   row.CreatedAt.Time,
-			UpdatedAt:   row.UpdatedAt.Time,
-		})
+		topicRows = append(topicRows, repo.FindPostsByTopicRow(row))
 	}
-	return posts, nil
+
+	posts, meta := paginatePosts(topicRows, arg.Sort, page, total)
+	err = s.attachMentions(ctx, posts)
+	if err != nil {
+		return []Post{}, api.PageMeta{}, err
+	}
+	return posts, meta, nil
 }
 
-// FindPostByID returns a specific post identified by id from the database.
+// FindPostByID returns a specific post identified by id from the database, together with its
+// mentioned users and its poll if it has one.
 func (s *svc) FindPostByID(ctx context.Context, arg repo.FindPostByIDParams) (Post, error) {
 	rows, err := s.repo.FindPostByID(ctx, arg)
 	if err != nil {
@@ -73,15 +147,53 @@ func (s *svc) FindPostByID(ctx context.Context, arg repo.FindPostByIDParams) (Po
 		Likes:       rows.Likes,
 		Dislikes:    rows.Dislikes,
 		UserVote:    rows.UserVote,
+		Saved:       rows.Saved,
+		Edited:      rows.EditCount > 0,
+		EditCount:   rows.EditCount,
+		Mentions:    []mentions.User{},
 		CreatedAt:   rows.CreatedAt.Time,
 		UpdatedAt:   rows.UpdatedAt.Time,
 	}
+
+	withMentions := []Post{posts}
+	err = s.attachMentions(ctx, withMentions)
+	if err != nil {
+		return Post{}, err
+	}
+	posts = withMentions[0]
+
+	poll, err := s.findPoll(ctx, rows.PostID, arg.UserID)
+	if err != nil && err != ErrPollNotFound {
+		return Post{}, err
+	}
+	if err == nil {
+		posts.Poll = &poll
+	}
 	return posts, nil
 }
 
-// CreatePost creates and returns a new post with the given arg params.
-func (s *svc) CreatePost(ctx context.Context, arg repo.CreatePostParams) (repo.Post, error) {
-	post, err := s.repo.CreatePost(ctx, arg)
+// CreatePost creates and returns a new post with the given arg params, stores the users mentioned
+// in it, and pushes it to the clients that follow its topic. If poll is given, the poll and its
+// options are created in the same transaction as the post. Users who are suspended from the topic
+// cannot create posts.
+func (s *svc) CreatePost(ctx context.Context, arg repo.CreatePostParams, poll *CreatePollRequest) (repo.Post, error) {
+	err := s.suspensions.CheckTopic(ctx, arg.UserID, arg.TopicID)
+	if err != nil {
+		return repo.Post{}, err
+	}
+
+	if poll != nil && poll.ClosesAt != nil && !poll.ClosesAt.After(time.Now()) {
+		return repo.Post{}, ErrInvalidPoll
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.Post{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.repo.WithTx(tx)
+	post, err := qtx.CreatePost(ctx, arg)
 	if err != nil {
 		if helper.IsUniqueViolation(err) {
 			return repo.Post{}, ErrPostAlreadyExists
@@ -89,12 +201,90 @@ func (s *svc) CreatePost(ctx context.Context, arg repo.CreatePostParams) (repo.P
 		return repo.Post{}, err
 	}
 
+	if poll != nil {
+		pollArg := repo.CreatePollParams{
+			PostID:         post.PostID,
+			Question:       poll.Question,
+			MultipleChoice: poll.MultipleChoice,
+		}
+		if poll.ClosesAt != nil {
+			pollArg.ClosesAt = pgtype.Timestamptz{Time: *poll.ClosesAt, Valid: true}
+		}
+		err = qtx.CreatePoll(ctx, pollArg)
+		if err != nil {
+			return repo.Post{}, err
+		}
+
+		optionsArg := repo.CreatePollOptionsParams{
+			PostID: post.PostID,
+			Labels: poll.Options,
+		}
+		err = qtx.CreatePollOptions(ctx, optionsArg)
+		if err != nil {
+			return repo.Post{}, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return repo.Post{}, err
+	}
+
+	err = s.mentions.SyncPost(ctx, post)
+	if err != nil {
+		log.Printf("failed to sync mentions of post %d: %v", post.PostID, err)
+	}
+
+	s.publishPost(ctx, stream.TypePostCreated, post)
 	return post, nil
 }
 
-// UpdatePost updates an existing post with the given arg params and returns it.
-func (s *svc) UpdatePost(ctx context.Context, arg repo.UpdatePostParams) (repo.Post, error) {
-	post, err := s.repo.UpdatePost(ctx, arg)
+// UpdatePost updates an existing post with the given arg params and returns it, stores the users
+// mentioned in it, and pushes the change to the clients. The prior version of the post is stored as
+// a revision in the same transaction. Users who are suspended from the topic cannot update posts.
+// Only the author of the post or a user who can moderate its topic may update it. Updates by
+// moderators are recorded in the audit log.
+func (s *svc) UpdatePost(ctx context.Context, userId int64, arg repo.UpdatePostParams) (repo.Post, error) {
+	err := s.checkSuspended(ctx, userId, arg.PostID)
+	if err != nil {
+		return repo.Post{}, err
+	}
+
+	author, err := s.checkPermission(ctx, userId, arg.PostID)
+	if err != nil {
+		return repo.Post{}, err
+	}
+
+	moderated := author.UserID != userId
+	var before repo.Post
+	if moderated {
+		before, err = s.repo.FindPostSnapshot(ctx, arg.PostID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return repo.Post{}, ErrPostNotFound
+			}
+			return repo.Post{}, err
+		}
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.Post{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.repo.WithTx(tx)
+
+	revisionArg := repo.CreatePostRevisionParams{
+		ReplacedBy: userId,
+		PostID:     arg.PostID,
+	}
+	err = qtx.CreatePostRevision(ctx, revisionArg)
+	if err != nil {
+		return repo.Post{}, err
+	}
+
+	post, err := qtx.UpdatePost(ctx, arg)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return repo.Post{}, ErrPostNotFound
@@ -105,13 +295,70 @@ func (s *svc) UpdatePost(ctx context.Context, arg repo.UpdatePostParams) (repo.P
 		return repo.Post{}, err
 	}
 
+	if moderated {
+		err = s.audit.Record(ctx, qtx, audit.Entry{
+			ActorID:    userId,
+			Action:     audit.ActionPostUpdate,
+			TargetType: audit.TargetPost,
+			TargetID:   post.PostID,
+			TopicID:    post.TopicID,
+			Before:     before,
+			After:      post,
+		})
+		if err != nil {
+			return repo.Post{}, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return repo.Post{}, err
+	}
+
+	err = s.mentions.SyncPost(ctx, post)
+	if err != nil {
+		log.Printf("failed to sync mentions of post %d: %v", post.PostID, err)
+	}
+
+	s.publishPost(ctx, stream.TypePostUpdated, post)
 	return post, nil
 }
 
-// DeletePost deletes the post given by the id from the database.
-// It deletes all comments under that post too.
-func (s *svc) DeletePost(ctx context.Context, arg repo.DeletePostParams) error {
-	delRows, err := s.repo.DeletePost(ctx, arg)
+// DeletePost soft-deletes the post given by the id, which hides it and all comments under it.
+// The post can be restored within the restore window, and is removed from the database by the
+// purge job once the retention period has passed. Only the author of the post or a user who can
+// moderate its topic may delete it. Deletions by moderators are recorded in the audit log.
+func (s *svc) DeletePost(ctx context.Context, userId int64, postId int64) error {
+	author, err := s.checkPermission(ctx, userId, postId)
+	if err != nil {
+		return err
+	}
+
+	moderated := author.UserID != userId
+	var before repo.Post
+	if moderated {
+		before, err = s.repo.FindPostSnapshot(ctx, postId)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrPostNotFound
+			}
+			return err
+		}
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.repo.WithTx(tx)
+
+	arg := repo.DeletePostParams{
+		PostID:    postId,
+		DeletedBy: pgtype.Int8{Int64: userId, Valid: true},
+	}
+	delRows, err := qtx.DeletePost(ctx, arg)
 	if err != nil {
 		return err
 	}
@@ -120,15 +367,121 @@ func (s *svc) DeletePost(ctx context.Context, arg repo.DeletePostParams) error {
 		return ErrPostNotFound
 	}
 
-	return nil
+	if moderated {
+		err = s.audit.Record(ctx, qtx, audit.Entry{
+			ActorID:    userId,
+			Action:     audit.ActionPostDelete,
+			TargetType: audit.TargetPost,
+			TargetID:   postId,
+			TopicID:    author.TopicID,
+			Before:     before,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RestorePost restores the deleted post given by the id and returns it, together with the comments
+// under it. Authors may restore the posts they deleted themselves, and users who can moderate the
+// topic may restore any post of it, as long as the restore window has not passed. Users who are
+// suspended from the topic cannot restore posts. Restorations by moderators are recorded in the
+// audit log.
+func (s *svc) RestorePost(ctx context.Context, userId int64, postId int64) (repo.Post, error) {
+	deleted, err := s.repo.FindDeletedPost(ctx, postId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return repo.Post{}, ErrPostNotFound
+		}
+		return repo.Post{}, err
+	}
+
+	err = s.suspensions.CheckTopic(ctx, userId, deleted.TopicID)
+	if err != nil {
+		return repo.Post{}, err
+	}
+
+	moderated := deleted.UserID != userId || deleted.DeletedBy.Int64 != userId
+	if moderated {
+		allowed, err := s.roles.CanModerateTopic(ctx, userId, deleted.TopicID)
+		if err != nil {
+			return repo.Post{}, err
+		}
+		if !allowed {
+			return repo.Post{}, ErrPermissionDenied
+		}
+	}
+
+	if !helper.CanRestore(deleted.DeletedAt.Time) {
+		return repo.Post{}, ErrRestoreExpired
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.Post{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.repo.WithTx(tx)
+
+	post, err := qtx.RestorePost(ctx, postId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return repo.Post{}, ErrPostNotFound
+		}
+		if helper.IsUniqueViolation(err) {
+			return repo.Post{}, ErrPostAlreadyExists
+		}
+		return repo.Post{}, err
+	}
+
+	if moderated {
+		err = s.audit.Record(ctx, qtx, audit.Entry{
+			ActorID:    userId,
+			Action:     audit.ActionPostRestore,
+			TargetType: audit.TargetPost,
+			TargetID:   post.PostID,
+			TopicID:    post.TopicID,
+			After:      post,
+		})
+		if err != nil {
+			return repo.Post{}, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return repo.Post{}, err
+	}
+
+	return post, nil
 }
 
-// SearchPost searches all post titles and descriptions under the specific topic that contains the
-// search query (case-insensitive) and returns all matched posts.
-func (s *svc) SearchPost(ctx context.Context, arg repo.SearchPostParams) ([]Post, error) {
+// SearchPost runs a full-text search over the post titles and descriptions under the specific
+// topic, with matches in the title ranked above matches in the description. It returns a page of
+// matched posts ordered by relevance, together with the pagination metadata.
+func (s *svc) SearchPost(ctx context.Context, arg repo.SearchPostParams, page helper.Page) ([]Post, api.PageMeta, error) {
+	var err error
+	arg.CursorRank, arg.CursorPostID, err = helper.DecodeSearchCursor(page.Cursor)
+	if err != nil {
+		return []Post{}, api.PageMeta{}, err
+	}
+	arg.PageLimit = page.Limit + 1
+
 	rows, err := s.repo.SearchPost(ctx, arg)
 	if err != nil {
-		return []Post{}, err
+		return []Post{}, api.PageMeta{}, err
+	}
+
+	countArg := repo.CountSearchPostParams{
+		TopicID: arg.TopicID,
+		Query:   arg.Query,
+	}
+	total, err := s.repo.CountSearchPost(ctx, countArg)
+	if err != nil {
+		return []Post{}, api.PageMeta{}, err
 	}
 
 	posts := make([]Post, 0, len(rows))
@@ -143,35 +496,83 @@ func (s *svc) SearchPost(ctx context.Context, arg repo.SearchPostParams) ([]Post
 			Likes:       row.Likes,
 			Dislikes:    row.Dislikes,
 			UserVote:    row.UserVote,
+			Saved:       row.Saved,
+			Edited:      row.EditCount > 0,
+			EditCount:   row.EditCount,
+			Mentions:    []mentions.User{},
+			Headline:    helper.SanitizeHeadline(row.Headline),
+			Rank:        row.Rank,
 			CreatedAt:   row.CreatedAt.Time,
 			UpdatedAt:   row.UpdatedAt.Time,
 		})
 	}
-	return posts, nil
+
+	fetched := len(posts)
+	if fetched > int(page.Limit) {
+		posts = posts[:page.Limit]
+	}
+
+	nextCursor := ""
+	if len(posts) > 0 {
+		last := posts[len(posts)-1]
+		nextCursor = helper.EncodeCursor(helper.SearchCursor{Rank: last.Rank, ID: last.PostID})
+	}
+
+	err = s.attachMentions(ctx, posts)
+	if err != nil {
+		return []Post{}, api.PageMeta{}, err
+	}
+	return posts, helper.NewPageMeta(fetched, page, total, nextCursor), nil
 }
 
-// LikesPost increments the like count for the specific post by 1.
+// LikesPost increments the like count for the specific post by 1, and notifies the author of the
+// post. The new vote counts are pushed to the clients. Users who are suspended from the topic
+// cannot vote.
 func (s *svc) LikesPost(ctx context.Context, arg repo.LikesPostParams) error {
-	err := s.repo.LikesPost(ctx, arg)
+	err := s.checkSuspended(ctx, arg.UserID, arg.PostID)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.LikesPost(ctx, arg)
 	if err != nil {
 		return err
 	}
 
+	err = s.notifyVote(ctx, arg)
+	if err != nil {
+		log.Printf("failed to notify vote on post %d: %v", arg.PostID, err)
+	}
+
+	s.publishVote(ctx, arg.PostID, arg.UserID)
 	return nil
 }
 
-// DislikesPost increments the dislike count for the specific post by 1.
+// DislikesPost increments the dislike count for the specific post by 1. The new vote counts are
+// pushed to the clients. Users who are suspended from the topic cannot vote.
 func (s *svc) DislikesPost(ctx context.Context, arg repo.DislikesPostParams) error {
-	err := s.repo.DislikesPost(ctx, arg)
+	err := s.checkSuspended(ctx, arg.UserID, arg.PostID)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.DislikesPost(ctx, arg)
 	if err != nil {
 		return err
 	}
 
+	s.publishVote(ctx, arg.PostID, arg.UserID)
 	return nil
 }
 
-// RemovePostVote removes the user's vote for that specific post.
+// RemovePostVote removes the user's vote for that specific post. The new vote counts are pushed to
+// the clients. Users who are suspended from the topic cannot vote.
 func (s *svc) RemovePostVote(ctx context.Context, arg repo.RemovePostVoteParams) error {
+	err := s.checkSuspended(ctx, arg.UserID, arg.PostID)
+	if err != nil {
+		return err
+	}
+
 	delRows, err := s.repo.RemovePostVote(ctx, arg)
 	if err != nil {
 		return err
@@ -181,5 +582,444 @@ func (s *svc) RemovePostVote(ctx context.Context, arg repo.RemovePostVoteParams)
 		return ErrVoteNotFound
 	}
 
+	s.publishVote(ctx, arg.PostID, arg.UserID)
 	return nil
 }
+
+// VotePoll casts the ballot of the user on the poll of the post given by the id, replacing any
+// ballot the user cast before, and returns the new tallies. Each option id must belong to the poll,
+// and single choice polls only accept one option. Closed polls no longer accept ballots. Users who
+// are suspended from the topic cannot vote.
+// The ballot row is upserted first, which locks it until the transaction ends, so concurrent votes
+// of the same user are applied one after the other.
+func (s *svc) VotePoll(ctx context.Context, userId int64, postId int64, optionIds []int64) (Poll, error) {
+	err := s.checkSuspended(ctx, userId, postId)
+	if err != nil {
+		return Poll{}, err
+	}
+
+	poll, err := s.repo.FindPoll(ctx, postId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return Poll{}, ErrPollNotFound
+		}
+		return Poll{}, err
+	}
+
+	if poll.ClosesAt.Valid && !poll.ClosesAt.Time.After(time.Now()) {
+		return Poll{}, ErrPollClosed
+	}
+
+	choices := make([]int64, 0, len(optionIds))
+	for _, id := range optionIds {
+		if !slices.Contains(choices, id) {
+			choices = append(choices, id)
+		}
+	}
+	if len(choices) == 0 || (!poll.MultipleChoice && len(choices) > 1) {
+		return Poll{}, ErrInvalidPollOption
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return Poll{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.repo.WithTx(tx)
+	ballotArg := repo.UpsertPollBallotParams{
+		PostID: postId,
+		UserID: userId,
+	}
+	err = qtx.UpsertPollBallot(ctx, ballotArg)
+	if err != nil {
+		return Poll{}, err
+	}
+
+	deleteArg := repo.DeletePollBallotOptionsParams{
+		PostID: postId,
+		UserID: userId,
+	}
+	err = qtx.DeletePollBallotOptions(ctx, deleteArg)
+	if err != nil {
+		return Poll{}, err
+	}
+
+	optionsArg := repo.CreatePollBallotOptionsParams{
+		UserID:    userId,
+		PostID:    postId,
+		OptionIds: choices,
+	}
+	inserted, err := qtx.CreatePollBallotOptions(ctx, optionsArg)
+	if err != nil {
+		return Poll{}, err
+	}
+	if inserted != int64(len(choices)) {
+		return Poll{}, ErrInvalidPollOption
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return Poll{}, err
+	}
+
+	return s.findPoll(ctx, postId, userId)
+}
+
+// ListRevisions returns all versions of the post given by the id, from the current content to the
+// original post. Revisions of deleted posts are hidden together with the post.
+func (s *svc) ListRevisions(ctx context.Context, postId int64) ([]Revision, error) {
+	current, err := s.currentRevision(ctx, postId)
+	if err != nil {
+		return []Revision{}, err
+	}
+
+	rows, err := s.repo.ListPostRevisions(ctx, postId)
+	if err != nil {
+		return []Revision{}, err
+	}
+
+	revisions := make([]Revision, 0, len(rows)+1)
+	revisions = append(revisions, current)
+	for _, row := range rows {
+		revisions = append(revisions, toRevision(repo.FindPostRevisionRow(row)))
+	}
+
+	return revisions, nil
+}
+
+// DiffRevisions returns the line-based changes of the title and description of the post given by
+// the id, from one version to another. Either version may be the current content.
+func (s *svc) DiffRevisions(ctx context.Context, postId int64, from int32, to int32) (RevisionDiff, error) {
+	current, err := s.currentRevision(ctx, postId)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+
+	fromRevision, err := s.findRevision(ctx, postId, from, current)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+
+	toRevision, err := s.findRevision(ctx, postId, to, current)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+
+	title, err := helper.DiffLines(fromRevision.Title, toRevision.Title)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+
+	description, err := helper.DiffLines(fromRevision.Description, toRevision.Description)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+
+	diff := RevisionDiff{
+		From:        from,
+		To:          to,
+		Title:       title,
+		Description: description,
+	}
+	return diff, nil
+}
+
+// currentRevision returns the current content of the post as its latest revision.
+func (s *svc) currentRevision(ctx context.Context, postId int64) (Revision, error) {
+	post, err := s.repo.FindPostSnapshot(ctx, postId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return Revision{}, ErrPostNotFound
+		}
+		return Revision{}, err
+	}
+
+	if post.DeletedAt.Valid {
+		return Revision{}, ErrPostNotFound
+	}
+
+	revision := Revision{
+		Version:     post.EditCount + 1,
+		Title:       post.Title,
+		Description: post.Description,
+		Current:     true,
+		CreatedAt:   post.UpdatedAt.Time,
+	}
+	return revision, nil
+}
+
+// findRevision returns the given version of the post, which is either the current content or one
+// of its prior revisions.
+func (s *svc) findRevision(ctx context.Context, postId int64, version int32, current Revision) (Revision, error) {
+	if version == current.Version {
+		return current, nil
+	}
+
+	arg := repo.FindPostRevisionParams{
+		PostID:  postId,
+		Version: version,
+	}
+	row, err := s.repo.FindPostRevision(ctx, arg)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return Revision{}, ErrRevisionNotFound
+		}
+		return Revision{}, err
+	}
+
+	return toRevision(row), nil
+}
+
+// findPoll returns the poll of the post given by the id with its tallies, and the options chosen by
+// the user.
+func (s *svc) findPoll(ctx context.Context, postId int64, userId int64) (Poll, error) {
+	row, err := s.repo.FindPoll(ctx, postId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return Poll{}, ErrPollNotFound
+		}
+		return Poll{}, err
+	}
+
+	optionsArg := repo.FindPollOptionsParams{
+		UserID: userId,
+		PostID: postId,
+	}
+	rows, err := s.repo.FindPollOptions(ctx, optionsArg)
+	if err != nil {
+		return Poll{}, err
+	}
+
+	voters, err := s.repo.CountPollVoters(ctx, postId)
+	if err != nil {
+		return Poll{}, err
+	}
+
+	poll := Poll{
+		Question:       row.Question,
+		MultipleChoice: row.MultipleChoice,
+		Closed:         row.ClosesAt.Valid && !row.ClosesAt.Time.After(time.Now()),
+		Options:        make([]PollOption, 0, len(rows)),
+		TotalVoters:    voters,
+		UserChoices:    []int64{},
+	}
+	if row.ClosesAt.Valid {
+		poll.ClosesAt = &row.ClosesAt.Time
+	}
+	for _, option := range rows {
+		poll.Options = append(poll.Options, PollOption{
+			OptionID: option.OptionID,
+			Label:    option.Label,
+			Votes:    option.Votes,
+		})
+		if option.Chosen {
+			poll.UserChoices = append(poll.UserChoices, option.OptionID)
+		}
+	}
+
+	return poll, nil
+}
+
+// checkSuspended returns a *helper.SuspendedError if the user is suspended from the topic the post
+// belongs to.
+func (s *svc) checkSuspended(ctx context.Context, userId int64, postId int64) error {
+	post, err := s.repo.FindPostAuthor(ctx, postId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrPostNotFound
+		}
+		return err
+	}
+
+	return s.suspensions.CheckTopic(ctx, userId, post.TopicID)
+}
+
+// checkPermission returns the author of the post if the user is the author or can moderate the
+// topic the post belongs to.
+func (s *svc) checkPermission(ctx context.Context, userId int64, postId int64) (repo.FindPostAuthorRow, error) {
+	post, err := s.repo.FindPostAuthor(ctx, postId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return repo.FindPostAuthorRow{}, ErrPostNotFound
+		}
+		return repo.FindPostAuthorRow{}, err
+	}
+
+	if post.UserID == userId {
+		return post, nil
+	}
+
+	allowed, err := s.roles.CanModerateTopic(ctx, userId, post.TopicID)
+	if err != nil {
+		return repo.FindPostAuthorRow{}, err
+	}
+	if !allowed {
+		return repo.FindPostAuthorRow{}, ErrPermissionDenied
+	}
+
+	return post, nil
+}
+
+// notifyVote notifies the author of the post that the user liked it.
+func (s *svc) notifyVote(ctx context.Context, vote repo.LikesPostParams) error {
+	post, err := s.repo.FindPostAuthor(ctx, vote.PostID)
+	if err != nil {
+		return err
+	}
+
+	arg := repo.CreateNotificationParams{
+		UserID:  post.UserID,
+		ActorID: vote.UserID,
+		Type:    notifications.TypePostVote,
+		TopicID: pgtype.Int8{Int64: post.TopicID, Valid: true},
+		PostID:  pgtype.Int8{Int64: post.PostID, Valid: true},
+	}
+	return s.notifications.Notify(ctx, arg)
+}
+
+// attachMentions sets the mentioned users of each post, and renders its description with the
+// mentions linked to their profiles.
+func (s *svc) attachMentions(ctx context.Context, posts []Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	postIds := make([]int64, 0, len(posts))
+	for _, post := range posts {
+		postIds = append(postIds, post.PostID)
+	}
+
+	users, err := s.mentions.ForPosts(ctx, postIds)
+	if err != nil {
+		return err
+	}
+
+	for i := range posts {
+		mentioned, ok := users[posts[i].PostID]
+		if ok {
+			posts[i].Mentions = mentioned
+		}
+		posts[i].DescriptionHTML = helper.RenderMarkdown(posts[i].Description, mentions.Usernames(mentioned)...)
+	}
+	return nil
+}
+
+// publishPost pushes a change to the post to the clients that follow its topic.
+func (s *svc) publishPost(ctx context.Context, eventType string, post repo.Post) {
+	err := s.stream.Publish(ctx, stream.Event{
+		Type:    eventType,
+		TopicID: post.TopicID,
+		PostID:  post.PostID,
+		UserID:  post.UserID,
+	})
+	if err != nil {
+		log.Printf("failed to publish %s for post %d: %v", eventType, post.PostID, err)
+	}
+}
+
+// publishVote pushes the new vote counts of the post to the clients that follow it.
+func (s *svc) publishVote(ctx context.Context, postId int64, userId int64) {
+	counts, err := s.repo.FindPostVoteCounts(ctx, postId)
+	if err != nil {
+		log.Printf("failed to count votes of post %d: %v", postId, err)
+		return
+	}
+
+	err = s.stream.Publish(ctx, stream.Event{
+		Type:     stream.TypePostVoted,
+		TopicID:  counts.TopicID,
+		PostID:   counts.PostID,
+		UserID:   userId,
+		Likes:    &counts.Likes,
+		Dislikes: &counts.Dislikes,
+	})
+	if err != nil {
+		log.Printf("failed to publish %s for post %d: %v", stream.TypePostVoted, postId, err)
+	}
+}
+
+// toRevision converts a prior revision row into the Revision model.
+func toRevision(row repo.FindPostRevisionRow) Revision {
+	revision := Revision{
+		Version:        row.Version,
+		Title:          row.Title,
+		Description:    row.Description,
+		ReplacedByName: row.ReplacedByName,
+		CreatedAt:      row.CreatedAt.Time,
+		ReplacedAt:     &row.ReplacedAt.Time,
+	}
+
+	if row.ReplacedBy.Valid {
+		replacedBy := row.ReplacedBy.Int64
+		revision.ReplacedBy = &replacedBy
+	}
+
+	return revision
+}
+
+// paginatePosts trims the extra post fetched to detect further pages, and converts the rows into
+// the Post model together with the pagination metadata and the cursor of the last post.
+func paginatePosts(rows []repo.FindPostsByTopicRow, sort string, page helper.Page, total int64) ([]Post, api.PageMeta) {
+	fetched := len(rows)
+	if fetched > int(page.Limit) {
+		rows = rows[:page.Limit]
+	}
+
+	posts := make([]Post, 0, len(rows))
+	for _, row := range rows {
+		posts = append(posts, Post{
+			PostID:      row.PostID,
+			TopicID:     row.TopicID,
+			UserID:      row.UserID,
+			Username:    row.Username,
+			Title:       row.Title,
+			Description: row.Description,
+			Likes:       row.Likes,
+			Dislikes:    row.Dislikes,
+			UserVote:    row.UserVote,
+			Saved:       row.Saved,
+			Edited:      row.EditCount > 0,
+			EditCount:   row.EditCount,
+			Mentions:    []mentions.User{},
+			CreatedAt:   row.CreatedAt.Time,
+			UpdatedAt:   row.UpdatedAt.Time,
+		})
+	}
+
+	nextCursor := ""
+	if len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = helper.EncodeCursor(postCursor{
+			Sort:      sort,
+			SortKey:   last.SortKey,
+			UpdatedAt: last.UpdatedAt.Time,
+			PostID:    last.PostID,
+		})
+	}
+
+	return posts, helper.NewPageMeta(fetched, page, total, nextCursor)
+}
+
+// decodePostCursor returns the sort keys to continue after, or invalid values for the first page.
+// A cursor from a different sort order is invalid.
+func decodePostCursor(cursor string, sort string) (pgtype.Float8, pgtype.Timestamptz, pgtype.Int8, error) {
+	if cursor == "" {
+		return pgtype.Float8{}, pgtype.Timestamptz{}, pgtype.Int8{}, nil
+	}
+
+	var c postCursor
+	err := helper.DecodeCursor(cursor, &c)
+	if err != nil {
+		return pgtype.Float8{}, pgtype.Timestamptz{}, pgtype.Int8{}, err
+	}
+
+	if c.Sort != sort {
+		return pgtype.Float8{}, pgtype.Timestamptz{}, pgtype.Int8{}, helper.ErrInvalidCursor
+	}
+
+	sortKey := pgtype.Float8{Float64: c.SortKey, Valid: true}
+	updatedAt := pgtype.Timestamptz{Time: c.UpdatedAt, Valid: true}
+	postId := pgtype.Int8{Int64: c.PostID, Valid: true}
+	return sortKey, updatedAt, postId, nil
+}
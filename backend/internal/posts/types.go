@@ -4,47 +4,142 @@ import (
 	"context"
 	"time"
 
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	"github.com/haobuhaoo/gossip-with-go/internal/mentions"
 	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
 )
 
 // Service defines the domain logic for post related operations.
 // It is responsible for enforcing application rules and making database calls.
 type Service interface {
-	FindPostsByTopic(ctx context.Context, arg repo.FindPostsByTopicParams) ([]Post, error)
+	FindPostsByTopic(ctx context.Context, arg repo.FindPostsByTopicParams, page helper.Page) ([]Post, api.PageMeta, error)
+	FindFeed(ctx context.Context, arg repo.FindFeedParams, page helper.Page) ([]Post, api.PageMeta, error)
 	FindPostByID(ctx context.Context, arg repo.FindPostByIDParams) (Post, error)
-	CreatePost(ctx context.Context, arg repo.CreatePostParams) (repo.Post, error)
-	UpdatePost(ctx context.Context, arg repo.UpdatePostParams) (repo.Post, error)
-	DeletePost(ctx context.Context, arg repo.DeletePostParams) error
-	SearchPost(ctx context.Context, arg repo.SearchPostParams) ([]Post, error)
+	CreatePost(ctx context.Context, arg repo.CreatePostParams, poll *CreatePollRequest) (repo.Post, error)
+	UpdatePost(ctx context.Context, userId int64, arg repo.UpdatePostParams) (repo.Post, error)
+	DeletePost(ctx context.Context, userId int64, postId int64) error
+	RestorePost(ctx context.Context, userId int64, postId int64) (repo.Post, error)
+	SearchPost(ctx context.Context, arg repo.SearchPostParams, page helper.Page) ([]Post, api.PageMeta, error)
 	LikesPost(ctx context.Context, arg repo.LikesPostParams) error
 	DislikesPost(ctx context.Context, arg repo.DislikesPostParams) error
 	RemovePostVote(ctx context.Context, arg repo.RemovePostVoteParams) error
+	ListRevisions(ctx context.Context, postId int64) ([]Revision, error)
+	DiffRevisions(ctx context.Context, postId int64, from int32, to int32) (RevisionDiff, error)
+	VotePoll(ctx context.Context, userId int64, postId int64, optionIds []int64) (Poll, error)
 }
 
 // Post model that is passed to the frontend.
+// Description is the CommonMark source of the post, and DescriptionHTML is its rendered and
+// sanitized HTML.
+// Saved tells whether the requesting user has saved the post, and Mentions lists the users
+// mentioned in the description.
+// Edited is set once the post has been updated, and EditCount is the number of updates, so that
+// the post has EditCount prior revisions.
+// Poll is only set when a single post is fetched and the post has a poll.
+// Headline and Rank are only set for search results, where the headline is a snippet of the
+// description with the matched terms wrapped in <mark> tags.
 type Post struct {
-	PostID      int64       `json:"post_id"`
-	TopicID     int64       `json:"topic_id"`
-	UserID      int64       `json:"user_id"`
-	Username    string      `json:"username"`
-	Title       string      `json:"title"`
-	Description string      `json:"description"`
-	Likes       int64       `json:"likes"`
-	Dislikes    int64       `json:"dislikes"`
-	UserVote    interface{} `json:"user_vote"`
-	CreatedAt   time.Time   `json:"created_at"`
-	UpdatedAt   time.Time   `json:"updated_at"`
+	PostID          int64           `json:"post_id"`
+	TopicID         int64           `json:"topic_id"`
+	UserID          int64           `json:"user_id"`
+	Username        string          `json:"username"`
+	Title           string          `json:"title"`
+	Description     string          `json:"description"`
+	DescriptionHTML string          `json:"description_html"`
+	Likes           int64           `json:"likes"`
+	Dislikes        int64           `json:"dislikes"`
+	UserVote        interface{}     `json:"user_vote"`
+	Saved           bool            `json:"saved"`
+	Edited          bool            `json:"edited"`
+	EditCount       int32           `json:"edit_count"`
+	Mentions        []mentions.User `json:"mentions"`
+	Poll            *Poll           `json:"poll,omitempty"`
+	Headline        string          `json:"headline,omitempty"`
+	Rank            float32         `json:"rank,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+// Poll model of the poll attached to a post that is passed to the frontend.
+// Votes of each option and TotalVoters are the tallies of all ballots, and UserChoices holds the
+// option ids chosen by the requesting user, which is empty if the user has not voted. Closed is set
+// once ClosesAt has passed, after which ballots can no longer be cast.
+type Poll struct {
+	Question       string       `json:"question"`
+	MultipleChoice bool         `json:"multiple_choice"`
+	ClosesAt       *time.Time   `json:"closes_at"`
+	Closed         bool         `json:"closed"`
+	Options        []PollOption `json:"options"`
+	TotalVoters    int64        `json:"total_voters"`
+	UserChoices    []int64      `json:"user_choices"`
+}
+
+// PollOption model of a single option of a poll that is passed to the frontend.
+type PollOption struct {
+	OptionID int64  `json:"option_id"`
+	Label    string `json:"label"`
+	Votes    int64  `json:"votes"`
+}
+
+// Revision model of a version of a post that is passed to the frontend.
+// Version 1 is the original post, and the version after the last prior revision is the current
+// content. ReplacedBy and ReplacedAt are only set for prior revisions, and tell which user replaced
+// the revision with an update and when.
+type Revision struct {
+	Version        int32      `json:"version"`
+	Title          string     `json:"title"`
+	Description    string     `json:"description"`
+	Current        bool       `json:"current"`
+	ReplacedBy     *int64     `json:"replaced_by"`
+	ReplacedByName string     `json:"replaced_by_name,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ReplacedAt     *time.Time `json:"replaced_at"`
+}
+
+// RevisionDiff model of the line-based changes between two revisions of a post that is passed to
+// the frontend.
+type RevisionDiff struct {
+	From        int32             `json:"from"`
+	To          int32             `json:"to"`
+	Title       []helper.DiffLine `json:"title"`
+	Description []helper.DiffLine `json:"description"`
+}
+
+// postCursor holds the sort order and sort keys of the last post in a page, which is encoded into
+// the opaque next_cursor string.
+type postCursor struct {
+	Sort      string    `json:"sort"`
+	SortKey   float64   `json:"sort_key"`
+	UpdatedAt time.Time `json:"updated_at"`
+	PostID    int64     `json:"post_id"`
 }
 
 // CreatePostRequest handles the post related HTTP request body for creation of a new post.
 type CreatePostRequest struct {
-	TopicID     int64  `json:"topicId" validate:"required,min=1"`
-	Title       string `json:"title" validate:"required"`
-	Description string `json:"description" validate:"required"`
+	TopicID     int64              `json:"topicId" validate:"required,min=1"`
+	Title       string             `json:"title" validate:"required,max=200"`
+	Description string             `json:"description" validate:"required,max=20000"`
+	Poll        *CreatePollRequest `json:"poll" validate:"omitempty"`
+}
+
+// CreatePollRequest handles the optional poll of the HTTP request body for creation of a new post.
+// A poll has between 2 and 10 distinct options, and stays open forever if ClosesAt is not given.
+type CreatePollRequest struct {
+	Question       string     `json:"question" validate:"required"`
+	Options        []string   `json:"options" validate:"required,min=2,max=10,unique,dive,required"`
+	MultipleChoice bool       `json:"multipleChoice"`
+	ClosesAt       *time.Time `json:"closesAt"`
+}
+
+// VotePollRequest handles the poll related HTTP request body for casting a ballot. Single choice
+// polls only accept one option id.
+type VotePollRequest struct {
+	OptionIDs []int64 `json:"optionIds" validate:"required,min=1,dive,min=1"`
 }
 
 // UpdatePostRequest handles the post related HTTP request body for updating of existing post.
 type UpdatePostRequest struct {
-	Title       string `json:"title" validate:"required"`
-	Description string `json:"description" validate:"required"`
+	Title       string `json:"title" validate:"required,max=200"`
+	Description string `json:"description" validate:"required,max=20000"`
 }
@@ -0,0 +1,115 @@
+package purge
+
+import (
+	"context"
+	"log"
+	"time"
+
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+	"github.com/haobuhaoo/gossip-with-go/internal/storage"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// interval is how often Run purges the deleted content.
+const interval = time.Hour
+
+// svc implements the Service interface.
+// It depends on the sql generated Queries type to interact with the PostgreSQL database, and on the
+// storage to remove the files of purged attachments.
+type svc struct {
+	repo      *repo.Queries
+	storage   storage.Storage
+	retention time.Duration
+}
+
+// NewService creates a new purge service that purges content deleted longer than retention ago.
+func NewService(repo *repo.Queries, storage storage.Storage, retention time.Duration) Service {
+	return &svc{
+		repo:      repo,
+		storage:   storage,
+		retention: retention,
+	}
+}
+
+// Purge removes the posts and comments that were deleted longer than the retention period ago,
+// together with everything under them. Deleted comments are removed leaves first, so a thread of
+// deleted comments is removed in one purge, while deleted comments that still have live replies are
+// kept as tombstones with their content and revisions erased until their replies are gone. The
+// attachments of purged posts and comments are removed together with their stored files.
+func (s *svc) Purge(ctx context.Context) (Result, error) {
+	before := pgtype.Timestamptz{Time: time.Now().Add(-s.retention), Valid: true}
+
+	var result Result
+	attachments, err := s.repo.PurgeDeletedAttachments(ctx, before)
+	if err != nil {
+		return Result{}, err
+	}
+
+	for _, attachment := range attachments {
+		s.removeFile(ctx, attachment.StorageKey)
+		if attachment.ThumbnailKey.Valid {
+			s.removeFile(ctx, attachment.ThumbnailKey.String)
+		}
+	}
+	result.Attachments = int64(len(attachments))
+
+	result.Posts, err = s.repo.PurgeDeletedPosts(ctx, before)
+	if err != nil {
+		return Result{}, err
+	}
+
+	for {
+		purged, err := s.repo.PurgeDeletedComments(ctx, before)
+		if err != nil {
+			return Result{}, err
+		}
+		if purged == 0 {
+			break
+		}
+		result.Comments += purged
+	}
+
+	err = s.repo.PurgeTombstoneRevisions(ctx, before)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result.Tombstones, err = s.repo.PurgeCommentTombstones(ctx, before)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return result, nil
+}
+
+// Run purges the deleted content once an hour until ctx is cancelled. It is meant to be run in its
+// own goroutine.
+func (s *svc) Run(ctx context.Context) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := s.Purge(ctx)
+		if err != nil {
+			log.Printf("failed to purge deleted content: %v", err)
+		} else if result.Posts > 0 || result.Comments > 0 || result.Tombstones > 0 || result.Attachments > 0 {
+			log.Printf("purged %d posts, %d comments and %d attachments, and erased %d comment tombstones",
+				result.Posts, result.Comments, result.Attachments, result.Tombstones)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// removeFile removes the stored file of a purged attachment. Failures are only logged, as the
+// attachment itself is already gone.
+func (s *svc) removeFile(ctx context.Context, key string) {
+	err := s.storage.Delete(ctx, key)
+	if err != nil {
+		log.Printf("failed to delete stored file %s: %v", key, err)
+	}
+}
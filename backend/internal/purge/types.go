@@ -0,0 +1,27 @@
+package purge
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultRetention is how long deleted posts and comments are kept before they are purged, unless
+// it is configured otherwise.
+const DefaultRetention = 30 * 24 * time.Hour
+
+// Service defines the domain logic for purging deleted content.
+// It is responsible for enforcing application rules and making database calls.
+type Service interface {
+	Purge(ctx context.Context) (Result, error)
+	Run(ctx context.Context)
+}
+
+// Result counts what a purge removed. Posts, Comments and Attachments are the rows deleted from
+// the database, and Tombstones are the deleted comments that are kept for their replies but had
+// their content erased.
+type Result struct {
+	Posts       int64
+	Comments    int64
+	Attachments int64
+	Tombstones  int64
+}
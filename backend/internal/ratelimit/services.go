@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// purgeInterval is how often Run purges the idle buckets.
+	purgeInterval = time.Hour
+
+	// idleTimeout is how long a bucket has to be unused before it is purged. It is longer than any
+	// bucket takes to refill, so purged buckets would have been full anyway.
+	idleTimeout = 24 * time.Hour
+)
+
+// svc implements the Service interface.
+// It depends on the sql generated Queries type to interact with the PostgreSQL database.
+type svc struct {
+	repo *repo.Queries
+}
+
+// NewService creates a new rate limit service.
+func NewService(repo *repo.Queries) Service {
+	return &svc{
+		repo: repo,
+	}
+}
+
+// Take takes a token from the bucket given by the key, which holds up to burst tokens and gains a
+// token every interval. New buckets start full. It returns 0 if a token was taken, or how long to
+// wait for the next token if the bucket is empty, which is at least a second.
+func (s *svc) Take(ctx context.Context, key string, burst int32, interval time.Duration) (time.Duration, error) {
+	arg := repo.TakeRateLimitTokenParams{
+		BucketKey:       key,
+		Burst:           float64(burst),
+		IntervalSeconds: interval.Seconds(),
+	}
+	_, err := s.repo.TakeRateLimitToken(ctx, arg)
+	if err == nil {
+		return 0, nil
+	}
+	if err != pgx.ErrNoRows {
+		return 0, err
+	}
+
+	findArg := repo.FindRateLimitTokensParams{
+		Burst:           float64(burst),
+		IntervalSeconds: interval.Seconds(),
+		BucketKey:       key,
+	}
+	tokens, err := s.repo.FindRateLimitTokens(ctx, findArg)
+	if err != nil {
+		return 0, err
+	}
+
+	wait := time.Duration(math.Max(1-tokens, 0) * float64(interval))
+	return max(wait, time.Second), nil
+}
+
+// Purge removes the buckets that have not been used for a day.
+func (s *svc) Purge(ctx context.Context) (int64, error) {
+	before := pgtype.Timestamptz{Time: time.Now().Add(-idleTimeout), Valid: true}
+	return s.repo.PurgeRateLimits(ctx, before)
+}
+
+// Run purges the idle buckets once an hour until ctx is cancelled. It is meant to be run in its own
+// goroutine.
+func (s *svc) Run(ctx context.Context) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		_, err := s.Purge(ctx)
+		if err != nil {
+			log.Printf("failed to purge rate limits: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
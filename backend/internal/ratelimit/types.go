@@ -0,0 +1,15 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Service defines the domain logic for token bucket rate limits that are stored in the database,
+// so that the limits hold across instances of the server.
+// It is responsible for enforcing application rules and making database calls.
+type Service interface {
+	Take(ctx context.Context, key string, burst int32, interval time.Duration) (time.Duration, error)
+	Purge(ctx context.Context) (int64, error)
+	Run(ctx context.Context)
+}
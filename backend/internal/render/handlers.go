@@ -0,0 +1,61 @@
+package render
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+)
+
+const (
+	SuccessfulPreviewMessage = "Successfully rendered preview"
+)
+
+// handler handles the render related HTTP requests.
+// It is responsible for translating HTTP requests into service calls and formatting service
+// responses into HTTP responses.
+type handler struct {
+	service Service
+}
+
+// NewHandler creates a new render handler.
+func NewHandler(service Service) *handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// Preview handles POST /api/render/preview requests.
+// It reads and validates the request body, and passes the description to the render service to
+// render it into sanitized HTML with its mentions resolved. It then serializes the result into a
+// JSON HTTP response.
+func (h *handler) Preview(w http.ResponseWriter, r *http.Request) {
+	var req PreviewRequest
+	err := helper.Read(r, &req)
+	if err != nil {
+		helper.WriteError(w, err.Error(), http.StatusBadRequest, api.CodeMalformedBody)
+		return
+	}
+
+	err = helper.Validate(req)
+	if err != nil {
+		helper.WriteValidationError(w, err)
+		return
+	}
+
+	preview, err := h.service.Preview(r.Context(), req.Description)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonPreview, err := json.Marshal(preview)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonPreview, SuccessfulPreviewMessage)
+	helper.Write(w, response)
+}
@@ -0,0 +1,11 @@
+package render
+
+import "github.com/go-chi/chi/v5"
+
+// Routes group all render related HTTP endpoints together, with the base prefix path /render.
+// It connects the URLS to their respective handler methods.
+func Routes(router chi.Router, h *handler) {
+	router.Route("/render", func(r chi.Router) {
+		r.Post("/preview", h.Preview)
+	})
+}
@@ -0,0 +1,36 @@
+package render
+
+import (
+	"context"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/mentions"
+)
+
+// svc implements the Service interface.
+// It depends on the mention service to resolve the mentioned users.
+type svc struct {
+	mentions mentions.Service
+}
+
+// NewService creates a new render service.
+func NewService(mentions mentions.Service) Service {
+	return &svc{
+		mentions: mentions,
+	}
+}
+
+// Preview renders the CommonMark description into sanitized HTML, exactly as it is returned
+// together with a saved post or comment, with the mentions of existing users linked to their
+// profiles.
+func (s *svc) Preview(ctx context.Context, description string) (Preview, error) {
+	html, err := s.mentions.Render(ctx, description)
+	if err != nil {
+		return Preview{}, err
+	}
+
+	preview := Preview{
+		Description:     description,
+		DescriptionHTML: html,
+	}
+	return preview, nil
+}
@@ -0,0 +1,20 @@
+package render
+
+import "context"
+
+// Service defines the domain logic for rendering user generated content.
+// It renders content the same way as the post and comment services do.
+type Service interface {
+	Preview(ctx context.Context, description string) (Preview, error)
+}
+
+// Preview model that is passed to the frontend, with the sanitized HTML of a rendered description.
+type Preview struct {
+	Description     string `json:"description"`
+	DescriptionHTML string `json:"description_html"`
+}
+
+// PreviewRequest handles the render related HTTP request body for previewing a description.
+type PreviewRequest struct {
+	Description string `json:"description" validate:"required"`
+}
@@ -0,0 +1,12 @@
+package reports
+
+import "errors"
+
+var (
+	ErrTargetNotFound   = errors.New("reported content not found")
+	ErrAlreadyReported  = errors.New("already reported")
+	ErrReportNotFound   = errors.New("report not found")
+	ErrReportNotOpen    = errors.New("report already resolved")
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrInvalidAction    = errors.New("action does not apply to the reported content")
+)
@@ -0,0 +1,202 @@
+package reports
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+)
+
+const (
+	InvalidReportIdMessage         = "Invalid report id"
+	InvalidPageMessage             = "Invalid limit or cursor"
+	MissingUserIDMessage           = "Missing userID"
+	SuccessfulCreateReportMessage  = "Successfully reported content"
+	SuccessfulListQueueMessage     = "Successfully listed all open reports"
+	SuccessfulResolveReportMessage = "Successfully resolved report"
+)
+
+// handler handles the report related HTTP requests.
+// It is responsible for translating HTTP requests into service calls and formatting service
+// responses into HTTP responses.
+type handler struct {
+	service Service
+}
+
+// NewHandler creates a new report handler.
+func NewHandler(service Service) *handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// CreateReport handles POST /api/reports requests.
+// It reads and validates the request body, and passes it to the report service to report the
+// post, comment or user. It then serializes the new report into a JSON HTTP response.
+func (h *handler) CreateReport(w http.ResponseWriter, r *http.Request) {
+	var req CreateReportRequest
+	err := helper.Read(r, &req)
+	if err != nil {
+		helper.WriteError(w, err.Error(), http.StatusBadRequest, api.CodeMalformedBody)
+		return
+	}
+
+	err = helper.Validate(req)
+	if err != nil {
+		helper.WriteValidationError(w, err)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	newReport := repo.CreateReportParams{
+		ReporterID: userId,
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		Reason:     req.Reason,
+		Details:    req.Details,
+	}
+	report, err := h.service.CreateReport(r.Context(), newReport)
+	if err != nil {
+		if err == ErrTargetNotFound {
+			helper.WriteError(w, ErrTargetNotFound.Error(), http.StatusNotFound, api.CodeTargetNotFound)
+			return
+		}
+		if err == ErrAlreadyReported {
+			helper.WriteError(w, ErrAlreadyReported.Error(), http.StatusConflict, api.CodeAlreadyReported)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonReport, err := json.Marshal(report)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonReport, SuccessfulCreateReportMessage)
+	helper.Write(w, response)
+}
+
+// ListQueue handles GET /api/reports/queue requests.
+// It parses the optional limit and cursor query strings, and passes them to the report service to
+// return a page of the reported content that the user can moderate. It then serializes the page
+// and its pagination metadata into a JSON HTTP response.
+func (h *handler) ListQueue(w http.ResponseWriter, r *http.Request) {
+	page, err := helper.ReadPage(r)
+	if err != nil {
+		helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	items, meta, err := h.service.ListQueue(r.Context(), userId, page)
+	if err != nil {
+		if err == helper.ErrInvalidCursor {
+			helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonItems, err := json.Marshal(items)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonMeta, err := json.Marshal(meta)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataMetaAndMessage(jsonItems, jsonMeta, SuccessfulListQueueMessage)
+	helper.Write(w, response)
+}
+
+// ResolveReport handles POST /api/reports/{id}/resolve requests.
+// It parses the id string, reads and validates the request body, and passes it to the report
+// service to resolve the report with the given action. It then serializes the resolution into a
+// JSON HTTP response.
+func (h *handler) ResolveReport(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidReportIdMessage, http.StatusBadRequest, api.CodeInvalidReportID)
+		return
+	}
+
+	var req ResolveReportRequest
+	err = helper.Read(r, &req)
+	if err != nil {
+		helper.WriteError(w, err.Error(), http.StatusBadRequest, api.CodeMalformedBody)
+		return
+	}
+
+	err = helper.Validate(req)
+	if err != nil {
+		helper.WriteValidationError(w, err)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	resolution, err := h.service.ResolveReport(r.Context(), userId, id, req)
+	if err != nil {
+		if err == ErrReportNotFound {
+			helper.WriteError(w, ErrReportNotFound.Error(), http.StatusNotFound, api.CodeReportNotFound)
+			return
+		}
+		if err == ErrTargetNotFound {
+			helper.WriteError(w, ErrTargetNotFound.Error(), http.StatusNotFound, api.CodeTargetNotFound)
+			return
+		}
+		if err == ErrReportNotOpen {
+			helper.WriteError(w, ErrReportNotOpen.Error(), http.StatusConflict, api.CodeReportNotOpen)
+			return
+		}
+		if err == ErrPermissionDenied {
+			helper.WriteError(w, ErrPermissionDenied.Error(), http.StatusForbidden, api.CodePermissionDenied)
+			return
+		}
+		if err == ErrInvalidAction {
+			helper.WriteError(w, ErrInvalidAction.Error(), http.StatusBadRequest, api.CodeInvalidAction)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonResolution, err := json.Marshal(resolution)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonResolution, SuccessfulResolveReportMessage)
+	helper.Write(w, response)
+}
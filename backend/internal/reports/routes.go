@@ -0,0 +1,13 @@
+package reports
+
+import "github.com/go-chi/chi/v5"
+
+// Routes group all report related HTTP endpoints together, with the base prefix path /reports.
+// It connects the URLS to their respective handler methods.
+func Routes(router chi.Router, h *handler) {
+	router.Route("/reports", func(r chi.Router) {
+		r.Post("/", h.CreateReport)
+		r.Get("/queue", h.ListQueue)
+		r.Post("/{id}/resolve", h.ResolveReport)
+	})
+}
@@ -0,0 +1,501 @@
+package reports
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/audit"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	"github.com/haobuhaoo/gossip-with-go/internal/notifications"
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+	"github.com/haobuhaoo/gossip-with-go/internal/roles"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// svc implements the Service interface.
+// It depends on the sql generated Queries type to interact with the PostgreSQL database, on the
+// pgx connection pool to run transactions, on the role service to check permissions, and on the
+// audit log service to record resolutions.
+type svc struct {
+	repo  *repo.Queries
+	db    *pgxpool.Pool
+	roles roles.Service
+	audit audit.Service
+}
+
+// NewService creates a new report service.
+func NewService(repo *repo.Queries, db *pgxpool.Pool, roles roles.Service, audit audit.Service) Service {
+	return &svc{
+		repo:  repo,
+		db:    db,
+		roles: roles,
+		audit: audit,
+	}
+}
+
+// CreateReport creates and returns a new report with the given arg params. The reported content
+// must exist, and a user can only have one open report on the same content.
+func (s *svc) CreateReport(ctx context.Context, arg repo.CreateReportParams) (repo.Report, error) {
+	target, err := s.findTarget(ctx, arg.TargetType, arg.TargetID)
+	if err != nil {
+		return repo.Report{}, err
+	}
+
+	arg.TopicID = target.TopicID
+	report, err := s.repo.CreateReport(ctx, arg)
+	if err != nil {
+		if helper.IsUniqueViolation(err) {
+			return repo.Report{}, ErrAlreadyReported
+		}
+		return repo.Report{}, err
+	}
+
+	return report, nil
+}
+
+// ListQueue returns a page of the reported content that the moderator can moderate, oldest report
+// first, together with the pagination metadata. The open reports of each content are grouped
+// together. Admins and global moderators see every report, while topic moderators only see the
+// reports of their topics.
+func (s *svc) ListQueue(ctx context.Context, moderatorId int64, page helper.Page) ([]QueueItem, api.PageMeta, error) {
+	allTopics, err := s.roles.CanModerateAll(ctx, moderatorId)
+	if err != nil {
+		return []QueueItem{}, api.PageMeta{}, err
+	}
+
+	arg := repo.ListReportQueueParams{
+		AllTopics:   allTopics,
+		ModeratorID: moderatorId,
+		PageLimit:   page.Limit + 1,
+	}
+	if page.Cursor != "" {
+		var c queueCursor
+		err := helper.DecodeCursor(page.Cursor, &c)
+		if err != nil {
+			return []QueueItem{}, api.PageMeta{}, err
+		}
+
+		arg.CursorReportedAt = pgtype.Timestamptz{Time: c.FirstReportedAt, Valid: true}
+		arg.CursorTargetType = pgtype.Text{String: c.TargetType, Valid: true}
+		arg.CursorTargetID = pgtype.Int8{Int64: c.TargetID, Valid: true}
+	}
+
+	rows, err := s.repo.ListReportQueue(ctx, arg)
+	if err != nil {
+		return []QueueItem{}, api.PageMeta{}, err
+	}
+
+	countArg := repo.CountReportQueueParams{
+		AllTopics:   allTopics,
+		ModeratorID: moderatorId,
+	}
+	total, err := s.repo.CountReportQueue(ctx, countArg)
+	if err != nil {
+		return []QueueItem{}, api.PageMeta{}, err
+	}
+
+	fetched := len(rows)
+	if fetched > int(page.Limit) {
+		rows = rows[:page.Limit]
+	}
+
+	items := make([]QueueItem, 0, len(rows))
+	index := make(map[string]int, len(rows))
+	reportArg := repo.ListOpenReportsByTargetsParams{}
+	for i, row := range rows {
+		item := QueueItem{
+			TargetType:      row.TargetType,
+			TargetID:        row.TargetID,
+			ReportCount:     row.ReportCount,
+			Reasons:         map[string]int{},
+			Reports:         []Report{},
+			FirstReportedAt: row.FirstReportedAt.Time,
+			LastReportedAt:  row.LastReportedAt.Time,
+		}
+		if row.TopicID.Valid {
+			item.TopicID = &row.TopicID.Int64
+		}
+		items = append(items, item)
+
+		index[targetKey(row.TargetType, row.TargetID)] = i
+		reportArg.TargetTypes = append(reportArg.TargetTypes, row.TargetType)
+		reportArg.TargetIds = append(reportArg.TargetIds, row.TargetID)
+	}
+
+	if len(items) > 0 {
+		reports, err := s.repo.ListOpenReportsByTargets(ctx, reportArg)
+		if err != nil {
+			return []QueueItem{}, api.PageMeta{}, err
+		}
+
+		for _, report := range reports {
+			i, ok := index[targetKey(report.TargetType, report.TargetID)]
+			if !ok {
+				continue
+			}
+
+			items[i].Reasons[report.Reason]++
+			items[i].Reports = append(items[i].Reports, Report{
+				ReportID:     report.ReportID,
+				ReporterID:   report.ReporterID,
+				ReporterName: report.ReporterName,
+				Reason:       report.Reason,
+				Details:      report.Details,
+				CreatedAt:    report.CreatedAt.Time,
+			})
+		}
+	}
+
+	nextCursor := ""
+	if len(items) > 0 {
+		last := items[len(items)-1]
+		nextCursor = helper.EncodeCursor(queueCursor{
+			FirstReportedAt: last.FirstReportedAt,
+			TargetType:      last.TargetType,
+			TargetID:        last.TargetID,
+		})
+	}
+
+	return items, helper.NewPageMeta(fetched, page, total, nextCursor), nil
+}
+
+// ResolveReport resolves the report given by the id, together with every other open report of the
+// same content, by taking the action of the request and recording it with the moderator. Only a
+// user who can moderate the topic of the reported content may resolve it, and reports of users and
+// global suspensions need an admin or a global moderator. Admins cannot be suspended, and only
+// admins may suspend global moderators. The resolution, and the suspension if any, are recorded in
+// the audit log.
+// The report is locked for the length of the transaction, so a report resolved concurrently returns
+// ErrReportNotOpen. The content is removed, or the author warned, in the same transaction as the
+// resolution, so if any step fails the whole transaction is rolled back and the report stays open.
+func (s *svc) ResolveReport(ctx context.Context, moderatorId int64, reportId int64, req ResolveReportRequest) (Resolution, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return Resolution{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.repo.WithTx(tx)
+
+	report, err := qtx.LockReportByID(ctx, reportId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return Resolution{}, ErrReportNotFound
+		}
+		return Resolution{}, err
+	}
+
+	if report.Status != StatusOpen {
+		return Resolution{}, ErrReportNotOpen
+	}
+
+	if req.Action == ActionRemoveContent && report.TargetType == TargetUser {
+		return Resolution{}, ErrInvalidAction
+	}
+
+	global := !report.TopicID.Valid || (req.Action == ActionSuspendAuthor && req.Global)
+	err = s.checkPermission(ctx, moderatorId, report.TopicID, global)
+	if err != nil {
+		return Resolution{}, err
+	}
+
+	var target reportTarget
+	if req.Action == ActionWarnAuthor || req.Action == ActionSuspendAuthor {
+		target, err = s.findTarget(ctx, report.TargetType, report.TargetID)
+		if err != nil {
+			return Resolution{}, err
+		}
+	}
+
+	if req.Action == ActionSuspendAuthor {
+		err = s.checkSuspend(ctx, moderatorId, target.AuthorID)
+		if err != nil {
+			return Resolution{}, err
+		}
+	}
+
+	resolution := Resolution{
+		TargetType: report.TargetType,
+		TargetID:   report.TargetID,
+		Status:     StatusActioned,
+		Action:     req.Action,
+		ResolvedBy: moderatorId,
+	}
+	if req.Action == ActionDismiss {
+		resolution.Status = StatusDismissed
+	}
+
+	var suspension repo.Suspension
+	if req.Action == ActionSuspendAuthor {
+		expiresAt := time.Now().AddDate(0, 0, req.SuspendDays)
+		suspensionArg := repo.CreateSuspensionParams{
+			UserID:    target.AuthorID,
+			Reason:    suspensionReason(report, req.Note),
+			ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+			CreatedBy: pgtype.Int8{Int64: moderatorId, Valid: true},
+			ReportID:  pgtype.Int8{Int64: report.ReportID, Valid: true},
+		}
+		if !global {
+			suspensionArg.TopicID = report.TopicID
+		}
+
+		suspension, err = qtx.CreateSuspension(ctx, suspensionArg)
+		if err != nil {
+			return Resolution{}, err
+		}
+		resolution.SuspendedUntil = &expiresAt
+	}
+
+	resolveArg := repo.ResolveReportsParams{
+		Status:         resolution.Status,
+		Action:         pgtype.Text{String: req.Action, Valid: true},
+		ResolvedBy:     pgtype.Int8{Int64: moderatorId, Valid: true},
+		ResolutionNote: req.Note,
+		TargetType:     report.TargetType,
+		TargetID:       report.TargetID,
+	}
+	resolution.ResolvedReports, err = qtx.ResolveReports(ctx, resolveArg)
+	if err != nil {
+		return Resolution{}, err
+	}
+	if resolution.ResolvedReports == 0 {
+		return Resolution{}, ErrReportNotOpen
+	}
+
+	switch req.Action {
+	case ActionRemoveContent:
+		err = s.removeContent(ctx, qtx, moderatorId, report)
+	case ActionWarnAuthor:
+		err = s.warnAuthor(ctx, qtx, moderatorId, report, target, req.Note)
+	}
+	if err != nil {
+		return Resolution{}, err
+	}
+
+	err = s.audit.Record(ctx, qtx, audit.Entry{
+		ActorID:    moderatorId,
+		Action:     audit.ActionReportResolve,
+		TargetType: audit.TargetReport,
+		TargetID:   report.ReportID,
+		TopicID:    report.TopicID.Int64,
+		Before:     report,
+		After:      resolution,
+	})
+	if err != nil {
+		return Resolution{}, err
+	}
+
+	if req.Action == ActionSuspendAuthor {
+		err = s.audit.Record(ctx, qtx, audit.Entry{
+			ActorID:    moderatorId,
+			Action:     audit.ActionSuspensionCreate,
+			TargetType: audit.TargetUser,
+			TargetID:   suspension.UserID,
+			TopicID:    suspension.TopicID.Int64,
+			After:      suspension,
+		})
+		if err != nil {
+			return Resolution{}, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return Resolution{}, err
+	}
+
+	return resolution, nil
+}
+
+// findTarget returns the author and location of the reported content, or ErrTargetNotFound if it
+// does not exist.
+func (s *svc) findTarget(ctx context.Context, targetType string, targetId int64) (reportTarget, error) {
+	var target reportTarget
+
+	switch targetType {
+	case TargetPost:
+		post, err := s.repo.FindPostAuthor(ctx, targetId)
+		if err != nil {
+			return reportTarget{}, notFound(err)
+		}
+
+		target.AuthorID = post.UserID
+		target.TopicID = pgtype.Int8{Int64: post.TopicID, Valid: true}
+		target.PostID = pgtype.Int8{Int64: post.PostID, Valid: true}
+	case TargetComment:
+		comment, err := s.repo.FindCommentAuthor(ctx, targetId)
+		if err != nil {
+			return reportTarget{}, notFound(err)
+		}
+
+		target.AuthorID = comment.UserID
+		target.TopicID = pgtype.Int8{Int64: comment.TopicID, Valid: true}
+		target.PostID = pgtype.Int8{Int64: comment.PostID, Valid: true}
+		target.CommentID = pgtype.Int8{Int64: comment.CommentID, Valid: true}
+	case TargetUser:
+		user, err := s.repo.FindUserByID(ctx, targetId)
+		if err != nil {
+			return reportTarget{}, notFound(err)
+		}
+
+		target.AuthorID = user.UserID
+	default:
+		return reportTarget{}, ErrTargetNotFound
+	}
+
+	return target, nil
+}
+
+// checkPermission returns nil if the user can moderate the topic, or if global is true, can
+// moderate every topic and user.
+func (s *svc) checkPermission(ctx context.Context, userId int64, topicId pgtype.Int8, global bool) error {
+	var allowed bool
+	var err error
+	if global {
+		allowed, err = s.roles.CanModerateAll(ctx, userId)
+	} else {
+		allowed, err = s.roles.CanModerateTopic(ctx, userId, topicId.Int64)
+	}
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrPermissionDenied
+	}
+
+	return nil
+}
+
+// checkSuspend returns ErrPermissionDenied if the moderator's global role does not allow them to
+// suspend the user.
+func (s *svc) checkSuspend(ctx context.Context, moderatorId int64, userId int64) error {
+	allowed, err := s.roles.CanSuspend(ctx, moderatorId, userId)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrPermissionDenied
+	}
+
+	return nil
+}
+
+// removeContent soft-deletes the reported post or comment on behalf of the moderator through qtx,
+// and records the deletion in the audit log unless the moderator is the author. Content that has
+// already been deleted is left as it is.
+func (s *svc) removeContent(ctx context.Context, qtx *repo.Queries, moderatorId int64, report repo.Report) error {
+	switch report.TargetType {
+	case TargetPost:
+		before, err := qtx.FindPostSnapshot(ctx, report.TargetID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return nil
+			}
+			return err
+		}
+
+		arg := repo.DeletePostParams{
+			PostID:    report.TargetID,
+			DeletedBy: pgtype.Int8{Int64: moderatorId, Valid: true},
+		}
+		delRows, err := qtx.DeletePost(ctx, arg)
+		if err != nil {
+			return err
+		}
+		if delRows == 0 || before.UserID == moderatorId {
+			return nil
+		}
+
+		return s.audit.Record(ctx, qtx, audit.Entry{
+			ActorID:    moderatorId,
+			Action:     audit.ActionPostDelete,
+			TargetType: audit.TargetPost,
+			TargetID:   report.TargetID,
+			TopicID:    before.TopicID,
+			Before:     before,
+		})
+	case TargetComment:
+		before, err := qtx.FindCommentSnapshot(ctx, report.TargetID)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return nil
+			}
+			return err
+		}
+
+		arg := repo.DeleteCommentParams{
+			CommentID: report.TargetID,
+			DeletedBy: pgtype.Int8{Int64: moderatorId, Valid: true},
+		}
+		delRows, err := qtx.DeleteComment(ctx, arg)
+		if err != nil {
+			return err
+		}
+		if delRows == 0 || before.UserID == moderatorId {
+			return nil
+		}
+
+		return s.audit.Record(ctx, qtx, audit.Entry{
+			ActorID:    moderatorId,
+			Action:     audit.ActionCommentDelete,
+			TargetType: audit.TargetComment,
+			TargetID:   report.TargetID,
+			TopicID:    report.TopicID.Int64,
+			Before:     before,
+		})
+	default:
+		return ErrInvalidAction
+	}
+}
+
+// warnAuthor creates a moderation warning notification for the author of the reported content
+// through qtx, with the moderator's note as its message.
+func (s *svc) warnAuthor(ctx context.Context, qtx *repo.Queries, moderatorId int64, report repo.Report, target reportTarget, note string) error {
+	message := note
+	if message == "" {
+		message = fmt.Sprintf("Your %s was reported for %s.", report.TargetType, reasonText(report.Reason))
+	}
+
+	arg := repo.CreateNotificationParams{
+		UserID:    target.AuthorID,
+		ActorID:   moderatorId,
+		Type:      notifications.TypeModerationWarning,
+		TopicID:   target.TopicID,
+		PostID:    target.PostID,
+		CommentID: target.CommentID,
+		Message:   message,
+	}
+	return qtx.CreateNotification(ctx, arg)
+}
+
+// suspensionReason returns the reason recorded with a suspension, which is the moderator's note or
+// the reason of the report.
+func suspensionReason(report repo.Report, note string) string {
+	if note != "" {
+		return note
+	}
+	return reasonText(report.Reason)
+}
+
+// reasonText returns the report reason in a readable form.
+func reasonText(reason string) string {
+	return strings.ReplaceAll(reason, "_", " ")
+}
+
+// targetKey returns the key that identifies the reported content of a queue item.
+func targetKey(targetType string, targetId int64) string {
+	return fmt.Sprintf("%s:%d", targetType, targetId)
+}
+
+// notFound maps a missing row to ErrTargetNotFound.
+func notFound(err error) error {
+	if err == pgx.ErrNoRows {
+		return ErrTargetNotFound
+	}
+	return err
+}
@@ -0,0 +1,113 @@
+package reports
+
+import (
+	"context"
+	"time"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Types of content that can be reported.
+const (
+	TargetPost    = "post"
+	TargetComment = "comment"
+	TargetUser    = "user"
+)
+
+// Statuses of a report. A report is open until a moderator resolves it, after which it is
+// dismissed or actioned.
+const (
+	StatusOpen      = "open"
+	StatusDismissed = "dismissed"
+	StatusActioned  = "actioned"
+)
+
+// Actions a moderator can take to resolve a report.
+const (
+	ActionDismiss       = "dismiss"
+	ActionRemoveContent = "remove_content"
+	ActionWarnAuthor    = "warn_author"
+	ActionSuspendAuthor = "suspend_author"
+)
+
+// Service defines the domain logic for report related operations.
+// It is responsible for enforcing application rules and making database calls.
+type Service interface {
+	CreateReport(ctx context.Context, arg repo.CreateReportParams) (repo.Report, error)
+	ListQueue(ctx context.Context, moderatorId int64, page helper.Page) ([]QueueItem, api.PageMeta, error)
+	ResolveReport(ctx context.Context, moderatorId int64, reportId int64, req ResolveReportRequest) (Resolution, error)
+}
+
+// QueueItem model that is passed to the frontend for a reported post, comment or user in the
+// moderation queue. It groups all open reports of the content, and Reasons counts them by reason.
+type QueueItem struct {
+	TargetType      string         `json:"target_type"`
+	TargetID        int64          `json:"target_id"`
+	TopicID         *int64         `json:"topic_id,omitempty"`
+	ReportCount     int64          `json:"report_count"`
+	Reasons         map[string]int `json:"reasons"`
+	Reports         []Report       `json:"reports"`
+	FirstReportedAt time.Time      `json:"first_reported_at"`
+	LastReportedAt  time.Time      `json:"last_reported_at"`
+}
+
+// Report model that is passed to the frontend for a single open report in the moderation queue.
+type Report struct {
+	ReportID     int64     `json:"report_id"`
+	ReporterID   int64     `json:"reporter_id"`
+	ReporterName string    `json:"reporter_name"`
+	Reason       string    `json:"reason"`
+	Details      string    `json:"details"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Resolution model that is passed to the frontend after a report is resolved. Every open report of
+// the same content is resolved together, and ResolvedReports counts them. SuspendedUntil is only
+// set when the author was suspended.
+type Resolution struct {
+	TargetType      string     `json:"target_type"`
+	TargetID        int64      `json:"target_id"`
+	Status          string     `json:"status"`
+	Action          string     `json:"action"`
+	ResolvedBy      int64      `json:"resolved_by"`
+	ResolvedReports int64      `json:"resolved_reports"`
+	SuspendedUntil  *time.Time `json:"suspended_until,omitempty"`
+}
+
+// CreateReportRequest handles the report related HTTP request body for reporting a post, comment
+// or user.
+type CreateReportRequest struct {
+	TargetType string `json:"targetType" validate:"required,oneof=post comment user"`
+	TargetID   int64  `json:"targetId" validate:"required,min=1"`
+	Reason     string `json:"reason" validate:"required,oneof=spam harassment hate_speech violence sexual_content misinformation off_topic other"`
+	Details    string `json:"details" validate:"max=1000"`
+}
+
+// ResolveReportRequest handles the report related HTTP request body for resolving a report. A
+// suspension lasts for SuspendDays and only applies to the topic of the reported content, unless
+// Global is set by an admin or a global moderator.
+type ResolveReportRequest struct {
+	Action      string `json:"action" validate:"required,oneof=dismiss remove_content warn_author suspend_author"`
+	Note        string `json:"note" validate:"max=1000"`
+	SuspendDays int    `json:"suspendDays" validate:"required_if=Action suspend_author,omitempty,min=1,max=3650"`
+	Global      bool   `json:"global"`
+}
+
+// reportTarget identifies the author and location of the reported content.
+type reportTarget struct {
+	AuthorID  int64
+	TopicID   pgtype.Int8
+	PostID    pgtype.Int8
+	CommentID pgtype.Int8
+}
+
+// queueCursor holds the sort keys of the last item in a page of the moderation queue, which is
+// encoded into the opaque next_cursor string.
+type queueCursor struct {
+	FirstReportedAt time.Time `json:"first_reported_at"`
+	TargetType      string    `json:"target_type"`
+	TargetID        int64     `json:"target_id"`
+}
@@ -0,0 +1,10 @@
+package roles
+
+import "errors"
+
+var (
+	ErrUserNotFound        = errors.New("user not found")
+	ErrTopicNotFound       = errors.New("topic not found")
+	ErrModeratorNotFound   = errors.New("moderator not found")
+	ErrCannotChangeOwnRole = errors.New("cannot change own role")
+)
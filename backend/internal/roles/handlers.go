@@ -0,0 +1,213 @@
+package roles
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+)
+
+const (
+	InvalidUserIdMessage             = "Invalid user id"
+	InvalidTopicIdMessage            = "Invalid topic id"
+	MissingUserIDMessage             = "Missing userID"
+	SuccessfulSetUserRoleMessage     = "Successfully changed user role"
+	SuccessfulListModeratorsMessage  = "Successfully listed all moderators"
+	SuccessfulAddModeratorMessage    = "Successfully added moderator"
+	SuccessfulRemoveModeratorMessage = "Successfully removed moderator"
+)
+
+// handler handles the role related HTTP requests.
+// It is responsible for translating HTTP requests into service calls and formatting service
+// responses into HTTP responses.
+type handler struct {
+	service Service
+}
+
+// NewHandler creates a new role handler.
+func NewHandler(service Service) *handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// SetUserRole handles PUT /api/admin/users/{id}/role requests.
+// It parses the id string, reads and validates the request body, and passes it to the role service
+// to change the global role of the user. Admins cannot change their own role, so that the last
+// admin cannot lock themselves out. It then serializes the result into a JSON HTTP response.
+func (h *handler) SetUserRole(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidUserIdMessage, http.StatusBadRequest, api.CodeInvalidUserID)
+		return
+	}
+
+	var req SetUserRoleRequest
+	err = helper.Read(r, &req)
+	if err != nil {
+		helper.WriteError(w, err.Error(), http.StatusBadRequest, api.CodeMalformedBody)
+		return
+	}
+
+	err = helper.Validate(req)
+	if err != nil {
+		helper.WriteValidationError(w, err)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	if id == userId {
+		helper.WriteError(w, ErrCannotChangeOwnRole.Error(), http.StatusBadRequest, api.CodeCannotChangeOwnRole)
+		return
+	}
+
+	user, err := h.service.SetUserRole(r.Context(), userId, id, req.Role)
+	if err != nil {
+		if err == ErrUserNotFound {
+			helper.WriteError(w, ErrUserNotFound.Error(), http.StatusNotFound, api.CodeUserNotFound)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonUser, err := json.Marshal(user)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonUser, SuccessfulSetUserRoleMessage)
+	helper.Write(w, response)
+}
+
+// ListTopicModerators handles GET /api/admin/topics/{topicId}/moderators requests.
+// It parses the topicId string, and passes it to the role service to return all moderators of
+// that topic, and serializes the result into a JSON HTTP response.
+func (h *handler) ListTopicModerators(w http.ResponseWriter, r *http.Request) {
+	topicIdStr := chi.URLParam(r, "topicId")
+	topicId, err := strconv.ParseInt(topicIdStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidTopicIdMessage, http.StatusBadRequest, api.CodeInvalidTopicID)
+		return
+	}
+
+	moderators, err := h.service.ListTopicModerators(r.Context(), topicId)
+	if err != nil {
+		if err == ErrTopicNotFound {
+			helper.WriteError(w, ErrTopicNotFound.Error(), http.StatusNotFound, api.CodeTopicNotFound)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonModerators, err := json.Marshal(moderators)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonModerators, SuccessfulListModeratorsMessage)
+	helper.Write(w, response)
+}
+
+// AddTopicModerator handles POST /api/admin/topics/{topicId}/moderators requests.
+// It parses the topicId string, reads and validates the request body, and passes it to the role
+// service to appoint the user as a moderator of that topic. It then serializes the result into a
+// JSON HTTP response.
+func (h *handler) AddTopicModerator(w http.ResponseWriter, r *http.Request) {
+	topicIdStr := chi.URLParam(r, "topicId")
+	topicId, err := strconv.ParseInt(topicIdStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidTopicIdMessage, http.StatusBadRequest, api.CodeInvalidTopicID)
+		return
+	}
+
+	var req AddTopicModeratorRequest
+	err = helper.Read(r, &req)
+	if err != nil {
+		helper.WriteError(w, err.Error(), http.StatusBadRequest, api.CodeMalformedBody)
+		return
+	}
+
+	err = helper.Validate(req)
+	if err != nil {
+		helper.WriteValidationError(w, err)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	err = h.service.AddTopicModerator(r.Context(), userId, topicId, req.UserID)
+	if err != nil {
+		if err == ErrTopicNotFound {
+			helper.WriteError(w, ErrTopicNotFound.Error(), http.StatusNotFound, api.CodeTopicNotFound)
+			return
+		}
+		if err == ErrUserNotFound {
+			helper.WriteError(w, ErrUserNotFound.Error(), http.StatusNotFound, api.CodeUserNotFound)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseMessage(SuccessfulAddModeratorMessage)
+	helper.Write(w, response)
+}
+
+// RemoveTopicModerator handles DELETE /api/admin/topics/{topicId}/moderators/{userId} requests.
+// It parses the topicId and userId string, and passes it to the role service to remove the user
+// from the moderators of that topic, which then serializes the result into a JSON HTTP response.
+func (h *handler) RemoveTopicModerator(w http.ResponseWriter, r *http.Request) {
+	topicIdStr := chi.URLParam(r, "topicId")
+	topicId, err := strconv.ParseInt(topicIdStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidTopicIdMessage, http.StatusBadRequest, api.CodeInvalidTopicID)
+		return
+	}
+
+	userIdStr := chi.URLParam(r, "userId")
+	userId, err := strconv.ParseInt(userIdStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidUserIdMessage, http.StatusBadRequest, api.CodeInvalidUserID)
+		return
+	}
+
+	adminId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	err = h.service.RemoveTopicModerator(r.Context(), adminId, topicId, userId)
+	if err != nil {
+		if err == ErrModeratorNotFound {
+			helper.WriteError(w, ErrModeratorNotFound.Error(), http.StatusNotFound, api.CodeModeratorNotFound)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseMessage(SuccessfulRemoveModeratorMessage)
+	helper.Write(w, response)
+}
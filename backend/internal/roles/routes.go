@@ -0,0 +1,15 @@
+package roles
+
+import "github.com/go-chi/chi/v5"
+
+// AdminRoutes group all role management HTTP endpoints together. They must be mounted under the
+// admin prefix path, behind a middleware that only lets admins through.
+// It connects the URLS to their respective handler methods.
+func AdminRoutes(router chi.Router, h *handler) {
+	router.Put("/users/{id}/role", h.SetUserRole)
+	router.Route("/topics/{topicId}/moderators", func(r chi.Router) {
+		r.Get("/", h.ListTopicModerators)
+		r.Post("/", h.AddTopicModerator)
+		r.Delete("/{userId}", h.RemoveTopicModerator)
+	})
+}
@@ -0,0 +1,273 @@
+package roles
+
+import (
+	"context"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/audit"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+	"github.com/haobuhaoo/gossip-with-go/internal/users"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// svc implements the Service interface.
+// It depends on the sql generated Queries type to interact with the PostgreSQL database, and on
+// the audit log service to record role changes.
+type svc struct {
+	repo  *repo.Queries
+	db    *pgxpool.Pool
+	audit audit.Service
+}
+
+// NewService creates a new role service.
+func NewService(repo *repo.Queries, db *pgxpool.Pool, audit audit.Service) Service {
+	return &svc{
+		repo:  repo,
+		db:    db,
+		audit: audit,
+	}
+}
+
+// IsAdmin returns true if the user holds the global admin role.
+func (s *svc) IsAdmin(ctx context.Context, userId int64) (bool, error) {
+	user, err := s.repo.FindUserByID(ctx, userId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return user.Role == Admin, nil
+}
+
+// CanModerateTopic returns true if the user is an admin, a global moderator or a moderator of the
+// given topic.
+func (s *svc) CanModerateTopic(ctx context.Context, userId int64, topicId int64) (bool, error) {
+	arg := repo.CanModerateTopicParams{
+		UserID:  userId,
+		TopicID: topicId,
+	}
+	return s.repo.CanModerateTopic(ctx, arg)
+}
+
+// CanModerateAll returns true if the user is an admin or a global moderator, who can moderate
+// every topic and every user.
+func (s *svc) CanModerateAll(ctx context.Context, userId int64) (bool, error) {
+	user, err := s.repo.FindUserByID(ctx, userId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return user.Role == Admin || user.Role == Moderator, nil
+}
+
+// CanSuspend returns true if the moderator's global role allows them to suspend the user. Admins
+// can never be suspended, and no one can suspend a user whose global role outranks their own, so
+// only admins can suspend global moderators.
+func (s *svc) CanSuspend(ctx context.Context, moderatorId int64, userId int64) (bool, error) {
+	moderator, err := s.repo.FindUserByID(ctx, moderatorId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+
+	user, err := s.repo.FindUserByID(ctx, userId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return user.Role != Admin && rank(user.Role) <= rank(moderator.Role), nil
+}
+
+// rank returns the position of the global role in the role hierarchy, where a higher rank
+// outranks a lower one.
+func rank(role string) int {
+	switch role {
+	case Admin:
+		return 2
+	case Moderator:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// SetUserRole changes the global role of the user and returns the updated user. It also revokes
+// the user's access tokens, so that the role carried in their JWT claims is refreshed. The change
+// is recorded in the audit log with the admin who made it.
+// If there is an error in between, the whole transaction is rolled back.
+func (s *svc) SetUserRole(ctx context.Context, adminId int64, userId int64, role string) (users.User, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return users.User{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.repo.WithTx(tx)
+
+	existing, err := qtx.FindUserByID(ctx, userId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return users.User{}, ErrUserNotFound
+		}
+		return users.User{}, err
+	}
+
+	arg := repo.UpdateUserRoleParams{
+		UserID: userId,
+		Role:   role,
+	}
+	user, err := qtx.UpdateUserRole(ctx, arg)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return users.User{}, ErrUserNotFound
+		}
+		return users.User{}, err
+	}
+
+	err = qtx.RevokeUserTokens(ctx, userId)
+	if err != nil {
+		return users.User{}, err
+	}
+
+	err = s.audit.Record(ctx, qtx, audit.Entry{
+		ActorID:    adminId,
+		Action:     audit.ActionRoleUpdate,
+		TargetType: audit.TargetUser,
+		TargetID:   userId,
+		Before:     users.ToUser(existing),
+		After:      users.ToUser(user),
+	})
+	if err != nil {
+		return users.User{}, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return users.User{}, err
+	}
+
+	return users.ToUser(user), nil
+}
+
+// ListTopicModerators returns all moderators of the given topic.
+func (s *svc) ListTopicModerators(ctx context.Context, topicId int64) ([]TopicModerator, error) {
+	_, err := s.repo.FindTopicByID(ctx, topicId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return []TopicModerator{}, ErrTopicNotFound
+		}
+		return []TopicModerator{}, err
+	}
+
+	rows, err := s.repo.ListTopicModerators(ctx, topicId)
+	if err != nil {
+		return []TopicModerator{}, err
+	}
+
+	moderators := make([]TopicModerator, 0, len(rows))
+	for _, row := range rows {
+		moderators = append(moderators, TopicModerator{
+			TopicID:   row.TopicID,
+			UserID:    row.UserID,
+			Username:  row.Username,
+			CreatedAt: row.CreatedAt.Time,
+		})
+	}
+	return moderators, nil
+}
+
+// AddTopicModerator appoints the user as a moderator of the given topic, and records it in the
+// audit log with the admin who made the appointment.
+func (s *svc) AddTopicModerator(ctx context.Context, adminId int64, topicId int64, userId int64) error {
+	_, err := s.repo.FindTopicByID(ctx, topicId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrTopicNotFound
+		}
+		return err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.repo.WithTx(tx)
+
+	arg := repo.AddTopicModeratorParams{
+		TopicID: topicId,
+		UserID:  userId,
+	}
+	err = qtx.AddTopicModerator(ctx, arg)
+	if err != nil {
+		if helper.IsForeignKeyViolation(err) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	err = s.audit.Record(ctx, qtx, audit.Entry{
+		ActorID:    adminId,
+		Action:     audit.ActionModeratorAdd,
+		TargetType: audit.TargetUser,
+		TargetID:   userId,
+		TopicID:    topicId,
+		After:      arg,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RemoveTopicModerator removes the user from the moderators of the given topic, and records it in
+// the audit log with the admin who removed them.
+func (s *svc) RemoveTopicModerator(ctx context.Context, adminId int64, topicId int64, userId int64) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.repo.WithTx(tx)
+
+	arg := repo.RemoveTopicModeratorParams{
+		TopicID: topicId,
+		UserID:  userId,
+	}
+	delRows, err := qtx.RemoveTopicModerator(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	if delRows == 0 {
+		return ErrModeratorNotFound
+	}
+
+	err = s.audit.Record(ctx, qtx, audit.Entry{
+		ActorID:    adminId,
+		Action:     audit.ActionModeratorRemove,
+		TargetType: audit.TargetUser,
+		TargetID:   userId,
+		TopicID:    topicId,
+		Before:     arg,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,49 @@
+package roles
+
+import (
+	"context"
+	"time"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/users"
+)
+
+// Global roles a user can hold. Admins can do everything, moderators can moderate the content of
+// every topic and members can only manage their own content.
+const (
+	Admin     = "admin"
+	Moderator = "moderator"
+	Member    = "member"
+)
+
+// Service defines the domain logic for role related operations.
+// It is responsible for enforcing application rules and making database calls.
+type Service interface {
+	IsAdmin(ctx context.Context, userId int64) (bool, error)
+	CanModerateTopic(ctx context.Context, userId int64, topicId int64) (bool, error)
+	CanModerateAll(ctx context.Context, userId int64) (bool, error)
+	CanSuspend(ctx context.Context, moderatorId int64, userId int64) (bool, error)
+	SetUserRole(ctx context.Context, adminId int64, userId int64, role string) (users.User, error)
+	ListTopicModerators(ctx context.Context, topicId int64) ([]TopicModerator, error)
+	AddTopicModerator(ctx context.Context, adminId int64, topicId int64, userId int64) error
+	RemoveTopicModerator(ctx context.Context, adminId int64, topicId int64, userId int64) error
+}
+
+// TopicModerator model that is passed to the frontend.
+type TopicModerator struct {
+	TopicID   int64     `json:"topic_id"`
+	UserID    int64     `json:"user_id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SetUserRoleRequest handles the role related HTTP request body for changing the global role of a
+// user.
+type SetUserRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=admin moderator member"`
+}
+
+// AddTopicModeratorRequest handles the role related HTTP request body for appointing a moderator
+// to a topic.
+type AddTopicModeratorRequest struct {
+	UserID int64 `json:"userId" validate:"required,min=1"`
+}
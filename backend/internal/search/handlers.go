@@ -0,0 +1,158 @@
+package search
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	InvalidQueryMessage     = "Query string missing"
+	InvalidTypeMessage      = "Invalid result type"
+	InvalidTopicIdMessage   = "Invalid topic id"
+	InvalidDateMessage      = "Invalid date, expected YYYY-MM-DD or RFC 3339"
+	InvalidMinScoreMessage  = "Invalid minimum score"
+	InvalidPageMessage      = "Invalid limit or cursor"
+	SuccessfulSearchMessage = "Successfully searched"
+)
+
+// dateLayout is the layout of a date-only filter value.
+const dateLayout = "2006-01-02"
+
+// handler handles the search related HTTP requests.
+// It is responsible for translating HTTP requests into service calls and formatting service
+// responses into HTTP responses.
+type handler struct {
+	service Service
+}
+
+// NewHandler creates a new search handler.
+func NewHandler(service Service) *handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// Search handles GET /api/search requests.
+// It parses the query string together with the optional type, author, topic, from, to, min_score,
+// limit and cursor query strings, and passes them to the search service to search across topics,
+// posts, comments and users. A date-only `to` value includes the whole day. It then serializes the
+// page of ranked results and its pagination metadata into a JSON HTTP response.
+func (h *handler) Search(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	arg := repo.SearchParams{
+		Query: values.Get("q"),
+	}
+	if arg.Query == "" {
+		helper.WriteError(w, InvalidQueryMessage, http.StatusBadRequest, api.CodeInvalidQuery)
+		return
+	}
+
+	if resultType := values.Get("type"); resultType != "" {
+		switch resultType {
+		case TypeTopic, TypePost, TypeComment, TypeUser:
+			arg.ResultType = pgtype.Text{String: resultType, Valid: true}
+		default:
+			helper.WriteError(w, InvalidTypeMessage, http.StatusBadRequest, api.CodeInvalidType)
+			return
+		}
+	}
+
+	if author := values.Get("author"); author != "" {
+		arg.Author = pgtype.Text{String: author, Valid: true}
+	}
+
+	if topicStr := values.Get("topic"); topicStr != "" {
+		topicId, err := strconv.ParseInt(topicStr, 10, 64)
+		if err != nil {
+			helper.WriteError(w, InvalidTopicIdMessage, http.StatusBadRequest, api.CodeInvalidTopicID)
+			return
+		}
+		arg.TopicID = pgtype.Int8{Int64: topicId, Valid: true}
+	}
+
+	if fromStr := values.Get("from"); fromStr != "" {
+		from, _, err := parseDate(fromStr)
+		if err != nil {
+			helper.WriteError(w, InvalidDateMessage, http.StatusBadRequest, api.CodeInvalidDate)
+			return
+		}
+		arg.CreatedAfter = pgtype.Timestamptz{Time: from, Valid: true}
+	}
+
+	if toStr := values.Get("to"); toStr != "" {
+		to, dateOnly, err := parseDate(toStr)
+		if err != nil {
+			helper.WriteError(w, InvalidDateMessage, http.StatusBadRequest, api.CodeInvalidDate)
+			return
+		}
+
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		arg.CreatedBefore = pgtype.Timestamptz{Time: to, Valid: true}
+	}
+
+	if minScoreStr := values.Get("min_score"); minScoreStr != "" {
+		minScore, err := strconv.ParseInt(minScoreStr, 10, 64)
+		if err != nil {
+			helper.WriteError(w, InvalidMinScoreMessage, http.StatusBadRequest, api.CodeInvalidMinScore)
+			return
+		}
+		arg.MinScore = pgtype.Int8{Int64: minScore, Valid: true}
+	}
+
+	page, err := helper.ReadPage(r)
+	if err != nil {
+		helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+		return
+	}
+
+	results, meta, err := h.service.Search(r.Context(), arg, page)
+	if err != nil {
+		if err == helper.ErrInvalidCursor {
+			helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonResult, err := json.Marshal(results)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonMeta, err := json.Marshal(meta)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataMetaAndMessage(jsonResult, jsonMeta, SuccessfulSearchMessage)
+	helper.Write(w, response)
+}
+
+// parseDate parses a date filter value in either the YYYY-MM-DD or the RFC 3339 format. It also
+// reports whether the value was a date without a time.
+func parseDate(value string) (time.Time, bool, error) {
+	date, err := time.Parse(dateLayout, value)
+	if err == nil {
+		return date, true, nil
+	}
+
+	date, err = time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return date, false, nil
+}
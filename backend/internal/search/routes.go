@@ -0,0 +1,11 @@
+package search
+
+import "github.com/go-chi/chi/v5"
+
+// Routes group all search related HTTP endpoints together, with the base prefix path /search.
+// It connects the URLS to their respective handler methods.
+func Routes(router chi.Router, h *handler) {
+	router.Route("/search", func(r chi.Router) {
+		r.Get("/", h.Search)
+	})
+}
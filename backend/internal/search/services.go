@@ -0,0 +1,114 @@
+package search
+
+import (
+	"context"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// svc implements the Service interface.
+// It depends on the sql generated Queries type to interact with the PostgreSQL database.
+type svc struct {
+	repo *repo.Queries
+}
+
+// NewService creates a new search service.
+func NewService(repo *repo.Queries) Service {
+	return &svc{
+		repo: repo,
+	}
+}
+
+// Search runs a search across topics, posts, comments and users that match the query and the
+// filters in arg. Topics, posts and comments are matched with full-text search, while users are
+// matched by a case-insensitive substring of their name. It returns a page of results ordered by
+// relevance, together with the pagination metadata.
+func (s *svc) Search(ctx context.Context, arg repo.SearchParams, page helper.Page) ([]Result, api.PageMeta, error) {
+	if page.Cursor != "" {
+		var c searchCursor
+		err := helper.DecodeCursor(page.Cursor, &c)
+		if err != nil {
+			return []Result{}, api.PageMeta{}, err
+		}
+
+		arg.CursorRank = pgtype.Float4{Float32: c.Rank, Valid: true}
+		arg.CursorType = pgtype.Text{String: c.Type, Valid: true}
+		arg.CursorID = pgtype.Int8{Int64: c.ID, Valid: true}
+	}
+	arg.PageLimit = page.Limit + 1
+
+	rows, err := s.repo.Search(ctx, arg)
+	if err != nil {
+		return []Result{}, api.PageMeta{}, err
+	}
+
+	countArg := repo.CountSearchParams{
+		Query:         arg.Query,
+		ResultType:    arg.ResultType,
+		Author:        arg.Author,
+		TopicID:       arg.TopicID,
+		CreatedAfter:  arg.CreatedAfter,
+		CreatedBefore: arg.CreatedBefore,
+		MinScore:      arg.MinScore,
+	}
+	total, err := s.repo.CountSearch(ctx, countArg)
+	if err != nil {
+		return []Result{}, api.PageMeta{}, err
+	}
+
+	fetched := len(rows)
+	if fetched > int(page.Limit) {
+		rows = rows[:page.Limit]
+	}
+
+	results := make([]Result, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, toResult(row))
+	}
+
+	nextCursor := ""
+	if len(results) > 0 {
+		last := results[len(results)-1]
+		nextCursor = helper.EncodeCursor(searchCursor{
+			Rank: last.Rank,
+			Type: last.Type,
+			ID:   last.ID,
+		})
+	}
+
+	return results, helper.NewPageMeta(fetched, page, total, nextCursor), nil
+}
+
+// toResult converts a search row into the Result model.
+func toResult(row repo.SearchRow) Result {
+	result := Result{
+		Type:     row.ResultType,
+		ID:       row.ResultID,
+		UserID:   row.UserID,
+		Username: row.Username,
+		Title:    row.Title.String,
+		Headline: helper.SanitizeHeadline(row.Headline),
+		Score:    row.Score,
+		Rank:     row.Rank,
+	}
+
+	if row.TopicID.Valid {
+		topicId := row.TopicID.Int64
+		result.TopicID = &topicId
+	}
+
+	if row.PostID.Valid {
+		postId := row.PostID.Int64
+		result.PostID = &postId
+	}
+
+	if row.CreatedAt.Valid {
+		createdAt := row.CreatedAt.Time
+		result.CreatedAt = &createdAt
+	}
+
+	return result
+}
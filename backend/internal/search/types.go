@@ -0,0 +1,51 @@
+package search
+
+import (
+	"context"
+	"time"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+)
+
+// Types of items that a search can return.
+const (
+	TypeTopic   = "topic"
+	TypePost    = "post"
+	TypeComment = "comment"
+	TypeUser    = "user"
+)
+
+// Service defines the domain logic for search related operations.
+// It is responsible for enforcing application rules and making database calls.
+type Service interface {
+	Search(ctx context.Context, arg repo.SearchParams, page helper.Page) ([]Result, api.PageMeta, error)
+}
+
+// Result model that is passed to the frontend for an item that matches a search query.
+// ID is the id of the item of the given type. TopicID and PostID locate the item within the forum
+// and are omitted when they do not apply, and Title is the title of the topic or post the item
+// belongs to. The headline is a snippet of the content with the matched terms wrapped in <mark>
+// tags, and Score is the number of likes minus dislikes of a post or comment.
+type Result struct {
+	Type      string     `json:"type"`
+	ID        int64      `json:"id"`
+	TopicID   *int64     `json:"topic_id,omitempty"`
+	PostID    *int64     `json:"post_id,omitempty"`
+	UserID    int64      `json:"user_id"`
+	Username  string     `json:"username"`
+	Title     string     `json:"title,omitempty"`
+	Headline  string     `json:"headline"`
+	Score     int64      `json:"score"`
+	Rank      float32    `json:"rank"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// searchCursor holds the sort keys of the last result in a page, which is encoded into the opaque
+// next_cursor string.
+type searchCursor struct {
+	Rank float32 `json:"rank"`
+	Type string  `json:"type"`
+	ID   int64   `json:"id"`
+}
@@ -0,0 +1,9 @@
+package storage
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("file not found")
+	ErrInvalidKey    = errors.New("invalid storage key")
+	ErrUnknownDriver = errors.New("unknown storage driver")
+)
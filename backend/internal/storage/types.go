@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// Drivers of the storage backends that can be configured.
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// Storage stores the files of attachments under opaque keys.
+// It is implemented by a local filesystem backend and an S3-compatible backend, so that where the
+// files are kept can be changed without touching the attachment service.
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Config selects and configures the storage backend. Dir is only used by the local backend, and
+// the S3 fields are only used by the S3-compatible backend.
+type Config struct {
+	Driver      string
+	Dir         string
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3UseSSL    bool
+}
+
+// New creates the storage backend selected by the driver of cfg.
+func New(ctx context.Context, cfg Config) (Storage, error) {
+	switch cfg.Driver {
+	case DriverLocal:
+		return NewLocal(cfg.Dir)
+	case DriverS3:
+		return NewS3(ctx, cfg)
+	default:
+		return nil, ErrUnknownDriver
+	}
+}
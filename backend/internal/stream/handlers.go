@@ -0,0 +1,115 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+)
+
+const (
+	InvalidTopicIdMessage       = "Invalid topic id"
+	InvalidPostIdMessage        = "Invalid post id"
+	StreamingUnsupportedMessage = "Streaming unsupported"
+)
+
+// heartbeatInterval is how often a comment line is sent to keep idle connections open.
+const heartbeatInterval = 15 * time.Second
+
+// handler handles the stream related HTTP requests.
+// It is responsible for translating HTTP requests into service calls and formatting service
+// responses into HTTP responses.
+type handler struct {
+	service Service
+}
+
+// NewHandler creates a new stream handler.
+func NewHandler(service Service) *handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// Stream handles GET /api/stream requests.
+// It parses the optional topic and post query strings, and subscribes to the stream service for
+// the events of that topic or post. It then pushes each event to the client as a Server-Sent Event
+// named after the event type, until the client disconnects.
+func (h *handler) Stream(w http.ResponseWriter, r *http.Request) {
+	var filter Filter
+
+	if topicStr := r.URL.Query().Get("topic"); topicStr != "" {
+		topicId, err := strconv.ParseInt(topicStr, 10, 64)
+		if err != nil {
+			helper.WriteError(w, InvalidTopicIdMessage, http.StatusBadRequest, api.CodeInvalidTopicID)
+			return
+		}
+		filter.TopicID = topicId
+	}
+
+	if postStr := r.URL.Query().Get("post"); postStr != "" {
+		postId, err := strconv.ParseInt(postStr, 10, 64)
+		if err != nil {
+			helper.WriteError(w, InvalidPostIdMessage, http.StatusBadRequest, api.CodeInvalidPostID)
+			return
+		}
+		filter.PostID = postId
+	}
+
+	rc := http.NewResponseController(w)
+	err := rc.SetWriteDeadline(time.Time{})
+	if err != nil {
+		helper.WriteError(w, StreamingUnsupportedMessage, http.StatusInternalServerError, api.CodeStreamingUnsupported)
+		return
+	}
+
+	events, unsubscribe := h.service.Subscribe(filter)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	err = rc.Flush()
+	if err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			if err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(w, ": heartbeat\n\n")
+			if err != nil {
+				return
+			}
+		}
+
+		err = rc.Flush()
+		if err != nil {
+			return
+		}
+	}
+}
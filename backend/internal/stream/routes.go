@@ -0,0 +1,11 @@
+package stream
+
+import "github.com/go-chi/chi/v5"
+
+// Routes group all stream related HTTP endpoints together, with the base prefix path /stream.
+// It connects the URLS to their respective handler methods.
+func Routes(router chi.Router, h *handler) {
+	router.Route("/stream", func(r chi.Router) {
+		r.Get("/", h.Stream)
+	})
+}
@@ -0,0 +1,162 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// subscriberBuffer is the number of events buffered for each client. Events for a client that
+	// falls further behind are dropped.
+	subscriberBuffer = 32
+
+	// reconnectDelay is how long Listen waits before listening again after losing its connection.
+	reconnectDelay = 5 * time.Second
+)
+
+// subscriber is a client that receives the events selected by its filter.
+type subscriber struct {
+	filter Filter
+	events chan Event
+}
+
+// svc implements the Service interface.
+// It depends on the sql generated Queries type to publish events, and on the connection pool to
+// hold a dedicated connection that listens for them.
+type svc struct {
+	repo        *repo.Queries
+	db          *pgxpool.Pool
+	mu          sync.RWMutex
+	subscribers map[*subscriber]struct{}
+}
+
+// NewService creates a new stream service.
+func NewService(repo *repo.Queries, db *pgxpool.Pool) Service {
+	return &svc{
+		repo:        repo,
+		db:          db,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// Publish sends the event to every server instance through PostgreSQL NOTIFY.
+func (s *svc) Publish(ctx context.Context, event Event) error {
+	event.CreatedAt = time.Now()
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.PublishEvent(ctx, string(payload))
+}
+
+// Subscribe registers a client for the events selected by the filter. It returns the channel the
+// events are delivered on, and a function that unregisters the client and closes the channel.
+func (s *svc) Subscribe(filter Filter) (<-chan Event, func()) {
+	sub := &subscriber{
+		filter: filter,
+		events: make(chan Event, subscriberBuffer),
+	}
+
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+
+	unsubscribe := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if _, ok := s.subscribers[sub]; ok {
+			delete(s.subscribers, sub)
+			close(sub.events)
+		}
+	}
+
+	return sub.events, unsubscribe
+}
+
+// Close unregisters every client and closes their channels, which ends their streams so that the
+// server does not wait for them when it shuts down.
+func (s *svc) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sub := range s.subscribers {
+		delete(s.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// Listen holds a connection from the pool that listens on the events channel, and fans every
+// event out to the subscribed clients. It listens again after losing the connection, and blocks
+// until the context is cancelled.
+func (s *svc) Listen(ctx context.Context) {
+	for {
+		err := s.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("stream listener stopped: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// listen acquires a connection and fans out the events received on it until an error occurs.
+func (s *svc) listen(ctx context.Context) error {
+	conn, err := s.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{Channel}.Sanitize())
+	if err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var event Event
+		err = json.Unmarshal([]byte(notification.Payload), &event)
+		if err != nil {
+			log.Printf("invalid stream event: %v", err)
+			continue
+		}
+
+		s.broadcast(event)
+	}
+}
+
+// broadcast delivers the event to every subscribed client whose filter selects it, without
+// waiting for clients that are not keeping up.
+func (s *svc) broadcast(event Event) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for sub := range s.subscribers {
+		if !sub.filter.Matches(event) {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+		}
+	}
+}
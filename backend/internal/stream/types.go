@@ -0,0 +1,62 @@
+package stream
+
+import (
+	"context"
+	"time"
+)
+
+// Channel is the PostgreSQL channel that events are published on. Every server instance listens
+// on it, so that clients receive events from all instances.
+const Channel = "forum_events"
+
+// Types of events that are pushed to the clients.
+const (
+	TypePostCreated    = "post_created"
+	TypePostUpdated    = "post_updated"
+	TypePostVoted      = "post_voted"
+	TypeCommentCreated = "comment_created"
+	TypeCommentUpdated = "comment_updated"
+	TypeCommentVoted   = "comment_voted"
+)
+
+// Service defines the domain logic for real-time updates.
+// It is responsible for publishing events to every server instance and fanning them out to the
+// subscribed clients.
+type Service interface {
+	Publish(ctx context.Context, event Event) error
+	Subscribe(filter Filter) (<-chan Event, func())
+	Listen(ctx context.Context)
+	Close()
+}
+
+// Event model that is pushed to the frontend. It only identifies the changed content, so that
+// clients fetch the content themselves with their own permissions. Likes and Dislikes hold the new
+// vote counts of a vote event.
+type Event struct {
+	Type      string    `json:"type"`
+	TopicID   int64     `json:"topic_id"`
+	PostID    int64     `json:"post_id"`
+	CommentID *int64    `json:"comment_id,omitempty"`
+	UserID    int64     `json:"user_id"`
+	Likes     *int64    `json:"likes,omitempty"`
+	Dislikes  *int64    `json:"dislikes,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Filter selects the events that a client subscribes to. A zero TopicID or PostID matches every
+// topic or post.
+type Filter struct {
+	TopicID int64
+	PostID  int64
+}
+
+// Matches returns true if the event is selected by the filter.
+func (f Filter) Matches(event Event) bool {
+	if f.TopicID != 0 && f.TopicID != event.TopicID {
+		return false
+	}
+	if f.PostID != 0 && f.PostID != event.PostID {
+		return false
+	}
+	return true
+}
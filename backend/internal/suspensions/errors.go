@@ -0,0 +1,11 @@
+package suspensions
+
+import "errors"
+
+var (
+	ErrSuspensionNotFound = errors.New("suspension not found")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrTopicNotFound      = errors.New("topic not found")
+	ErrPermissionDenied   = errors.New("permission denied")
+	ErrCannotSuspendSelf  = errors.New("cannot suspend yourself")
+)
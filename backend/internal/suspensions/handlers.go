@@ -0,0 +1,170 @@
+package suspensions
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+)
+
+const (
+	InvalidSuspensionIdMessage        = "Invalid suspension id"
+	InvalidPageMessage                = "Invalid limit or cursor"
+	MissingUserIDMessage              = "Missing userID"
+	SuccessfulCreateSuspensionMessage = "Successfully suspended user"
+	SuccessfulListSuspensionsMessage  = "Successfully listed all active suspensions"
+	SuccessfulLiftSuspensionMessage   = "Successfully lifted suspension"
+)
+
+// handler handles the suspension related HTTP requests.
+// It is responsible for translating HTTP requests into service calls and formatting service
+// responses into HTTP responses.
+type handler struct {
+	service Service
+}
+
+// NewHandler creates a new suspension handler.
+func NewHandler(service Service) *handler {
+	return &handler{
+		service: service,
+	}
+}
+
+// ListSuspensions handles GET /api/suspensions requests.
+// It parses the optional limit and cursor query strings, and passes them to the suspension service
+// to return a page of the active suspensions that the user can lift. It then serializes the page
+// and its pagination metadata into a JSON HTTP response.
+func (h *handler) ListSuspensions(w http.ResponseWriter, r *http.Request) {
+	page, err := helper.ReadPage(r)
+	if err != nil {
+		helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	suspensions, meta, err := h.service.ListSuspensions(r.Context(), userId, page)
+	if err != nil {
+		if err == helper.ErrInvalidCursor {
+			helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonSuspensions, err := json.Marshal(suspensions)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonMeta, err := json.Marshal(meta)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataMetaAndMessage(jsonSuspensions, jsonMeta, SuccessfulListSuspensionsMessage)
+	helper.Write(w, response)
+}
+
+// CreateSuspension handles POST /api/suspensions requests.
+// It reads and validates the request body, and passes it to the suspension service to suspend or
+// ban the user. It then serializes the suspension into a JSON HTTP response.
+func (h *handler) CreateSuspension(w http.ResponseWriter, r *http.Request) {
+	var req CreateSuspensionRequest
+	err := helper.Read(r, &req)
+	if err != nil {
+		helper.WriteError(w, err.Error(), http.StatusBadRequest, api.CodeMalformedBody)
+		return
+	}
+
+	err = helper.Validate(req)
+	if err != nil {
+		helper.WriteValidationError(w, err)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	suspension, err := h.service.CreateSuspension(r.Context(), userId, req)
+	if err != nil {
+		if err == ErrCannotSuspendSelf {
+			helper.WriteError(w, ErrCannotSuspendSelf.Error(), http.StatusBadRequest, api.CodeCannotSuspendSelf)
+			return
+		}
+		if err == ErrPermissionDenied {
+			helper.WriteError(w, ErrPermissionDenied.Error(), http.StatusForbidden, api.CodePermissionDenied)
+			return
+		}
+		if err == ErrUserNotFound {
+			helper.WriteError(w, ErrUserNotFound.Error(), http.StatusNotFound, api.CodeUserNotFound)
+			return
+		}
+		if err == ErrTopicNotFound {
+			helper.WriteError(w, ErrTopicNotFound.Error(), http.StatusNotFound, api.CodeTopicNotFound)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonSuspension, err := json.Marshal(suspension)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataAndMessage(jsonSuspension, SuccessfulCreateSuspensionMessage)
+	helper.Write(w, response)
+}
+
+// LiftSuspension handles DELETE /api/suspensions/{id} requests.
+// It parses the id string, and passes it to the suspension service to lift the suspension, which
+// then serializes the result into a JSON HTTP response.
+func (h *handler) LiftSuspension(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidSuspensionIdMessage, http.StatusBadRequest, api.CodeInvalidSuspensionID)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	err = h.service.LiftSuspension(r.Context(), userId, id)
+	if err != nil {
+		if err == ErrSuspensionNotFound {
+			helper.WriteError(w, ErrSuspensionNotFound.Error(), http.StatusNotFound, api.CodeSuspensionNotFound)
+			return
+		}
+		if err == ErrPermissionDenied {
+			helper.WriteError(w, ErrPermissionDenied.Error(), http.StatusForbidden, api.CodePermissionDenied)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseMessage(SuccessfulLiftSuspensionMessage)
+	helper.Write(w, response)
+}
@@ -0,0 +1,14 @@
+package suspensions
+
+import "github.com/go-chi/chi/v5"
+
+// Routes group all suspension related HTTP endpoints together, with the base prefix path
+// /suspensions.
+// It connects the URLS to their respective handler methods.
+func Routes(router chi.Router, h *handler) {
+	router.Route("/suspensions", func(r chi.Router) {
+		r.Get("/", h.ListSuspensions)
+		r.Post("/", h.CreateSuspension)
+		r.Delete("/{id}", h.LiftSuspension)
+	})
+}
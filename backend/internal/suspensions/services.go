@@ -0,0 +1,313 @@
+package suspensions
+
+import (
+	"context"
+	"time"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/audit"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+	"github.com/haobuhaoo/gossip-with-go/internal/roles"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// svc implements the Service interface.
+// It depends on the sql generated Queries type to interact with the PostgreSQL database, on the
+// pgx connection pool to run transactions, on the role service to check permissions, and on the
+// audit log service to record moderator actions.
+type svc struct {
+	repo  *repo.Queries
+	db    *pgxpool.Pool
+	roles roles.Service
+	audit audit.Service
+}
+
+// NewService creates a new suspension service.
+func NewService(repo *repo.Queries, db *pgxpool.Pool, roles roles.Service, audit audit.Service) Service {
+	return &svc{
+		repo:  repo,
+		db:    db,
+		roles: roles,
+		audit: audit,
+	}
+}
+
+// CheckGlobal returns a *helper.SuspendedError if the user is suspended or banned from the whole
+// forum.
+func (s *svc) CheckGlobal(ctx context.Context, userId int64) error {
+	arg := repo.FindActiveSuspensionParams{
+		UserID: userId,
+	}
+	return s.check(ctx, arg)
+}
+
+// CheckTopic returns a *helper.SuspendedError if the user is suspended or banned from the topic or
+// from the whole forum.
+func (s *svc) CheckTopic(ctx context.Context, userId int64, topicId int64) error {
+	arg := repo.FindActiveSuspensionParams{
+		UserID:  userId,
+		TopicID: pgtype.Int8{Int64: topicId, Valid: true},
+	}
+	return s.check(ctx, arg)
+}
+
+// CreateSuspension suspends the user with the given request and returns the suspension. Only a
+// user who can moderate the topic may suspend a user from it, and only admins and global moderators
+// may suspend a user from the whole forum. Users cannot suspend themselves, admins cannot be
+// suspended, and only admins may suspend global moderators. The suspension is recorded in the
+// audit log.
+func (s *svc) CreateSuspension(ctx context.Context, moderatorId int64, req CreateSuspensionRequest) (repo.Suspension, error) {
+	if req.UserID == moderatorId {
+		return repo.Suspension{}, ErrCannotSuspendSelf
+	}
+
+	arg := repo.CreateSuspensionParams{
+		UserID:    req.UserID,
+		Reason:    req.Reason,
+		CreatedBy: pgtype.Int8{Int64: moderatorId, Valid: true},
+	}
+	if req.TopicID != nil {
+		arg.TopicID = pgtype.Int8{Int64: *req.TopicID, Valid: true}
+	}
+	if req.Days > 0 {
+		arg.ExpiresAt = pgtype.Timestamptz{Time: time.Now().AddDate(0, 0, req.Days), Valid: true}
+	}
+
+	err := s.checkPermission(ctx, moderatorId, arg.TopicID)
+	if err != nil {
+		return repo.Suspension{}, err
+	}
+
+	_, err = s.repo.FindUserByID(ctx, req.UserID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return repo.Suspension{}, ErrUserNotFound
+		}
+		return repo.Suspension{}, err
+	}
+
+	allowed, err := s.roles.CanSuspend(ctx, moderatorId, req.UserID)
+	if err != nil {
+		return repo.Suspension{}, err
+	}
+	if !allowed {
+		return repo.Suspension{}, ErrPermissionDenied
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.Suspension{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.repo.WithTx(tx)
+
+	suspension, err := qtx.CreateSuspension(ctx, arg)
+	if err != nil {
+		if helper.IsForeignKeyViolation(err) {
+			return repo.Suspension{}, ErrTopicNotFound
+		}
+		return repo.Suspension{}, err
+	}
+
+	err = s.audit.Record(ctx, qtx, audit.Entry{
+		ActorID:    moderatorId,
+		Action:     audit.ActionSuspensionCreate,
+		TargetType: audit.TargetUser,
+		TargetID:   suspension.UserID,
+		TopicID:    suspension.TopicID.Int64,
+		After:      suspension,
+	})
+	if err != nil {
+		return repo.Suspension{}, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return repo.Suspension{}, err
+	}
+
+	return suspension, nil
+}
+
+// ListSuspensions returns a page of the active suspensions that the moderator can lift, newest
+// first, together with the pagination metadata. Admins and global moderators see every
+// suspension, while topic moderators only see the suspensions from their topics.
+func (s *svc) ListSuspensions(ctx context.Context, moderatorId int64, page helper.Page) ([]Suspension, api.PageMeta, error) {
+	allTopics, err := s.roles.CanModerateAll(ctx, moderatorId)
+	if err != nil {
+		return []Suspension{}, api.PageMeta{}, err
+	}
+
+	arg := repo.ListActiveSuspensionsParams{
+		AllTopics:   allTopics,
+		ModeratorID: moderatorId,
+		PageLimit:   page.Limit + 1,
+	}
+	if page.Cursor != "" {
+		var c suspensionCursor
+		err := helper.DecodeCursor(page.Cursor, &c)
+		if err != nil {
+			return []Suspension{}, api.PageMeta{}, err
+		}
+		arg.CursorSuspensionID = pgtype.Int8{Int64: c.SuspensionID, Valid: true}
+	}
+
+	rows, err := s.repo.ListActiveSuspensions(ctx, arg)
+	if err != nil {
+		return []Suspension{}, api.PageMeta{}, err
+	}
+
+	countArg := repo.CountActiveSuspensionsParams{
+		AllTopics:   allTopics,
+		ModeratorID: moderatorId,
+	}
+	total, err := s.repo.CountActiveSuspensions(ctx, countArg)
+	if err != nil {
+		return []Suspension{}, api.PageMeta{}, err
+	}
+
+	fetched := len(rows)
+	if fetched > int(page.Limit) {
+		rows = rows[:page.Limit]
+	}
+
+	suspensions := make([]Suspension, 0, len(rows))
+	for _, row := range rows {
+		suspensions = append(suspensions, toSuspension(row))
+	}
+
+	nextCursor := ""
+	if len(suspensions) > 0 {
+		last := suspensions[len(suspensions)-1]
+		nextCursor = helper.EncodeCursor(suspensionCursor{SuspensionID: last.SuspensionID})
+	}
+
+	return suspensions, helper.NewPageMeta(fetched, page, total, nextCursor), nil
+}
+
+// LiftSuspension ends the suspension given by the id before it expires, and records it in the audit
+// log. Only a user who could have created the suspension may lift it.
+func (s *svc) LiftSuspension(ctx context.Context, moderatorId int64, suspensionId int64) error {
+	suspension, err := s.repo.FindSuspensionByID(ctx, suspensionId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrSuspensionNotFound
+		}
+		return err
+	}
+
+	err = s.checkPermission(ctx, moderatorId, suspension.TopicID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.repo.WithTx(tx)
+
+	arg := repo.LiftSuspensionParams{
+		SuspensionID: suspensionId,
+		LiftedBy:     pgtype.Int8{Int64: moderatorId, Valid: true},
+	}
+	updRows, err := qtx.LiftSuspension(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	if updRows == 0 {
+		return ErrSuspensionNotFound
+	}
+
+	lifted, err := qtx.FindSuspensionByID(ctx, suspensionId)
+	if err != nil {
+		return err
+	}
+
+	err = s.audit.Record(ctx, qtx, audit.Entry{
+		ActorID:    moderatorId,
+		Action:     audit.ActionSuspensionLift,
+		TargetType: audit.TargetUser,
+		TargetID:   suspension.UserID,
+		TopicID:    suspension.TopicID.Int64,
+		Before:     suspension,
+		After:      lifted,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// check returns a *helper.SuspendedError for the active suspension that matches arg, if any. A
+// suspension from the whole forum takes precedence over one from the topic.
+func (s *svc) check(ctx context.Context, arg repo.FindActiveSuspensionParams) error {
+	suspension, err := s.repo.FindActiveSuspension(ctx, arg)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	suspended := &helper.SuspendedError{
+		Reason:  suspension.Reason,
+		TopicID: suspension.TopicID.Int64,
+	}
+	if suspension.ExpiresAt.Valid {
+		suspended.ExpiresAt = &suspension.ExpiresAt.Time
+	}
+
+	return suspended
+}
+
+// checkPermission returns nil if the user can moderate the topic, or for a suspension from the
+// whole forum, is an admin or a global moderator.
+func (s *svc) checkPermission(ctx context.Context, userId int64, topicId pgtype.Int8) error {
+	var allowed bool
+	var err error
+	if topicId.Valid {
+		allowed, err = s.roles.CanModerateTopic(ctx, userId, topicId.Int64)
+	} else {
+		allowed, err = s.roles.CanModerateAll(ctx, userId)
+	}
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrPermissionDenied
+	}
+
+	return nil
+}
+
+// toSuspension converts an active suspension row into the Suspension model.
+func toSuspension(row repo.ListActiveSuspensionsRow) Suspension {
+	suspension := Suspension{
+		SuspensionID: row.SuspensionID,
+		UserID:       row.UserID,
+		Username:     row.Username,
+		Reason:       row.Reason,
+		Permanent:    !row.ExpiresAt.Valid,
+		CreatedAt:    row.CreatedAt.Time,
+	}
+	if row.TopicID.Valid {
+		suspension.TopicID = &row.TopicID.Int64
+	}
+	if row.ExpiresAt.Valid {
+		suspension.ExpiresAt = &row.ExpiresAt.Time
+	}
+	if row.CreatedBy.Valid {
+		suspension.CreatedBy = &row.CreatedBy.Int64
+	}
+
+	return suspension
+}
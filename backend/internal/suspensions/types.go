@@ -0,0 +1,50 @@
+package suspensions
+
+import (
+	"context"
+	"time"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+)
+
+// Service defines the domain logic for suspension related operations.
+// It is responsible for enforcing application rules and making database calls.
+type Service interface {
+	CheckGlobal(ctx context.Context, userId int64) error
+	CheckTopic(ctx context.Context, userId int64, topicId int64) error
+	CreateSuspension(ctx context.Context, moderatorId int64, req CreateSuspensionRequest) (repo.Suspension, error)
+	ListSuspensions(ctx context.Context, moderatorId int64, page helper.Page) ([]Suspension, api.PageMeta, error)
+	LiftSuspension(ctx context.Context, moderatorId int64, suspensionId int64) error
+}
+
+// Suspension model that is passed to the frontend for an active suspension or ban. TopicID is
+// omitted for a suspension from the whole forum, and ExpiresAt is omitted for a permanent ban.
+type Suspension struct {
+	SuspensionID int64      `json:"suspension_id"`
+	UserID       int64      `json:"user_id"`
+	Username     string     `json:"username"`
+	TopicID      *int64     `json:"topic_id,omitempty"`
+	Reason       string     `json:"reason"`
+	Permanent    bool       `json:"permanent"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	CreatedBy    *int64     `json:"created_by,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// CreateSuspensionRequest handles the suspension related HTTP request body for suspending a user.
+// The suspension only applies to the topic if TopicID is set, and lasts for Days, or forever if
+// Days is left out.
+type CreateSuspensionRequest struct {
+	UserID  int64  `json:"userId" validate:"required,min=1"`
+	TopicID *int64 `json:"topicId" validate:"omitempty,min=1"`
+	Reason  string `json:"reason" validate:"required,max=1000"`
+	Days    int    `json:"days" validate:"omitempty,min=1,max=3650"`
+}
+
+// suspensionCursor holds the id of the last suspension in a page, which is encoded into the opaque
+// next_cursor string.
+type suspensionCursor struct {
+	SuspensionID int64 `json:"suspension_id"`
+}
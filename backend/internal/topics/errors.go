@@ -5,4 +5,6 @@ import "errors"
 var (
 	ErrTopicAlreadyExists = errors.New("topic already exists")
 	ErrTopicNotFound      = errors.New("topic not found")
+	ErrPermissionDenied   = errors.New("permission denied")
+	ErrNotSubscribed      = errors.New("not subscribed to topic")
 )
@@ -6,23 +6,25 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-playground/validator/v10"
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
 	"github.com/haobuhaoo/gossip-with-go/internal/helper"
 	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
-	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const (
-	InvalidTopicIdMessage        = "Invalid topic id"
-	InvalidRequestBodyMessage    = "Required fields missing"
-	InvalidQueryMessage          = "Query string missing"
-	MissingUserIDMessage         = "Missing userID"
-	SuccessfulListTopicMessage   = "Successfully listed all topics"
-	SuccessfulFindTopicMessage   = "Successfully find topic"
-	SuccessfulCreateTopicMessage = "Successfully created topic"
-	SuccessfulUpdateTopicMessage = "Successfully updated topic"
-	SuccessfulDeleteTopicMessage = "Successfully deleted topic"
-	SuccessfulSearchTopicMessage = "Successfully searched topic"
+	InvalidTopicIdMessage              = "Invalid topic id"
+	InvalidQueryMessage                = "Query string missing"
+	InvalidPageMessage                 = "Invalid limit or cursor"
+	MissingUserIDMessage               = "Missing userID"
+	SuccessfulListTopicMessage         = "Successfully listed all topics"
+	SuccessfulFindTopicMessage         = "Successfully find topic"
+	SuccessfulCreateTopicMessage       = "Successfully created topic"
+	SuccessfulUpdateTopicMessage       = "Successfully updated topic"
+	SuccessfulDeleteTopicMessage       = "Successfully deleted topic"
+	SuccessfulSearchTopicMessage       = "Successfully searched topic"
+	SuccessfulSubscribeMessage         = "Successfully subscribed to topic"
+	SuccessfulUnsubscribeMessage       = "Successfully unsubscribed from topic"
+	SuccessfulListSubscriptionsMessage = "Successfully listed subscribed topics"
 )
 
 // handler handles the topic related HTTP requests.
@@ -40,22 +42,39 @@ func NewHandler(service Service) *handler {
 }
 
 // ListTopics handles GET /api/topics requests.
-// It calls the topic service to return all topics and serializes the result into a JSON HTTP
-// response.
+// It parses the limit and cursor query strings, and calls the topic service to return a page of
+// topics. It then serializes the result and its pagination metadata into a JSON HTTP response.
 func (h *handler) ListTopics(w http.ResponseWriter, r *http.Request) {
-	topics, err := h.service.ListTopics(r.Context())
+	page, err := helper.ReadPage(r)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+		return
+	}
+
+	topics, meta, err := h.service.ListTopics(r.Context(), page)
+	if err != nil {
+		if err == helper.ErrInvalidCursor {
+			helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
 	jsonTopic, err := json.Marshal(topics)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
-	response := helper.ParseResponseDataAndMessage(jsonTopic, SuccessfulListTopicMessage)
+	jsonMeta, err := json.Marshal(meta)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataMetaAndMessage(jsonTopic, jsonMeta, SuccessfulListTopicMessage)
 	helper.Write(w, response)
 }
 
@@ -66,24 +85,24 @@ func (h *handler) FindTopicByID(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		helper.WriteError(w, InvalidTopicIdMessage, http.StatusBadRequest)
+		helper.WriteError(w, InvalidTopicIdMessage, http.StatusBadRequest, api.CodeInvalidTopicID)
 		return
 	}
 
 	topic, err := h.service.FindTopicByID(r.Context(), id)
 	if err != nil {
 		if err == ErrTopicNotFound {
-			helper.WriteError(w, ErrTopicNotFound.Error(), http.StatusNotFound)
+			helper.WriteError(w, ErrTopicNotFound.Error(), http.StatusNotFound, api.CodeTopicNotFound)
 			return
 		}
 
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
 	jsonTopic, err := json.Marshal(topic)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
@@ -98,19 +117,19 @@ func (h *handler) CreateTopic(w http.ResponseWriter, r *http.Request) {
 	var req CreateTopicRequest
 	err := helper.Read(r, &req)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusBadRequest)
+		helper.WriteError(w, err.Error(), http.StatusBadRequest, api.CodeMalformedBody)
 		return
 	}
 
-	err = validator.New().Struct(req)
+	err = helper.Validate(req)
 	if err != nil {
-		helper.WriteError(w, InvalidRequestBodyMessage, http.StatusBadRequest)
+		helper.WriteValidationError(w, err)
 		return
 	}
 
 	userId, ok := r.Context().Value("userID").(int64)
 	if !ok {
-		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest)
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
 		return
 	}
 
@@ -121,17 +140,17 @@ func (h *handler) CreateTopic(w http.ResponseWriter, r *http.Request) {
 	topic, err := h.service.CreateTopic(r.Context(), newTopic)
 	if err != nil {
 		if err == ErrTopicAlreadyExists {
-			helper.WriteError(w, ErrTopicAlreadyExists.Error(), http.StatusConflict)
+			helper.WriteError(w, ErrTopicAlreadyExists.Error(), http.StatusConflict, api.CodeTopicTitleTaken)
 			return
 		}
 
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
 	jsonTopic, err := json.Marshal(topic)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
@@ -147,52 +166,55 @@ func (h *handler) UpdateTopic(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		helper.WriteError(w, InvalidTopicIdMessage, http.StatusBadRequest)
+		helper.WriteError(w, InvalidTopicIdMessage, http.StatusBadRequest, api.CodeInvalidTopicID)
 		return
 	}
 
 	var req UpdateTopicRequest
 	err = helper.Read(r, &req)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusBadRequest)
+		helper.WriteError(w, err.Error(), http.StatusBadRequest, api.CodeMalformedBody)
 		return
 	}
 
-	err = validator.New().Struct(req)
+	err = helper.Validate(req)
 	if err != nil {
-		helper.WriteError(w, InvalidRequestBodyMessage, http.StatusBadRequest)
+		helper.WriteValidationError(w, err)
 		return
 	}
 
 	userId, ok := r.Context().Value("userID").(int64)
 	if !ok {
-		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest)
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
 		return
 	}
 
 	newTopic := repo.UpdateTopicParams{
 		TopicID: id,
-		UserID:  userId,
 		Title:   req.Title,
 	}
-	topic, err := h.service.UpdateTopic(r.Context(), newTopic)
+	topic, err := h.service.UpdateTopic(r.Context(), userId, newTopic)
 	if err != nil {
 		if err == ErrTopicNotFound {
-			helper.WriteError(w, ErrTopicNotFound.Error(), http.StatusNotFound)
+			helper.WriteError(w, ErrTopicNotFound.Error(), http.StatusNotFound, api.CodeTopicNotFound)
+			return
+		}
+		if err == ErrPermissionDenied {
+			helper.WriteError(w, ErrPermissionDenied.Error(), http.StatusForbidden, api.CodePermissionDenied)
 			return
 		}
 		if err == ErrTopicAlreadyExists {
-			helper.WriteError(w, ErrTopicAlreadyExists.Error(), http.StatusConflict)
+			helper.WriteError(w, ErrTopicAlreadyExists.Error(), http.StatusConflict, api.CodeTopicTitleTaken)
 			return
 		}
 
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
 	jsonTopic, err := json.Marshal(topic)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
@@ -207,28 +229,28 @@ func (h *handler) DeleteTopic(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		helper.WriteError(w, InvalidTopicIdMessage, http.StatusBadRequest)
+		helper.WriteError(w, InvalidTopicIdMessage, http.StatusBadRequest, api.CodeInvalidTopicID)
 		return
 	}
 
 	userId, ok := r.Context().Value("userID").(int64)
 	if !ok {
-		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest)
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
 		return
 	}
 
-	arg := repo.DeleteTopicParams{
-		TopicID: id,
-		UserID:  userId,
-	}
-	err = h.service.DeleteTopic(r.Context(), arg)
+	err = h.service.DeleteTopic(r.Context(), userId, id)
 	if err != nil {
 		if err == ErrTopicNotFound {
-			helper.WriteError(w, ErrTopicNotFound.Error(), http.StatusNotFound)
+			helper.WriteError(w, ErrTopicNotFound.Error(), http.StatusNotFound, api.CodeTopicNotFound)
+			return
+		}
+		if err == ErrPermissionDenied {
+			helper.WriteError(w, ErrPermissionDenied.Error(), http.StatusForbidden, api.CodePermissionDenied)
 			return
 		}
 
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
@@ -237,32 +259,153 @@ func (h *handler) DeleteTopic(w http.ResponseWriter, r *http.Request) {
 }
 
 // SearchTopic handles GET /api/topics/search requests.
-// It parses the query string and passes it to the topic service to search for all topic titles
-// that contains the query string (case-insensitive), which then serializes the result into a
-// JSON HTTP response.
+// It parses the query, limit and cursor query strings and passes them to the topic service to
+// run a full-text search over the topic titles, which then serializes the page of ranked results
+// and its pagination metadata into a JSON HTTP response.
 func (h *handler) SearchTopic(w http.ResponseWriter, r *http.Request) {
 	rawQuery := r.URL.Query().Get("q")
 	if rawQuery == "" {
-		helper.WriteError(w, InvalidQueryMessage, http.StatusBadRequest)
+		helper.WriteError(w, InvalidQueryMessage, http.StatusBadRequest, api.CodeInvalidQuery)
 		return
 	}
 
-	query := pgtype.Text{
-		String: rawQuery,
-		Valid:  true,
+	page, err := helper.ReadPage(r)
+	if err != nil {
+		helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+		return
 	}
-	topic, err := h.service.SearchTopic(r.Context(), query)
+
+	topic, meta, err := h.service.SearchTopic(r.Context(), rawQuery, page)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		if err == helper.ErrInvalidCursor {
+			helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
 	jsonTopic, err := json.Marshal(topic)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonMeta, err := json.Marshal(meta)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseDataMetaAndMessage(jsonTopic, jsonMeta, SuccessfulSearchTopicMessage)
+	helper.Write(w, response)
+}
+
+// Subscribe handles POST /api/topics/{id}/subscription requests.
+// It parses the id string, and passes it to the topic service to subscribe the user to the
+// specified topic, which then serializes the result into a JSON HTTP response.
+func (h *handler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidTopicIdMessage, http.StatusBadRequest, api.CodeInvalidTopicID)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	err = h.service.Subscribe(r.Context(), userId, id)
+	if err != nil {
+		if err == ErrTopicNotFound {
+			helper.WriteError(w, ErrTopicNotFound.Error(), http.StatusNotFound, api.CodeTopicNotFound)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseMessage(SuccessfulSubscribeMessage)
+	helper.Write(w, response)
+}
+
+// Unsubscribe handles DELETE /api/topics/{id}/subscription requests.
+// It parses the id string, and passes it to the topic service to unsubscribe the user from the
+// specified topic, which then serializes the result into a JSON HTTP response.
+func (h *handler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		helper.WriteError(w, InvalidTopicIdMessage, http.StatusBadRequest, api.CodeInvalidTopicID)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	err = h.service.Unsubscribe(r.Context(), userId, id)
+	if err != nil {
+		if err == ErrNotSubscribed {
+			helper.WriteError(w, ErrNotSubscribed.Error(), http.StatusNotFound, api.CodeNotSubscribed)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	response := helper.ParseResponseMessage(SuccessfulUnsubscribeMessage)
+	helper.Write(w, response)
+}
+
+// ListSubscriptions handles GET /api/topics/subscriptions requests.
+// It parses the limit and cursor query strings, and calls the topic service to return a page of the
+// topics that the user subscribed to. It then serializes the result and its pagination metadata
+// into a JSON HTTP response.
+func (h *handler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	page, err := helper.ReadPage(r)
+	if err != nil {
+		helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+		return
+	}
+
+	userId, ok := r.Context().Value("userID").(int64)
+	if !ok {
+		helper.WriteError(w, MissingUserIDMessage, http.StatusBadRequest, api.CodeUnauthenticated)
+		return
+	}
+
+	topics, meta, err := h.service.ListSubscriptions(r.Context(), userId, page)
+	if err != nil {
+		if err == helper.ErrInvalidCursor {
+			helper.WriteError(w, InvalidPageMessage, http.StatusBadRequest, api.CodeInvalidPage)
+			return
+		}
+
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonTopic, err := json.Marshal(topics)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
+		return
+	}
+
+	jsonMeta, err := json.Marshal(meta)
+	if err != nil {
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
-	response := helper.ParseResponseDataAndMessage(jsonTopic, SuccessfulSearchTopicMessage)
+	response := helper.ParseResponseDataMetaAndMessage(jsonTopic, jsonMeta, SuccessfulListSubscriptionsMessage)
 	helper.Write(w, response)
 }
@@ -7,10 +7,13 @@ import "github.com/go-chi/chi/v5"
 func Routes(router chi.Router, h *handler) {
 	router.Route("/topics", func(r chi.Router) {
 		r.Get("/search", h.SearchTopic)
+		r.Get("/subscriptions", h.ListSubscriptions)
 		r.Get("/", h.ListTopics)
 		r.Get("/{id}", h.FindTopicByID)
 		r.Post("/", h.CreateTopic)
 		r.Put("/{id}", h.UpdateTopic)
 		r.Delete("/{id}", h.DeleteTopic)
+		r.Post("/{id}/subscription", h.Subscribe)
+		r.Delete("/{id}/subscription", h.Unsubscribe)
 	})
 }
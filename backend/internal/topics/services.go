@@ -2,29 +2,67 @@ package topics
 
 import (
 	"context"
+	"log"
 
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/audit"
 	"github.com/haobuhaoo/gossip-with-go/internal/helper"
 	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+	"github.com/haobuhaoo/gossip-with-go/internal/roles"
+	"github.com/haobuhaoo/gossip-with-go/internal/storage"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // svc implements the Service interface.
-// It depends on the sql generated Queries type to interact with the PostgreSQL database.
+// It depends on the sql generated Queries type to interact with the PostgreSQL database, on the
+// pgx connection pool to run transactions, on the role service to check permissions, on the audit
+// log service to record moderator actions, and on the storage to remove the files of deleted
+// attachments.
 type svc struct {
-	repo *repo.Queries
+	repo    *repo.Queries
+	db      *pgxpool.Pool
+	roles   roles.Service
+	audit   audit.Service
+	storage storage.Storage
 }
 
 // NewService creates a new topic service.
-func NewService(repo *repo.Queries) Service {
+func NewService(repo *repo.Queries, db *pgxpool.Pool, roles roles.Service, audit audit.Service, storage storage.Storage) Service {
 	return &svc{
-		repo: repo,
+		repo:    repo,
+		db:      db,
+		roles:   roles,
+		audit:   audit,
+		storage: storage,
 	}
 }
 
-// ListTopics returns all topics from the database.
-func (s *svc) ListTopics(ctx context.Context) ([]repo.Topic, error) {
-	return s.repo.ListTopics(ctx)
+// ListTopics returns a page of topics ordered by title from the database, together with the
+// pagination metadata.
+func (s *svc) ListTopics(ctx context.Context, page helper.Page) ([]repo.Topic, api.PageMeta, error) {
+	cursor, err := decodeTopicCursor(page.Cursor)
+	if err != nil {
+		return []repo.Topic{}, api.PageMeta{}, err
+	}
+
+	arg := repo.ListTopicsParams{
+		CursorTitle: cursor,
+		PageLimit:   page.Limit + 1,
+	}
+	topics, err := s.repo.ListTopics(ctx, arg)
+	if err != nil {
+		return []repo.Topic{}, api.PageMeta{}, err
+	}
+
+	total, err := s.repo.CountTopics(ctx)
+	if err != nil {
+		return []repo.Topic{}, api.PageMeta{}, err
+	}
+
+	topics, meta := paginateTopics(topics, page, total)
+	return topics, meta, nil
 }
 
 // FindTopicByID returns a specific topic identified by id from the database.
@@ -54,8 +92,36 @@ func (s *svc) CreateTopic(ctx context.Context, arg repo.CreateTopicParams) (repo
 }
 
 // UpdateTopic updates an existing topic with the given arg params and returns it.
-func (s *svc) UpdateTopic(ctx context.Context, arg repo.UpdateTopicParams) (repo.Topic, error) {
-	topic, err := s.repo.UpdateTopic(ctx, arg)
+// Only the author of the topic or a user who can moderate it may update it. Updates by moderators
+// are recorded in the audit log.
+func (s *svc) UpdateTopic(ctx context.Context, userId int64, arg repo.UpdateTopicParams) (repo.Topic, error) {
+	existing, err := s.repo.FindTopicByID(ctx, arg.TopicID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return repo.Topic{}, ErrTopicNotFound
+		}
+		return repo.Topic{}, err
+	}
+
+	if existing.UserID != userId {
+		allowed, err := s.roles.CanModerateTopic(ctx, userId, arg.TopicID)
+		if err != nil {
+			return repo.Topic{}, err
+		}
+		if !allowed {
+			return repo.Topic{}, ErrPermissionDenied
+		}
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repo.Topic{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.repo.WithTx(tx)
+
+	topic, err := qtx.UpdateTopic(ctx, arg)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return repo.Topic{}, ErrTopicNotFound
@@ -66,13 +132,66 @@ func (s *svc) UpdateTopic(ctx context.Context, arg repo.UpdateTopicParams) (repo
 		return repo.Topic{}, err
 	}
 
+	if existing.UserID != userId {
+		err = s.audit.Record(ctx, qtx, audit.Entry{
+			ActorID:    userId,
+			Action:     audit.ActionTopicUpdate,
+			TargetType: audit.TargetTopic,
+			TargetID:   topic.TopicID,
+			TopicID:    topic.TopicID,
+			Before:     existing,
+			After:      topic,
+		})
+		if err != nil {
+			return repo.Topic{}, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return repo.Topic{}, err
+	}
+
 	return topic, nil
 }
 
 // DeleteTopic deletes the topic given by the id from the database.
-// It deletes all posts under that topic too.
-func (s *svc) DeleteTopic(ctx context.Context, arg repo.DeleteTopicParams) error {
-	delRows, err := s.repo.DeleteTopic(ctx, arg)
+// It deletes all posts under that topic too. Only the author of the topic or an admin may delete it.
+// Deletions by admins are recorded in the audit log. The stored files of the attachments under the
+// topic are removed once the deletion has been committed.
+func (s *svc) DeleteTopic(ctx context.Context, userId int64, topicId int64) error {
+	existing, err := s.repo.FindTopicByID(ctx, topicId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrTopicNotFound
+		}
+		return err
+	}
+
+	if existing.UserID != userId {
+		allowed, err := s.roles.IsAdmin(ctx, userId)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return ErrPermissionDenied
+		}
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.repo.WithTx(tx)
+
+	attachments, err := qtx.DeleteTopicAttachments(ctx, topicId)
+	if err != nil {
+		return err
+	}
+
+	delRows, err := qtx.DeleteTopic(ctx, topicId)
 	if err != nil {
 		return err
 	}
@@ -81,11 +200,184 @@ func (s *svc) DeleteTopic(ctx context.Context, arg repo.DeleteTopicParams) error
 		return ErrTopicNotFound
 	}
 
+	if existing.UserID != userId {
+		err = s.audit.Record(ctx, qtx, audit.Entry{
+			ActorID:    userId,
+			Action:     audit.ActionTopicDelete,
+			TargetType: audit.TargetTopic,
+			TargetID:   topicId,
+			TopicID:    topicId,
+			Before:     existing,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, attachment := range attachments {
+		s.removeFile(ctx, attachment.StorageKey)
+		if attachment.ThumbnailKey.Valid {
+			s.removeFile(ctx, attachment.ThumbnailKey.String)
+		}
+	}
+
+	return nil
+}
+
+// SearchTopic runs a full-text search over all topic titles and returns a page of matched topics
+// ordered by relevance, together with the pagination metadata.
+func (s *svc) SearchTopic(ctx context.Context, query string, page helper.Page) ([]TopicSearchResult, api.PageMeta, error) {
+	cursorRank, cursorId, err := helper.DecodeSearchCursor(page.Cursor)
+	if err != nil {
+		return []TopicSearchResult{}, api.PageMeta{}, err
+	}
+
+	arg := repo.SearchTopicParams{
+		Query:         query,
+		CursorTopicID: cursorId,
+		CursorRank:    cursorRank,
+		PageLimit:     page.Limit + 1,
+	}
+	rows, err := s.repo.SearchTopic(ctx, arg)
+	if err != nil {
+		return []TopicSearchResult{}, api.PageMeta{}, err
+	}
+
+	total, err := s.repo.CountSearchTopic(ctx, query)
+	if err != nil {
+		return []TopicSearchResult{}, api.PageMeta{}, err
+	}
+
+	fetched := len(rows)
+	if fetched > int(page.Limit) {
+		rows = rows[:page.Limit]
+	}
+
+	results := make([]TopicSearchResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, TopicSearchResult{
+			TopicID:   row.TopicID,
+			UserID:    row.UserID,
+			Title:     row.Title,
+			Headline:  helper.SanitizeHeadline(row.Headline),
+			Rank:      row.Rank,
+			CreatedAt: row.CreatedAt.Time,
+		})
+	}
+
+	nextCursor := ""
+	if len(results) > 0 {
+		last := results[len(results)-1]
+		nextCursor = helper.EncodeCursor(helper.SearchCursor{Rank: last.Rank, ID: last.TopicID})
+	}
+
+	return results, helper.NewPageMeta(fetched, page, total, nextCursor), nil
+}
+
+// Subscribe subscribes the user to the topic given by the id, so that its posts appear in the feed
+// of the user. Subscribing to a topic that the user already subscribed to has no effect.
+func (s *svc) Subscribe(ctx context.Context, userId int64, topicId int64) error {
+	_, err := s.repo.FindTopicByID(ctx, topicId)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return ErrTopicNotFound
+		}
+		return err
+	}
+
+	arg := repo.SubscribeTopicParams{
+		UserID:  userId,
+		TopicID: topicId,
+	}
+	return s.repo.SubscribeTopic(ctx, arg)
+}
+
+// Unsubscribe removes the subscription of the user to the topic given by the id.
+func (s *svc) Unsubscribe(ctx context.Context, userId int64, topicId int64) error {
+	arg := repo.UnsubscribeTopicParams{
+		UserID:  userId,
+		TopicID: topicId,
+	}
+	delRows, err := s.repo.UnsubscribeTopic(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	if delRows == 0 {
+		return ErrNotSubscribed
+	}
 	return nil
 }
 
-// SearchTopic searches all topic titles that contains the search query (case-insensitive)
-// and returns all matched topics.
-func (s *svc) SearchTopic(ctx context.Context, query pgtype.Text) ([]repo.Topic, error) {
-	return s.repo.SearchTopic(ctx, query)
+// ListSubscriptions returns a page of the topics that the user subscribed to ordered by title,
+// together with the pagination metadata.
+func (s *svc) ListSubscriptions(ctx context.Context, userId int64, page helper.Page) ([]repo.Topic, api.PageMeta, error) {
+	cursor, err := decodeTopicCursor(page.Cursor)
+	if err != nil {
+		return []repo.Topic{}, api.PageMeta{}, err
+	}
+
+	arg := repo.ListSubscribedTopicsParams{
+		UserID:      userId,
+		CursorTitle: cursor,
+		PageLimit:   page.Limit + 1,
+	}
+	topics, err := s.repo.ListSubscribedTopics(ctx, arg)
+	if err != nil {
+		return []repo.Topic{}, api.PageMeta{}, err
+	}
+
+	total, err := s.repo.CountSubscribedTopics(ctx, userId)
+	if err != nil {
+		return []repo.Topic{}, api.PageMeta{}, err
+	}
+
+	topics, meta := paginateTopics(topics, page, total)
+	return topics, meta, nil
+}
+
+// decodeTopicCursor returns the title to continue after, or an invalid pgtype.Text for the first
+// page.
+func decodeTopicCursor(cursor string) (pgtype.Text, error) {
+	if cursor == "" {
+		return pgtype.Text{}, nil
+	}
+
+	var c topicCursor
+	err := helper.DecodeCursor(cursor, &c)
+	if err != nil {
+		return pgtype.Text{}, err
+	}
+
+	return pgtype.Text{String: c.Title, Valid: true}, nil
+}
+
+// paginateTopics trims the extra topic fetched to detect further pages, and builds the pagination
+// metadata with the cursor of the last topic.
+func paginateTopics(topics []repo.Topic, page helper.Page, total int64) ([]repo.Topic, api.PageMeta) {
+	fetched := len(topics)
+	if fetched > int(page.Limit) {
+		topics = topics[:page.Limit]
+	}
+
+	nextCursor := ""
+	if len(topics) > 0 {
+		nextCursor = helper.EncodeCursor(topicCursor{Title: topics[len(topics)-1].Title})
+	}
+
+	return topics, helper.NewPageMeta(fetched, page, total, nextCursor)
+}
+
+// removeFile removes the stored file of a deleted attachment. Failures are only logged, as the
+// attachment itself is already gone.
+func (s *svc) removeFile(ctx context.Context, key string) {
+	err := s.storage.Delete(ctx, key)
+	if err != nil {
+		log.Printf("failed to delete stored file %s: %v", key, err)
+	}
 }
@@ -2,20 +2,42 @@ package topics
 
 import (
 	"context"
+	"time"
 
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
 	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
-	"github.com/jackc/pgx/v5/pgtype"
 )
 
 // Service defines the domain logic for topic related operations.
 // It is responsible for enforcing application rules and making database calls.
 type Service interface {
-	ListTopics(ctx context.Context) ([]repo.Topic, error)
+	ListTopics(ctx context.Context, page helper.Page) ([]repo.Topic, api.PageMeta, error)
 	FindTopicByID(ctx context.Context, id int64) (repo.Topic, error)
 	CreateTopic(ctx context.Context, arg repo.CreateTopicParams) (repo.Topic, error)
-	UpdateTopic(ctx context.Context, arg repo.UpdateTopicParams) (repo.Topic, error)
-	DeleteTopic(ctx context.Context, arg repo.DeleteTopicParams) error
-	SearchTopic(ctx context.Context, query pgtype.Text) ([]repo.Topic, error)
+	UpdateTopic(ctx context.Context, userId int64, arg repo.UpdateTopicParams) (repo.Topic, error)
+	DeleteTopic(ctx context.Context, userId int64, topicId int64) error
+	SearchTopic(ctx context.Context, query string, page helper.Page) ([]TopicSearchResult, api.PageMeta, error)
+	Subscribe(ctx context.Context, userId int64, topicId int64) error
+	Unsubscribe(ctx context.Context, userId int64, topicId int64) error
+	ListSubscriptions(ctx context.Context, userId int64, page helper.Page) ([]repo.Topic, api.PageMeta, error)
+}
+
+// TopicSearchResult model that is passed to the frontend for a topic that matches a search query.
+// The headline is the title with the matched terms wrapped in <mark> tags.
+type TopicSearchResult struct {
+	TopicID   int64     `json:"topic_id"`
+	UserID    int64     `json:"user_id"`
+	Title     string    `json:"title"`
+	Headline  string    `json:"headline"`
+	Rank      float32   `json:"rank"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// topicCursor holds the sort key of the last topic in a page, which is encoded into the opaque
+// next_cursor string.
+type topicCursor struct {
+	Title string `json:"title"`
 }
 
 // CreateTopicRequest handles the topic related HTTP request body for creation of a new topic.
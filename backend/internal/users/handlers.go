@@ -6,13 +6,13 @@ import (
 	"regexp"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-playground/validator/v10"
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
 	"github.com/haobuhaoo/gossip-with-go/internal/helper"
 )
 
 const (
-	InvalidRequestBodyMessage   = "Required fields missing"
 	InvalidUsernameMessage      = "Only alphanumeric, period, hyphen, underscore allowed"
+	InvalidPasswordMessage      = "Password must be between 8 and 72 characters"
 	SuccessfulFindUserMessage   = "Successfully find user"
 	SuccessfulCreateUserMessage = "Successfully created user"
 )
@@ -39,17 +39,17 @@ func (h *handler) FindUserByName(w http.ResponseWriter, r *http.Request) {
 	user, err := h.service.FindUserByName(r.Context(), name)
 	if err != nil {
 		if err == ErrUserNotFound {
-			helper.WriteError(w, ErrUserNotFound.Error(), http.StatusNotFound)
+			helper.WriteError(w, ErrUserNotFound.Error(), http.StatusNotFound, api.CodeUserNotFound)
 			return
 		}
 
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
 	jsonUser, err := json.Marshal(user)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
@@ -59,41 +59,46 @@ func (h *handler) FindUserByName(w http.ResponseWriter, r *http.Request) {
 
 // CreateUser handles POST /users requests.
 // It reads and validates the request body, and passes it to the user service to create the new
-// user with a unique name. It then serializes the result into a JSON HTTP response.
+// user with a unique name and password. It then serializes the result into a JSON HTTP response.
 func (h *handler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	var req CreateUserRequest
 	err := helper.Read(r, &req)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusBadRequest)
+		helper.WriteError(w, err.Error(), http.StatusBadRequest, api.CodeMalformedBody)
 		return
 	}
 
-	err = validator.New().Struct(req)
+	err = helper.Validate(req)
 	if err != nil {
-		helper.WriteError(w, InvalidRequestBodyMessage, http.StatusBadRequest)
+		helper.WriteValidationError(w, err)
 		return
 	}
 
 	var usernameRegex = regexp.MustCompile(`^[a-zA-Z0-9._-]{3,50}$`)
 	if !usernameRegex.MatchString(req.Name) {
-		helper.WriteError(w, InvalidUsernameMessage, http.StatusBadRequest)
+		helper.WriteError(w, InvalidUsernameMessage, http.StatusBadRequest, api.CodeInvalidUsername)
 		return
 	}
 
-	user, err := h.service.CreateUser(r.Context(), req.Name)
+	if !helper.IsValidPasswordLength(req.Password) {
+		helper.WriteError(w, InvalidPasswordMessage, http.StatusBadRequest, api.CodeInvalidPassword)
+		return
+	}
+
+	user, err := h.service.CreateUser(r.Context(), req.Name, req.Password)
 	if err != nil {
 		if err == ErrUserAlreadyExists {
-			helper.WriteError(w, ErrUserAlreadyExists.Error(), http.StatusConflict)
+			helper.WriteError(w, ErrUserAlreadyExists.Error(), http.StatusConflict, api.CodeUsernameTaken)
 			return
 		}
 
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
 	jsonUser, err := json.Marshal(user)
 	if err != nil {
-		helper.WriteError(w, err.Error(), http.StatusInternalServerError)
+		helper.WriteInternalError(w, r, err)
 		return
 	}
 
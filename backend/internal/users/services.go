@@ -6,6 +6,7 @@ import (
 	"github.com/haobuhaoo/gossip-with-go/internal/helper"
 	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 // svc implements the Service interface.
@@ -22,27 +23,46 @@ func NewService(repo *repo.Queries) Service {
 }
 
 // FindUserByName returns a specific user identified by the name from the database.
-func (s *svc) FindUserByName(ctx context.Context, name string) (repo.User, error) {
+func (s *svc) FindUserByName(ctx context.Context, name string) (User, error) {
 	user, err := s.repo.FindUserByName(ctx, name)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return repo.User{}, ErrUserNotFound
+			return User{}, ErrUserNotFound
 		}
-		return repo.User{}, err
+		return User{}, err
 	}
 
-	return user, nil
+	return ToUser(user), nil
 }
 
-// CreateUser creates and returns a new user with the given name.
-func (s *svc) CreateUser(ctx context.Context, name string) (repo.User, error) {
-	user, err := s.repo.CreateUser(ctx, name)
+// CreateUser hashes the password, then creates and returns a new user with the given name.
+func (s *svc) CreateUser(ctx context.Context, name string, password string) (User, error) {
+	hash, err := helper.HashPassword(password)
+	if err != nil {
+		return User{}, err
+	}
+
+	arg := repo.CreateUserParams{
+		Name:         name,
+		PasswordHash: pgtype.Text{String: hash, Valid: true},
+	}
+	user, err := s.repo.CreateUser(ctx, arg)
 	if err != nil {
 		if helper.IsUniqueViolation(err) {
-			return repo.User{}, ErrUserAlreadyExists
+			return User{}, ErrUserAlreadyExists
 		}
-		return repo.User{}, err
+		return User{}, err
 	}
 
-	return user, nil
+	return ToUser(user), nil
+}
+
+// ToUser converts the database user into the User model, dropping the password hash.
+func ToUser(user repo.User) User {
+	return User{
+		UserID:             user.UserID,
+		Name:               user.Name,
+		Role:               user.Role,
+		MustChangePassword: user.MustChangePassword,
+	}
 }
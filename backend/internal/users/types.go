@@ -2,18 +2,26 @@ package users
 
 import (
 	"context"
-
-	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
 )
 
 // Service defines the domain logic for user related operations.
 // It is responsible for enforcing application rules and making database calls.
 type Service interface {
-	FindUserByName(ctx context.Context, name string) (repo.User, error)
-	CreateUser(ctx context.Context, name string) (repo.User, error)
+	FindUserByName(ctx context.Context, name string) (User, error)
+	CreateUser(ctx context.Context, name string, password string) (User, error)
+}
+
+// User model that is passed to the frontend.
+// It never includes the password hash.
+type User struct {
+	UserID             int64  `json:"user_id"`
+	Name               string `json:"name"`
+	Role               string `json:"role"`
+	MustChangePassword bool   `json:"must_change_password"`
 }
 
 // CreateUserRequest handles the user related HTTP request body for creation of a new user.
 type CreateUserRequest struct {
-	Name string `json:"name" validate:"required"`
+	Name     string `json:"name" validate:"required"`
+	Password string `json:"password" validate:"required"`
 }
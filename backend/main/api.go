@@ -1,34 +1,70 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
+	"github.com/haobuhaoo/gossip-with-go/internal/attachments"
+	"github.com/haobuhaoo/gossip-with-go/internal/audit"
 	"github.com/haobuhaoo/gossip-with-go/internal/auth"
+	"github.com/haobuhaoo/gossip-with-go/internal/bookmarks"
 	"github.com/haobuhaoo/gossip-with-go/internal/comments"
+	"github.com/haobuhaoo/gossip-with-go/internal/health"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+	"github.com/haobuhaoo/gossip-with-go/internal/mentions"
+	"github.com/haobuhaoo/gossip-with-go/internal/notifications"
+	"github.com/haobuhaoo/gossip-with-go/internal/openapi"
 	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
 	"github.com/haobuhaoo/gossip-with-go/internal/posts"
+	"github.com/haobuhaoo/gossip-with-go/internal/purge"
+	"github.com/haobuhaoo/gossip-with-go/internal/ratelimit"
+	"github.com/haobuhaoo/gossip-with-go/internal/render"
+	"github.com/haobuhaoo/gossip-with-go/internal/reports"
+	"github.com/haobuhaoo/gossip-with-go/internal/roles"
+	"github.com/haobuhaoo/gossip-with-go/internal/search"
+	"github.com/haobuhaoo/gossip-with-go/internal/storage"
+	"github.com/haobuhaoo/gossip-with-go/internal/stream"
+	"github.com/haobuhaoo/gossip-with-go/internal/suspensions"
 	"github.com/haobuhaoo/gossip-with-go/internal/topics"
 	"github.com/haobuhaoo/gossip-with-go/internal/users"
 	middleWare "github.com/haobuhaoo/gossip-with-go/middleware"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// application contains the configuration and database connection for the web server.
+// application contains the configuration and database connection for the web server, the
+// background jobs that are started together with it, and the functions that are called when it
+// shuts down.
 type application struct {
-	config config
-	db     *pgxpool.Pool
+	config     config
+	db         *pgxpool.Pool
+	health     health.Service
+	jobs       []func(ctx context.Context)
+	onShutdown []func()
 }
 
-// config contains the server address string and database configurations.
+// config contains the server address string, the database configurations and how the server
+// shuts down.
 type config struct {
-	addr string
-	db   dbConfig
+	addr     string
+	db       dbConfig
+	shutdown shutdownConfig
+}
+
+// shutdownConfig contains how long the server keeps serving after it is no longer ready, so that
+// load balancers stop routing requests to it, and how long it then waits for the requests in
+// flight to finish.
+type shutdownConfig struct {
+	delay   time.Duration
+	timeout time.Duration
 }
 
 // dbConfig contains the connection string for the PostgreSQL database.
@@ -36,7 +72,8 @@ type dbConfig struct {
 	dsn string
 }
 
-// mount sets up the HTTP router, middleware, application routes.
+// mount sets up the HTTP router, middleware, application routes, and registers the background
+// jobs of the services, which are started by run.
 // It returns a chi.Router that can be used by the HTTP server.
 func (app *application) mount() http.Handler {
 	r := chi.NewRouter()
@@ -50,9 +87,11 @@ func (app *application) mount() http.Handler {
 		AllowedOrigins:   []string{frontendURL},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-type"},
+		ExposedHeaders:   []string{"Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
+	r.Use(middleware.RequestID)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 
@@ -63,38 +102,165 @@ func (app *application) mount() http.Handler {
 		log.Fatal("JWT_SECRET_KEY not set")
 	}
 
-	authService := auth.NewService(query)
+	retention := purge.DefaultRetention
+	if retentionStr := os.Getenv("DELETED_RETENTION"); retentionStr != "" {
+		d, err := time.ParseDuration(retentionStr)
+		if err != nil || d < helper.RestoreWindow {
+			log.Fatalf("DELETED_RETENTION must be a duration of at least %s", helper.RestoreWindow)
+		}
+		retention = d
+	}
+
+	storageConfig := storage.Config{
+		Driver:      os.Getenv("STORAGE_DRIVER"),
+		Dir:         os.Getenv("STORAGE_DIR"),
+		S3Endpoint:  os.Getenv("S3_ENDPOINT"),
+		S3Region:    os.Getenv("S3_REGION"),
+		S3Bucket:    os.Getenv("S3_BUCKET"),
+		S3AccessKey: os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey: os.Getenv("S3_SECRET_KEY"),
+		S3UseSSL:    os.Getenv("S3_USE_SSL") == "true",
+	}
+	if storageConfig.Driver == "" {
+		storageConfig.Driver = storage.DriverLocal
+	}
+	if storageConfig.Dir == "" {
+		storageConfig.Dir = "uploads"
+	}
+
+	fileStorage, err := storage.New(context.Background(), storageConfig)
+	if err != nil {
+		log.Fatalf("failed to set up %s storage: %v", storageConfig.Driver, err)
+	}
+
+	purgeService := purge.NewService(query, fileStorage, retention)
+	app.jobs = append(app.jobs, purgeService.Run)
+
+	rateLimitService := ratelimit.NewService(query)
+	app.jobs = append(app.jobs, rateLimitService.Run)
+	trustedProxies, err := middleWare.ParseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		log.Fatalf("TRUSTED_PROXIES must be a comma separated list of IP addresses or CIDR ranges: %v", err)
+	}
+	limitByIP := middleWare.RateLimitByIP(rateLimitService, ipRateLimits, trustedProxies)
+
+	auditService := audit.NewService(query)
+	auditHandler := audit.NewHandler(auditService)
+
+	roleService := roles.NewService(query, app.db, auditService)
+	roleHandler := roles.NewHandler(roleService)
+
+	suspensionService := suspensions.NewService(query, app.db, roleService, auditService)
+	suspensionHandler := suspensions.NewHandler(suspensionService)
+
+	authService := auth.NewService(query, app.db)
+	app.jobs = append(app.jobs, authService.Run)
 	authHandler := auth.NewHandler(authService, jwtSecret)
-	auth.Routes(r, authHandler)
+	authenticate := middleWare.JWTAuth(jwtSecret, authService, suspensionService)
+	auth.Routes(r.With(limitByIP), authHandler, authenticate)
+
+	healthService := health.NewService(app.db)
+	healthHandler := health.NewHandler(healthService)
+	health.Routes(r, healthHandler)
+	app.health = healthService
+
+	openapiHandler := openapi.NewHandler()
+	openapi.Routes(r, openapiHandler)
 
 	userService := users.NewService(query)
 	userHandler := users.NewHandler(userService)
-	users.Routes(r, userHandler)
+	users.Routes(r.With(limitByIP), userHandler)
 
 	r.Route("/api", func(r chi.Router) {
-		r.Use(middleWare.JWTAuth(jwtSecret))
+		r.Use(authenticate)
+		r.Use(middleWare.RateLimitByUser(rateLimitService, userRateLimits))
+
+		auth.MeRoutes(r, authHandler)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleWare.RequirePasswordChanged)
 
-		r.Get("/me", authHandler.AuthenticateUser)
+			notificationService := notifications.NewService(query, app.db)
+			notificationHandler := notifications.NewHandler(notificationService)
+			notifications.Routes(r, notificationHandler)
 
-		topicService := topics.NewService(query)
-		topicHandler := topics.NewHandler(topicService)
-		topics.Routes(r, topicHandler)
+			streamService := stream.NewService(query, app.db)
+			streamHandler := stream.NewHandler(streamService)
+			stream.Routes(r, streamHandler)
+			app.jobs = append(app.jobs, streamService.Listen)
+			app.onShutdown = append(app.onShutdown, streamService.Close)
 
-		postService := posts.NewService(query)
-		postHandler := posts.NewHandler(postService)
-		posts.Routes(r, postHandler)
+			topicService := topics.NewService(query, app.db, roleService, auditService, fileStorage)
+			topicHandler := topics.NewHandler(topicService)
+			topics.Routes(r, topicHandler)
 
-		commentService := comments.NewService(query, app.db)
-		commentHandler := comments.NewHandler(commentService)
-		comments.Routes(r, commentHandler)
+			mentionService := mentions.NewService(query, notificationService)
+			mentionHandler := mentions.NewHandler(mentionService)
+			mentions.Routes(r, mentionHandler)
+
+			postService := posts.NewService(query, app.db, roleService, suspensionService, notificationService, mentionService, streamService, auditService)
+			postHandler := posts.NewHandler(postService)
+			posts.Routes(r, postHandler)
+
+			commentService := comments.NewService(query, app.db, roleService, suspensionService, notificationService, mentionService, streamService, auditService)
+			commentHandler := comments.NewHandler(commentService)
+			comments.Routes(r, commentHandler)
+
+			attachmentService := attachments.NewService(query, fileStorage, suspensionService)
+			attachmentHandler := attachments.NewHandler(attachmentService)
+			attachments.Routes(r, attachmentHandler)
+
+			bookmarkService := bookmarks.NewService(query, mentionService)
+			bookmarkHandler := bookmarks.NewHandler(bookmarkService)
+			bookmarks.Routes(r, bookmarkHandler)
+
+			reportService := reports.NewService(query, app.db, roleService, auditService)
+			reportHandler := reports.NewHandler(reportService)
+			reports.Routes(r, reportHandler)
+			suspensions.Routes(r, suspensionHandler)
+
+			searchService := search.NewService(query)
+			searchHandler := search.NewHandler(searchService)
+			search.Routes(r, searchHandler)
+
+			renderService := render.NewService(mentionService)
+			renderHandler := render.NewHandler(renderService)
+			render.Routes(r, renderHandler)
+
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(middleWare.RequireRole(roles.Admin))
+
+				auth.AdminRoutes(r, authHandler)
+				roles.AdminRoutes(r, roleHandler)
+				audit.AdminRoutes(r, auditHandler)
+			})
+		})
 	})
 
 	return r
 }
 
-// run starts the HTTP server with the given handler.
-// It sets read, write, and idle timeouts and blocks until the server stops or an error occurs.
+// run starts the background jobs and the HTTP server with the given handler.
+// It sets read, write, and idle timeouts and blocks until the server fails or receives SIGINT or
+// SIGTERM. It then marks the server as not ready, keeps serving for the shutdown delay, and shuts
+// the server down, waiting up to the shutdown timeout for the requests in flight to finish, before
+// it stops the background jobs and waits for them to return.
 func (app *application) run(h http.Handler) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	var jobs sync.WaitGroup
+	for _, job := range app.jobs {
+		jobs.Go(func() {
+			job(jobCtx)
+		})
+	}
+	defer func() {
+		stopJobs()
+		jobs.Wait()
+	}()
+
 	svr := &http.Server{
 		Addr:         app.config.addr,
 		Handler:      h,
@@ -102,8 +268,35 @@ func (app *application) run(h http.Handler) error {
 		ReadTimeout:  time.Second * 10,
 		IdleTimeout:  time.Minute,
 	}
+	for _, f := range app.onShutdown {
+		svr.RegisterOnShutdown(f)
+	}
 
-	log.Printf("Starting server at %s", svr.Addr)
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Printf("Starting server at %s", svr.Addr)
+		serveErr <- svr.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+	stop()
+
+	log.Printf("Shutting down server, draining requests for up to %s", app.config.shutdown.delay+app.config.shutdown.timeout)
+	app.health.Drain()
+	time.Sleep(app.config.shutdown.delay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), app.config.shutdown.timeout)
+	defer cancel()
+
+	err := svr.Shutdown(shutdownCtx)
+	if err != nil {
+		return err
+	}
 
-	return svr.ListenAndServe()
+	log.Printf("Server stopped")
+	return nil
 }
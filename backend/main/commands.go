@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/haobuhaoo/gossip-with-go/internal/auth"
+	repo "github.com/haobuhaoo/gossip-with-go/internal/postgresql/sqlc"
+	"github.com/haobuhaoo/gossip-with-go/internal/roles"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// runCommand runs one of the administration commands that are given as arguments to the server
+// instead of starting it, e.g. `go run ./main reset-password cvwo`. They are run by an operator
+// with access to the database, so that no account has to be created with a known password and no
+// role has to be granted by a migration.
+func runCommand(ctx context.Context, db *pgxpool.Pool, args []string) error {
+	query := repo.New(db)
+
+	switch args[0] {
+	case "reset-password":
+		if len(args) != 2 {
+			return errors.New("usage: reset-password <username>")
+		}
+
+		authService := auth.NewService(query, db)
+		password, err := authService.ResetPassword(ctx, args[1])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Temporary password of %s: %s\n", args[1], password)
+		fmt.Println("It must be changed with PUT /api/me/password after logging in.")
+		return nil
+	case "grant-admin":
+		if len(args) != 2 {
+			return errors.New("usage: grant-admin <username>")
+		}
+
+		user, err := query.FindUserByName(ctx, args[1])
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return fmt.Errorf("user %s not found", args[1])
+			}
+			return err
+		}
+
+		arg := repo.UpdateUserRoleParams{
+			UserID: user.UserID,
+			Role:   roles.Admin,
+		}
+		_, err = query.UpdateUserRole(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s is now an admin. The role applies from the next login.\n", args[1])
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
 	"time"
@@ -22,16 +23,32 @@ func main() {
 		port = "3000"
 	}
 
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	slog.SetDefault(logger)
+
+	shutdownDelay, err := durationEnv("SHUTDOWN_DELAY", 5*time.Second)
+	if err != nil {
+		slog.Error("Invalid SHUTDOWN_DELAY", "error", err)
+		os.Exit(1)
+	}
+
+	shutdownTimeout, err := durationEnv("SHUTDOWN_TIMEOUT", 30*time.Second)
+	if err != nil {
+		slog.Error("Invalid SHUTDOWN_TIMEOUT", "error", err)
+		os.Exit(1)
+	}
+
 	cfg := config{
 		addr: ":" + port,
 		db: dbConfig{
 			dsn: os.Getenv("GOOSE_DBSTRING"),
 		},
+		shutdown: shutdownConfig{
+			delay:   shutdownDelay,
+			timeout: shutdownTimeout,
+		},
 	}
 
-	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
-	slog.SetDefault(logger)
-
 	poolConfig, err := pgxpool.ParseConfig(cfg.db.dsn)
 	if err != nil {
 		slog.Error("Invalid DB DSN", "error", err)
@@ -48,17 +65,45 @@ func main() {
 		slog.Error("Failed to connect to database", "error", err)
 		os.Exit(1)
 	}
-	defer pool.Close()
 
 	slog.Info("Connected to database")
 
+	if len(os.Args) > 1 {
+		err := runCommand(ctx, pool, os.Args[1:])
+		pool.Close()
+		if err != nil {
+			slog.Error("Command failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	api := application{
 		config: cfg,
 		db:     pool,
 	}
 
-	if err := api.run(api.mount()); err != nil {
-		slog.Error("Failed to start server", "error", err)
+	err = api.run(api.mount())
+	pool.Close()
+	if err != nil {
+		slog.Error("Server stopped with an error", "error", err)
 		os.Exit(1)
 	}
 }
+
+// durationEnv returns the duration in the environment variable, or the fallback if it is not set.
+func durationEnv(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("%s must not be negative", key)
+	}
+	return d, nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/haobuhaoo/gossip-with-go/internal/openapi"
+)
+
+// schemaRefRegex matches the references to the named schemas of the OpenAPI document.
+var schemaRefRegex = regexp.MustCompile(`"#/components/schemas/([^"]+)"`)
+
+// newTestRouter mounts the routes of the application without a database. The background jobs are
+// registered but not started.
+func newTestRouter(t *testing.T) chi.Routes {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	t.Setenv("STORAGE_DRIVER", "local")
+	t.Setenv("STORAGE_DIR", t.TempDir())
+
+	app := &application{}
+	router, ok := app.mount().(chi.Routes)
+	if !ok {
+		t.Fatal("mount did not return a chi router")
+	}
+	return router
+}
+
+// routeKey returns the method and the route pattern without a trailing slash, which is how the
+// paths of the OpenAPI document are written.
+func routeKey(method string, route string) string {
+	route = strings.TrimSuffix(route, "/")
+	if route == "" {
+		route = "/"
+	}
+	return strings.ToUpper(method) + " " + route
+}
+
+func TestOpenAPICoversAllRoutes(t *testing.T) {
+	router := newTestRouter(t)
+
+	spec := openapi.Spec()
+	documented := map[string]bool{}
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			documented[routeKey(method, path)] = true
+		}
+	}
+
+	registered := map[string]bool{}
+	err := chi.Walk(router, func(method string, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		key := routeKey(method, route)
+		registered[key] = true
+		if !documented[key] {
+			t.Errorf("route %s is missing from the OpenAPI document", key)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk the routes: %v", err)
+	}
+
+	for key := range documented {
+		if !registered[key] {
+			t.Errorf("OpenAPI document has %s, which is not a registered route", key)
+		}
+	}
+}
+
+func TestOpenAPIDocumentIsServed(t *testing.T) {
+	router := newTestRouter(t)
+
+	w := httptest.NewRecorder()
+	router.(http.Handler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, openapi.Path, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET %s returned %d", openapi.Path, w.Code)
+	}
+
+	var doc openapi.Document
+	err := json.Unmarshal(w.Body.Bytes(), &doc)
+	if err != nil {
+		t.Fatalf("failed to decode the OpenAPI document: %v", err)
+	}
+	if doc.OpenAPI != openapi.Version {
+		t.Errorf("openapi = %q, want %q", doc.OpenAPI, openapi.Version)
+	}
+
+	for path, operations := range doc.Paths {
+		for method, op := range operations {
+			if _, ok := op.Responses["200"]; !ok {
+				t.Errorf("%s %s has no successful response", method, path)
+			}
+		}
+	}
+
+	for _, match := range schemaRefRegex.FindAllStringSubmatch(w.Body.String(), -1) {
+		if _, ok := doc.Components.Schemas[match[1]]; !ok {
+			t.Errorf("schema %s is referred to but not defined", match[1])
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"time"
+
+	middleWare "github.com/haobuhaoo/gossip-with-go/middleware"
+)
+
+// votePolicy is shared by every kind of vote, so that flipping votes in a loop drains a single
+// bucket whichever route is used.
+var votePolicy = middleWare.RatePolicy{Name: "vote", Burst: 30, Interval: 2 * time.Second}
+
+// userRateLimits holds the rate limits of the logged in users on the /api routes. Creating content
+// and voting are limited more tightly than the rest of the API.
+var userRateLimits = middleWare.RateLimits{
+	Default: middleWare.RatePolicy{Name: "api", Burst: 120, Interval: 500 * time.Millisecond},
+	Routes: map[string]middleWare.RatePolicy{
+		"POST /api/posts":                            {Name: "post", Burst: 5, Interval: time.Minute},
+		"POST /api/comments":                         {Name: "comment", Burst: 10, Interval: 15 * time.Second},
+		"PUT /api/posts/{id}":                        {Name: "edit", Burst: 20, Interval: 15 * time.Second},
+		"PUT /api/comments/{id}":                     {Name: "edit", Burst: 20, Interval: 15 * time.Second},
+		"POST /api/attachments/posts/{postId}":       {Name: "upload", Burst: 10, Interval: 30 * time.Second},
+		"POST /api/attachments/comments/{commentId}": {Name: "upload", Burst: 10, Interval: 30 * time.Second},
+		"POST /api/reports":                          {Name: "report", Burst: 5, Interval: time.Minute},
+		"POST /api/posts/{id}/likes":                 votePolicy,
+		"POST /api/posts/{id}/dislikes":              votePolicy,
+		"DELETE /api/posts/{id}/remove":              votePolicy,
+		"POST /api/posts/{id}/poll/vote":             votePolicy,
+		"POST /api/comments/{id}/likes":              votePolicy,
+		"POST /api/comments/{id}/dislikes":           votePolicy,
+		"DELETE /api/comments/{id}/remove":           votePolicy,
+	},
+}
+
+// ipRateLimits holds the rate limits of each client IP address on the /auth and /users routes,
+// which are used before logging in. Logging in and registering are limited more tightly to slow
+// down password guessing and mass sign ups.
+var ipRateLimits = middleWare.RateLimits{
+	Default: middleWare.RatePolicy{Name: "public", Burst: 60, Interval: time.Second},
+	Routes: map[string]middleWare.RatePolicy{
+		"POST /auth/login": {Name: "login", Burst: 10, Interval: 30 * time.Second},
+		"POST /users":      {Name: "register", Burst: 3, Interval: 20 * time.Minute},
+	},
+}
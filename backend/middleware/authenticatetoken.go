@@ -4,47 +4,141 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
 )
 
+// RevocationChecker reports whether an access token has been revoked before it expired.
+type RevocationChecker interface {
+	IsTokenRevoked(ctx context.Context, jti string, userId int64, issuedAt time.Time) (bool, error)
+}
+
+// SuspensionChecker returns a *helper.SuspendedError if a user is suspended or banned from the
+// whole forum.
+type SuspensionChecker interface {
+	CheckGlobal(ctx context.Context, userId int64) error
+}
+
 // JWTAuth reads the Authorization Header which expects a Bearer token, validates it using the
-// `secret` string. It extracts `user_id` from the token and stores it in the request context
-// that is passed to the next handler.
-func JWTAuth(secret string) func(http.Handler) http.Handler {
+// `secret` string and rejects it if the `checker` reports that its `jti` has been revoked, or if
+// the `suspensions` checker reports that the user is suspended from the whole forum. It extracts
+// `user_id`, `role`, `jti`, `exp` and `must_change_password` from the token and stores them in the
+// request context that is passed to the next handler.
+func JWTAuth(secret string, checker RevocationChecker, suspensions SuspensionChecker) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
 			parts := strings.SplitN(authHeader, " ", 2)
 			if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-				http.Error(w, "Invalid Authorization Header", http.StatusUnauthorized)
+				helper.WriteError(w, "Invalid Authorization Header", http.StatusUnauthorized, api.CodeUnauthenticated)
 				return
 			}
 
 			tokenStr := parts[1]
 			token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
 				return []byte(secret), nil
-			})
+			}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuedAt())
 			if err != nil || !token.Valid {
-				http.Error(w, "Invalid token", http.StatusUnauthorized)
+				helper.WriteError(w, "Invalid token", http.StatusUnauthorized, api.CodeInvalidToken)
 				return
 			}
 
 			claims, ok := token.Claims.(jwt.MapClaims)
 			if !ok {
-				http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+				helper.WriteError(w, "Invalid token claims", http.StatusUnauthorized, api.CodeInvalidToken)
 				return
 			}
 
 			uidFloat, ok := claims["user_id"].(float64)
 			if !ok {
-				http.Error(w, "Invalid token user_id", http.StatusUnauthorized)
+				helper.WriteError(w, "Invalid token user_id", http.StatusUnauthorized, api.CodeInvalidToken)
+				return
+			}
+
+			jti, ok := claims["jti"].(string)
+			if !ok || jti == "" {
+				helper.WriteError(w, "Invalid token jti", http.StatusUnauthorized, api.CodeInvalidToken)
+				return
+			}
+
+			issuedAt, err := claims.GetIssuedAt()
+			if err != nil || issuedAt == nil {
+				helper.WriteError(w, "Invalid token iat", http.StatusUnauthorized, api.CodeInvalidToken)
+				return
+			}
+
+			expiresAt, err := claims.GetExpirationTime()
+			if err != nil || expiresAt == nil {
+				helper.WriteError(w, "Invalid token exp", http.StatusUnauthorized, api.CodeInvalidToken)
 				return
 			}
 
 			userId := int64(uidFloat)
+			revoked, err := checker.IsTokenRevoked(r.Context(), jti, userId, issuedAt.Time)
+			if err != nil {
+				helper.WriteInternalError(w, r, err)
+				return
+			}
+			if revoked {
+				helper.WriteError(w, "Token revoked", http.StatusUnauthorized, api.CodeTokenRevoked)
+				return
+			}
+
+			err = suspensions.CheckGlobal(r.Context(), userId)
+			if err != nil {
+				if suspended, ok := helper.AsSuspendedError(err); ok {
+					helper.WriteSuspendedError(w, suspended)
+					return
+				}
+
+				helper.WriteInternalError(w, r, err)
+				return
+			}
+
+			role, _ := claims["role"].(string)
+			mustChangePassword, _ := claims["must_change_password"].(bool)
+
 			ctx := context.WithValue(r.Context(), "userID", userId)
+			ctx = context.WithValue(ctx, "userRole", role)
+			ctx = context.WithValue(ctx, "tokenID", jti)
+			ctx = context.WithValue(ctx, "tokenExpiresAt", expiresAt.Time)
+			ctx = context.WithValue(ctx, "mustChangePassword", mustChangePassword)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// RequirePasswordChanged rejects requests from users whose token was issued while they still had
+// to change their password. It must be used after JWTAuth.
+func RequirePasswordChanged(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mustChangePassword, _ := r.Context().Value("mustChangePassword").(bool)
+		if mustChangePassword {
+			helper.WriteError(w, "Password change required", http.StatusForbidden, api.CodePasswordChangeRequired)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireRole rejects requests from users whose token does not carry one of the given roles. It
+// must be used after JWTAuth.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, _ := r.Context().Value("userRole").(string)
+			for _, allowed := range roles {
+				if role == allowed {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			helper.WriteError(w, "Insufficient role", http.StatusForbidden, api.CodeInsufficientRole)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/haobuhaoo/gossip-with-go/internal/api"
+	"github.com/haobuhaoo/gossip-with-go/internal/helper"
+)
+
+// RateLimiter takes a token from the bucket given by key, which holds up to burst tokens and gains
+// a token every interval. It returns 0 if a token was taken, or how long to wait for the next token
+// if the bucket is empty.
+type RateLimiter interface {
+	Take(ctx context.Context, key string, burst int32, interval time.Duration) (time.Duration, error)
+}
+
+// RatePolicy allows a burst of up to Burst requests, and one more request every Interval after
+// that. Routes whose policies share a Name also share their bucket.
+type RatePolicy struct {
+	Name     string
+	Burst    int32
+	Interval time.Duration
+}
+
+// RateLimits holds the policies of the routes, keyed by the method and the route pattern of the
+// request without a trailing slash, e.g. "POST /api/posts/{id}/likes". Default applies to the routes
+// without a policy.
+type RateLimits struct {
+	Default RatePolicy
+	Routes  map[string]RatePolicy
+}
+
+// RateLimitByUser limits the requests of each user according to the policy of the requested route.
+// Requests over the limit are rejected with 429 Too Many Requests and a Retry-After header. It must
+// be used after JWTAuth.
+func RateLimitByUser(limiter RateLimiter, limits RateLimits) func(http.Handler) http.Handler {
+	return rateLimit(limiter, limits, func(r *http.Request) string {
+		userId, _ := r.Context().Value("userID").(int64)
+		return fmt.Sprintf("user:%d", userId)
+	})
+}
+
+// RateLimitByIP limits the requests of each client IP address according to the policy of the
+// requested route, for routes that are used before logging in. Requests over the limit are
+// rejected with 429 Too Many Requests and a Retry-After header. The client address is taken from
+// X-Forwarded-For only when the request comes from one of the trusted proxies.
+func RateLimitByIP(limiter RateLimiter, limits RateLimits, trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return rateLimit(limiter, limits, func(r *http.Request) string {
+		return "ip:" + clientIP(r, trustedProxies)
+	})
+}
+
+// ParseTrustedProxies parses a comma separated list of IP addresses and CIDR ranges, e.g.
+// "10.0.0.0/8, 192.168.1.10". An empty value trusts no proxy.
+func ParseTrustedProxies(value string) ([]netip.Prefix, error) {
+	proxies := []netip.Prefix{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, err
+			}
+			proxies = append(proxies, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return proxies, nil
+}
+
+// clientIP returns the IP address of the client that sent the request. If the request comes from a
+// trusted proxy, X-Forwarded-For is read from the right, skipping the trusted proxies, so that the
+// first address that was not added by a trusted proxy is used. Addresses further left can be set
+// by the client, so they are never used.
+func clientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if !isTrusted(ip, trustedProxies) {
+		return ip
+	}
+
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			break
+		}
+
+		ip = addr.Unmap().String()
+		if !isTrusted(ip, trustedProxies) {
+			break
+		}
+	}
+	return ip
+}
+
+// isTrusted reports whether ip belongs to one of the trusted proxies.
+func isTrusted(ip string, trustedProxies []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// rateLimit takes a token from the bucket of the route policy and the client given by `client`.
+// Requests are let through if the limiter fails, so that an unavailable store does not take the
+// whole API down.
+func rateLimit(limiter RateLimiter, limits RateLimits, client func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			policy, ok := limits.Routes[r.Method+" "+routePattern(r)]
+			if !ok {
+				policy = limits.Default
+			}
+
+			key := policy.Name + ":" + client(r)
+			wait, err := limiter.Take(r.Context(), key, policy.Burst, policy.Interval)
+			if err != nil {
+				log.Printf("failed to check rate limit %s: %v", key, err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if wait > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				helper.WriteError(w, "Too many requests", http.StatusTooManyRequests, api.CodeRateLimited)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// routePattern returns the pattern of the route that the request is routed to without a trailing
+// slash, or an empty string if no route matches.
+func routePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || rctx.Routes == nil {
+		return ""
+	}
+	pattern := rctx.Routes.Find(chi.NewRouteContext(), r.Method, r.URL.Path)
+	return strings.TrimSuffix(pattern, "/")
+}
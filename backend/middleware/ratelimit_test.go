@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []netip.Prefix
+		wantErr bool
+	}{
+		{name: "empty", value: "", want: []netip.Prefix{}},
+		{name: "only separators", value: " , ,", want: []netip.Prefix{}},
+		{
+			name:  "single address",
+			value: "10.0.0.1",
+			want:  []netip.Prefix{netip.MustParsePrefix("10.0.0.1/32")},
+		},
+		{
+			name:  "cidr and spaces",
+			value: " 10.0.0.0/8 , 192.168.1.10 ",
+			want:  []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.1.10/32")},
+		},
+		{
+			name:  "cidr is masked",
+			value: "172.16.5.4/12",
+			want:  []netip.Prefix{netip.MustParsePrefix("172.16.0.0/12")},
+		},
+		{
+			name:  "ipv6",
+			value: "2001:db8::1, fd00::/8",
+			want:  []netip.Prefix{netip.MustParsePrefix("2001:db8::1/128"), netip.MustParsePrefix("fd00::/8")},
+		},
+		{name: "hostname", value: "proxy.internal", wantErr: true},
+		{name: "invalid address", value: "10.0.0.256", wantErr: true},
+		{name: "invalid cidr", value: "10.0.0.0/33", wantErr: true},
+		{name: "one invalid entry", value: "10.0.0.1, nope", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTrustedProxies(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTrustedProxies(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTrustedProxies(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	trusted, err := ParseTrustedProxies("10.0.0.0/8, 192.168.1.10, fd00::/8")
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies returned an error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{
+			name:       "untrusted remote without header",
+			remoteAddr: "1.2.3.4:5000",
+			want:       "1.2.3.4",
+		},
+		{
+			name:       "untrusted remote ignores header",
+			remoteAddr: "1.2.3.4:5000",
+			forwarded:  []string{"9.9.9.9"},
+			want:       "1.2.3.4",
+		},
+		{
+			name:       "trusted remote without header",
+			remoteAddr: "10.1.1.1:5000",
+			want:       "10.1.1.1",
+		},
+		{
+			name:       "trusted remote with client",
+			remoteAddr: "10.1.1.1:5000",
+			forwarded:  []string{"7.7.7.7"},
+			want:       "7.7.7.7",
+		},
+		{
+			name:       "chain walked from the right",
+			remoteAddr: "10.1.1.1:5000",
+			forwarded:  []string{"7.7.7.7, 192.168.1.10, 10.2.2.2"},
+			want:       "7.7.7.7",
+		},
+		{
+			name:       "spoofed leftmost entry",
+			remoteAddr: "10.1.1.1:5000",
+			forwarded:  []string{"6.6.6.6, 7.7.7.7"},
+			want:       "7.7.7.7",
+		},
+		{
+			name:       "multiple headers",
+			remoteAddr: "10.1.1.1:5000",
+			forwarded:  []string{"6.6.6.6", "7.7.7.7, 10.2.2.2"},
+			want:       "7.7.7.7",
+		},
+		{
+			name:       "every hop trusted",
+			remoteAddr: "10.1.1.1:5000",
+			forwarded:  []string{"192.168.1.10, 10.2.2.2"},
+			want:       "192.168.1.10",
+		},
+		{
+			name:       "invalid entry stops the walk",
+			remoteAddr: "10.1.1.1:5000",
+			forwarded:  []string{"junk, 10.2.2.2"},
+			want:       "10.2.2.2",
+		},
+		{
+			name:       "single address is not a cidr",
+			remoteAddr: "192.168.1.11:5000",
+			forwarded:  []string{"7.7.7.7"},
+			want:       "192.168.1.11",
+		},
+		{
+			name:       "ipv4 mapped remote",
+			remoteAddr: "[::ffff:10.1.1.1]:5000",
+			forwarded:  []string{"8.8.8.8"},
+			want:       "8.8.8.8",
+		},
+		{
+			name:       "ipv6 trusted remote",
+			remoteAddr: "[fd00::1]:5000",
+			forwarded:  []string{"2001:db8::2"},
+			want:       "2001:db8::2",
+		},
+		{
+			name:       "ipv6 untrusted remote",
+			remoteAddr: "[2001:db8::1]:5000",
+			forwarded:  []string{"7.7.7.7"},
+			want:       "2001:db8::1",
+		},
+		{
+			name:       "ipv4 mapped forwarded entry",
+			remoteAddr: "10.1.1.1:5000",
+			forwarded:  []string{"::ffff:7.7.7.7"},
+			want:       "7.7.7.7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+
+			got := clientIP(r, trusted)
+			if got != tt.want {
+				t.Errorf("clientIP(%q, %q) = %q, want %q", tt.remoteAddr, tt.forwarded, got, tt.want)
+			}
+		})
+	}
+}